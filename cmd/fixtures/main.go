@@ -0,0 +1,35 @@
+// Command fixtures loads a set of representative demo submissions into the
+// SQLite database at DB_PATH (default w2c.db). Run migrations first.
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	sqliteadapter "github.com/csg33k/w2c-generator/internal/adapters/sqlite"
+	"github.com/csg33k/w2c-generator/internal/fixtures"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		slog.Warn("error loading .env file", "err", err)
+	}
+	dsn := os.Getenv("DB_PATH")
+	if dsn == "" {
+		dsn = "w2c.db"
+	}
+
+	repo, err := sqliteadapter.New(dsn)
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	n, err := fixtures.Load(context.Background(), repo)
+	if err != nil {
+		log.Fatalf("loading fixtures: %v", err)
+	}
+	log.Printf("Loaded %d fixture submissions into %s", n, dsn)
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -31,9 +33,39 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to open database: %v", err)
 	}
+	if err := repo.EnsureSchema(context.Background()); err != nil {
+		log.Fatalf("database %s: %v", dsn, err)
+	}
+
+	var genOpts []efw2c.Option
+	if os.Getenv("STRICT_PAIRING") == "true" {
+		genOpts = append(genOpts, efw2c.WithStrictPairing())
+	}
+	if os.Getenv("BSOUID_ALLOW_LOWERCASE") == "true" {
+		genOpts = append(genOpts, efw2c.WithLowercaseBSOUID())
+	}
+	if os.Getenv("TRIM_POLICY") == "TRUNCATE" {
+		genOpts = append(genOpts, efw2c.WithTrimPolicy(efw2c.TrimTruncate))
+	}
+	if os.Getenv("RCS_LOCALITY") == "true" {
+		genOpts = append(genOpts, efw2c.WithRCSLocality())
+	}
+	if v := os.Getenv("SANDBOX_MARKER"); v != "" {
+		genOpts = append(genOpts, efw2c.WithSandboxMarker(v))
+	}
+
+	timeout := 60 * time.Second
+	if v := os.Getenv("REQUEST_TIMEOUT"); v != "" {
+		timeout, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid REQUEST_TIMEOUT %q: %v", v, err)
+		}
+	}
 
-	gen := efw2c.MustNew(0) // 0 = use DefaultYear; Generate() resolves per-submission anyway
-	h := handlers.New(repo, gen)
+	// The default year only preselects the form; Generate still resolves
+	// the layout from each submission's own tax year.
+	gen := efw2c.MustNew(efw2c.ParseDefaultYear(os.Getenv("DEFAULT_TAX_YEAR")), genOpts...)
+	h := handlers.New(repo, gen, handlers.WithTimeout(timeout))
 
 	log.Printf("W-2c EFW2C Generator running on http://localhost:%s", port)
 	log.Printf("Database: %s", dsn)
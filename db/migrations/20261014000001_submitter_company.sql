@@ -0,0 +1,7 @@
+-- migrate:up
+
+-- RCA submitter company — blank means "same as employer" (self-prepared)
+ALTER TABLE submissions ADD COLUMN submitter_company_name TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+ALTER TABLE submissions DROP COLUMN submitter_company_name;
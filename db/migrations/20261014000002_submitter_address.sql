@@ -0,0 +1,17 @@
+-- migrate:up
+
+-- RCA submitter address — all blank means "same as employer"
+ALTER TABLE submissions ADD COLUMN submitter_addr1   TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN submitter_addr2   TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN submitter_city    TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN submitter_state   TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN submitter_zip     TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN submitter_zip_ext TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+ALTER TABLE submissions DROP COLUMN submitter_addr1;
+ALTER TABLE submissions DROP COLUMN submitter_addr2;
+ALTER TABLE submissions DROP COLUMN submitter_city;
+ALTER TABLE submissions DROP COLUMN submitter_state;
+ALTER TABLE submissions DROP COLUMN submitter_zip;
+ALTER TABLE submissions DROP COLUMN submitter_zip_ext;
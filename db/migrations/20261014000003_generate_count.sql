@@ -0,0 +1,7 @@
+-- migrate:up
+
+-- Number of times the EFW2C file has been generated/downloaded
+ALTER TABLE submissions ADD COLUMN generate_count INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+ALTER TABLE submissions DROP COLUMN generate_count;
@@ -0,0 +1,33 @@
+-- migrate:up
+
+-- Box 12 codes with RCW and RCT positions not previously stored
+ALTER TABLE employees ADD COLUMN orig_code_c INTEGER NOT NULL DEFAULT 0; -- Code C
+ALTER TABLE employees ADD COLUMN corr_code_c INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_f INTEGER NOT NULL DEFAULT 0; -- Code F
+ALTER TABLE employees ADD COLUMN corr_code_f INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_h INTEGER NOT NULL DEFAULT 0; -- Code H
+ALTER TABLE employees ADD COLUMN corr_code_h INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_q INTEGER NOT NULL DEFAULT 0; -- Code Q
+ALTER TABLE employees ADD COLUMN corr_code_q INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_v INTEGER NOT NULL DEFAULT 0; -- Code V
+ALTER TABLE employees ADD COLUMN corr_code_v INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_y INTEGER NOT NULL DEFAULT 0; -- Code Y
+ALTER TABLE employees ADD COLUMN corr_code_y INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_ff INTEGER NOT NULL DEFAULT 0; -- Code FF
+ALTER TABLE employees ADD COLUMN corr_code_ff INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+ALTER TABLE employees DROP COLUMN orig_code_c;
+ALTER TABLE employees DROP COLUMN corr_code_c;
+ALTER TABLE employees DROP COLUMN orig_code_f;
+ALTER TABLE employees DROP COLUMN corr_code_f;
+ALTER TABLE employees DROP COLUMN orig_code_h;
+ALTER TABLE employees DROP COLUMN corr_code_h;
+ALTER TABLE employees DROP COLUMN orig_code_q;
+ALTER TABLE employees DROP COLUMN corr_code_q;
+ALTER TABLE employees DROP COLUMN orig_code_v;
+ALTER TABLE employees DROP COLUMN corr_code_v;
+ALTER TABLE employees DROP COLUMN orig_code_y;
+ALTER TABLE employees DROP COLUMN corr_code_y;
+ALTER TABLE employees DROP COLUMN orig_code_ff;
+ALTER TABLE employees DROP COLUMN corr_code_ff;
@@ -0,0 +1,9 @@
+-- migrate:up
+
+-- Box 12 Codes A & B — uncollected SS/RRTA and Medicare tax on tips (RCO record)
+ALTER TABLE employees ADD COLUMN orig_uncoll_tips_tax INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN corr_uncoll_tips_tax INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+ALTER TABLE employees DROP COLUMN orig_uncoll_tips_tax;
+ALTER TABLE employees DROP COLUMN corr_uncoll_tips_tax;
@@ -0,0 +1,29 @@
+-- migrate:up
+
+-- Box 12 codes written to the RCO record
+ALTER TABLE employees ADD COLUMN orig_code_r INTEGER NOT NULL DEFAULT 0; -- Code R
+ALTER TABLE employees ADD COLUMN corr_code_r INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_s INTEGER NOT NULL DEFAULT 0; -- Code S
+ALTER TABLE employees ADD COLUMN corr_code_s INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_t INTEGER NOT NULL DEFAULT 0; -- Code T
+ALTER TABLE employees ADD COLUMN corr_code_t INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_m INTEGER NOT NULL DEFAULT 0; -- Code M
+ALTER TABLE employees ADD COLUMN corr_code_m INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_n INTEGER NOT NULL DEFAULT 0; -- Code N
+ALTER TABLE employees ADD COLUMN corr_code_n INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN orig_code_z INTEGER NOT NULL DEFAULT 0; -- Code Z
+ALTER TABLE employees ADD COLUMN corr_code_z INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+ALTER TABLE employees DROP COLUMN orig_code_r;
+ALTER TABLE employees DROP COLUMN corr_code_r;
+ALTER TABLE employees DROP COLUMN orig_code_s;
+ALTER TABLE employees DROP COLUMN corr_code_s;
+ALTER TABLE employees DROP COLUMN orig_code_t;
+ALTER TABLE employees DROP COLUMN corr_code_t;
+ALTER TABLE employees DROP COLUMN orig_code_m;
+ALTER TABLE employees DROP COLUMN corr_code_m;
+ALTER TABLE employees DROP COLUMN orig_code_n;
+ALTER TABLE employees DROP COLUMN corr_code_n;
+ALTER TABLE employees DROP COLUMN orig_code_z;
+ALTER TABLE employees DROP COLUMN corr_code_z;
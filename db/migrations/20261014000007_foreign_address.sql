@@ -0,0 +1,18 @@
+-- migrate:up
+
+-- Foreign addresses (RCE/RCW CountryCode, ForeignStateProvince, ForeignPostalCode)
+ALTER TABLE submissions ADD COLUMN country_code           TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN foreign_state_province TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN foreign_postal_code    TEXT NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN country_code           TEXT NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN foreign_state_province TEXT NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN foreign_postal_code    TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE employees DROP COLUMN foreign_postal_code;
+ALTER TABLE employees DROP COLUMN foreign_state_province;
+ALTER TABLE employees DROP COLUMN country_code;
+ALTER TABLE submissions DROP COLUMN foreign_postal_code;
+ALTER TABLE submissions DROP COLUMN foreign_state_province;
+ALTER TABLE submissions DROP COLUMN country_code;
@@ -0,0 +1,10 @@
+-- migrate:up
+
+-- RCE third-party sick pay indicators: '' (not corrected), '0' or '1'
+ALTER TABLE submissions ADD COLUMN orig_third_party_sick TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN corr_third_party_sick TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE submissions DROP COLUMN corr_third_party_sick;
+ALTER TABLE submissions DROP COLUMN orig_third_party_sick;
@@ -0,0 +1,11 @@
+-- migrate:up
+
+-- RCA resubmission: indicator '' / '0' (original) or '1', and the WFID from
+-- SSA's rejection notice
+ALTER TABLE submissions ADD COLUMN resub_indicator TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN resub_wfid      TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE submissions DROP COLUMN resub_wfid;
+ALTER TABLE submissions DROP COLUMN resub_indicator;
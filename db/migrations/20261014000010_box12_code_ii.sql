@@ -0,0 +1,10 @@
+-- migrate:up
+
+-- Box 12 Code II (Medicaid waiver payments), RCO positions 277-298 from TY2024
+ALTER TABLE employees ADD COLUMN orig_code_ii INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE employees ADD COLUMN corr_code_ii INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+
+ALTER TABLE employees DROP COLUMN corr_code_ii;
+ALTER TABLE employees DROP COLUMN orig_code_ii;
@@ -0,0 +1,10 @@
+-- migrate:up
+
+-- RCA Software Code (98 in-house, 99 off-the-shelf) and NACTP vendor code
+ALTER TABLE submissions ADD COLUMN software_code        TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN software_vendor_code TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE submissions DROP COLUMN software_vendor_code;
+ALTER TABLE submissions DROP COLUMN software_code;
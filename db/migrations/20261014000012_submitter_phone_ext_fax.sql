@@ -0,0 +1,10 @@
+-- migrate:up
+
+-- RCA contact phone extension (254-258) and fax number (305-314)
+ALTER TABLE submissions ADD COLUMN phone_extension TEXT NOT NULL DEFAULT '';
+ALTER TABLE submissions ADD COLUMN contact_fax     TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE submissions DROP COLUMN contact_fax;
+ALTER TABLE submissions DROP COLUMN phone_extension;
@@ -0,0 +1,8 @@
+-- migrate:up
+
+-- RCE employer contact phone extension (270-274)
+ALTER TABLE submissions ADD COLUMN employer_contact_phone_ext TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE submissions DROP COLUMN employer_contact_phone_ext;
@@ -15,7 +15,7 @@ CREATE TABLE submissions (
                                            notes            TEXT    NOT NULL DEFAULT '',
                                            created_at       DATETIME NOT NULL,
                                            submitted_at     DATETIME
-, bso_uid          TEXT NOT NULL DEFAULT '', contact_name     TEXT NOT NULL DEFAULT '', contact_phone    TEXT NOT NULL DEFAULT '', contact_email    TEXT NOT NULL DEFAULT '', preparer_code    TEXT NOT NULL DEFAULT 'L', kind_of_employer       TEXT NOT NULL DEFAULT 'N', employer_contact_name  TEXT NOT NULL DEFAULT '', employer_contact_phone TEXT NOT NULL DEFAULT '', employer_contact_email TEXT NOT NULL DEFAULT '', employment_code        TEXT NOT NULL DEFAULT 'R', tax_year TEXT NOT NULL DEFAULT '2021', orig_ein TEXT NOT NULL DEFAULT '');
+, bso_uid          TEXT NOT NULL DEFAULT '', contact_name     TEXT NOT NULL DEFAULT '', contact_phone    TEXT NOT NULL DEFAULT '', contact_email    TEXT NOT NULL DEFAULT '', preparer_code    TEXT NOT NULL DEFAULT 'L', kind_of_employer       TEXT NOT NULL DEFAULT 'N', employer_contact_name  TEXT NOT NULL DEFAULT '', employer_contact_phone TEXT NOT NULL DEFAULT '', employer_contact_email TEXT NOT NULL DEFAULT '', employment_code        TEXT NOT NULL DEFAULT 'R', tax_year TEXT NOT NULL DEFAULT '2021', orig_ein TEXT NOT NULL DEFAULT '', submitter_company_name TEXT NOT NULL DEFAULT '', submitter_addr1   TEXT NOT NULL DEFAULT '', submitter_addr2   TEXT NOT NULL DEFAULT '', submitter_city    TEXT NOT NULL DEFAULT '', submitter_state   TEXT NOT NULL DEFAULT '', submitter_zip     TEXT NOT NULL DEFAULT '', submitter_zip_ext TEXT NOT NULL DEFAULT '', generate_count INTEGER NOT NULL DEFAULT 0, country_code           TEXT NOT NULL DEFAULT '', foreign_state_province TEXT NOT NULL DEFAULT '', foreign_postal_code    TEXT NOT NULL DEFAULT '', orig_third_party_sick TEXT NOT NULL DEFAULT '', corr_third_party_sick TEXT NOT NULL DEFAULT '', resub_indicator TEXT NOT NULL DEFAULT '', resub_wfid      TEXT NOT NULL DEFAULT '', software_code        TEXT NOT NULL DEFAULT '', software_vendor_code TEXT NOT NULL DEFAULT '', phone_extension TEXT NOT NULL DEFAULT '', contact_fax     TEXT NOT NULL DEFAULT '', employer_contact_phone_ext TEXT NOT NULL DEFAULT '');
 CREATE TABLE employees (
                                          id             INTEGER PRIMARY KEY AUTOINCREMENT,
                                          submission_id  INTEGER NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
@@ -45,11 +45,24 @@ CREATE TABLE employees (
                                          corr_med_tax   INTEGER NOT NULL DEFAULT 0,
                                          created_at     DATETIME NOT NULL,
                                          updated_at     DATETIME NOT NULL
-, orig_ss_tips INTEGER NOT NULL DEFAULT 0, corr_ss_tips INTEGER NOT NULL DEFAULT 0, orig_state_code TEXT NOT NULL DEFAULT '', corr_state_code TEXT NOT NULL DEFAULT '', orig_state_id   TEXT NOT NULL DEFAULT '', corr_state_id   TEXT NOT NULL DEFAULT '', orig_state_wages INTEGER NOT NULL DEFAULT 0, corr_state_wages INTEGER NOT NULL DEFAULT 0, orig_state_tax INTEGER NOT NULL DEFAULT 0, corr_state_tax INTEGER NOT NULL DEFAULT 0, orig_local_wages INTEGER NOT NULL DEFAULT 0, corr_local_wages INTEGER NOT NULL DEFAULT 0, orig_local_tax INTEGER NOT NULL DEFAULT 0, corr_local_tax INTEGER NOT NULL DEFAULT 0, orig_locality_name TEXT NOT NULL DEFAULT '', corr_locality_name TEXT NOT NULL DEFAULT '', orig_first_name  TEXT NOT NULL DEFAULT '', orig_middle_name TEXT NOT NULL DEFAULT '', orig_last_name   TEXT NOT NULL DEFAULT '', orig_suffix       TEXT NOT NULL DEFAULT '', orig_alloc_tips  INTEGER NOT NULL DEFAULT 0, corr_alloc_tips  INTEGER NOT NULL DEFAULT 0, orig_dep_care    INTEGER NOT NULL DEFAULT 0, corr_dep_care    INTEGER NOT NULL DEFAULT 0, orig_nonqual_457     INTEGER NOT NULL DEFAULT 0, corr_nonqual_457     INTEGER NOT NULL DEFAULT 0, orig_nonqual_not457  INTEGER NOT NULL DEFAULT 0, corr_nonqual_not457  INTEGER NOT NULL DEFAULT 0, orig_code_d       INTEGER NOT NULL DEFAULT 0, corr_code_d       INTEGER NOT NULL DEFAULT 0, orig_code_e       INTEGER NOT NULL DEFAULT 0, corr_code_e       INTEGER NOT NULL DEFAULT 0, orig_code_g       INTEGER NOT NULL DEFAULT 0, corr_code_g       INTEGER NOT NULL DEFAULT 0, orig_code_w       INTEGER NOT NULL DEFAULT 0, corr_code_w       INTEGER NOT NULL DEFAULT 0, orig_code_aa      INTEGER NOT NULL DEFAULT 0, corr_code_aa      INTEGER NOT NULL DEFAULT 0, orig_code_bb      INTEGER NOT NULL DEFAULT 0, corr_code_bb      INTEGER NOT NULL DEFAULT 0, orig_code_dd      INTEGER NOT NULL DEFAULT 0, corr_code_dd      INTEGER NOT NULL DEFAULT 0, orig_statutory_emp    INTEGER, corr_statutory_emp    INTEGER, orig_retirement_plan  INTEGER, corr_retirement_plan  INTEGER, orig_third_party_sick INTEGER, corr_third_party_sick INTEGER);
+, orig_ss_tips INTEGER NOT NULL DEFAULT 0, corr_ss_tips INTEGER NOT NULL DEFAULT 0, orig_state_code TEXT NOT NULL DEFAULT '', corr_state_code TEXT NOT NULL DEFAULT '', orig_state_id   TEXT NOT NULL DEFAULT '', corr_state_id   TEXT NOT NULL DEFAULT '', orig_state_wages INTEGER NOT NULL DEFAULT 0, corr_state_wages INTEGER NOT NULL DEFAULT 0, orig_state_tax INTEGER NOT NULL DEFAULT 0, corr_state_tax INTEGER NOT NULL DEFAULT 0, orig_local_wages INTEGER NOT NULL DEFAULT 0, corr_local_wages INTEGER NOT NULL DEFAULT 0, orig_local_tax INTEGER NOT NULL DEFAULT 0, corr_local_tax INTEGER NOT NULL DEFAULT 0, orig_locality_name TEXT NOT NULL DEFAULT '', corr_locality_name TEXT NOT NULL DEFAULT '', orig_first_name  TEXT NOT NULL DEFAULT '', orig_middle_name TEXT NOT NULL DEFAULT '', orig_last_name   TEXT NOT NULL DEFAULT '', orig_suffix       TEXT NOT NULL DEFAULT '', orig_alloc_tips  INTEGER NOT NULL DEFAULT 0, corr_alloc_tips  INTEGER NOT NULL DEFAULT 0, orig_dep_care    INTEGER NOT NULL DEFAULT 0, corr_dep_care    INTEGER NOT NULL DEFAULT 0, orig_nonqual_457     INTEGER NOT NULL DEFAULT 0, corr_nonqual_457     INTEGER NOT NULL DEFAULT 0, orig_nonqual_not457  INTEGER NOT NULL DEFAULT 0, corr_nonqual_not457  INTEGER NOT NULL DEFAULT 0, orig_code_d       INTEGER NOT NULL DEFAULT 0, corr_code_d       INTEGER NOT NULL DEFAULT 0, orig_code_e       INTEGER NOT NULL DEFAULT 0, corr_code_e       INTEGER NOT NULL DEFAULT 0, orig_code_g       INTEGER NOT NULL DEFAULT 0, corr_code_g       INTEGER NOT NULL DEFAULT 0, orig_code_w       INTEGER NOT NULL DEFAULT 0, corr_code_w       INTEGER NOT NULL DEFAULT 0, orig_code_aa      INTEGER NOT NULL DEFAULT 0, corr_code_aa      INTEGER NOT NULL DEFAULT 0, orig_code_bb      INTEGER NOT NULL DEFAULT 0, corr_code_bb      INTEGER NOT NULL DEFAULT 0, orig_code_dd      INTEGER NOT NULL DEFAULT 0, corr_code_dd      INTEGER NOT NULL DEFAULT 0, orig_statutory_emp    INTEGER, corr_statutory_emp    INTEGER, orig_retirement_plan  INTEGER, corr_retirement_plan  INTEGER, orig_third_party_sick INTEGER, corr_third_party_sick INTEGER, orig_code_c INTEGER NOT NULL DEFAULT 0, corr_code_c INTEGER NOT NULL DEFAULT 0, orig_code_f INTEGER NOT NULL DEFAULT 0, corr_code_f INTEGER NOT NULL DEFAULT 0, orig_code_h INTEGER NOT NULL DEFAULT 0, corr_code_h INTEGER NOT NULL DEFAULT 0, orig_code_q INTEGER NOT NULL DEFAULT 0, corr_code_q INTEGER NOT NULL DEFAULT 0, orig_code_v INTEGER NOT NULL DEFAULT 0, corr_code_v INTEGER NOT NULL DEFAULT 0, orig_code_y INTEGER NOT NULL DEFAULT 0, corr_code_y INTEGER NOT NULL DEFAULT 0, orig_code_ff INTEGER NOT NULL DEFAULT 0, corr_code_ff INTEGER NOT NULL DEFAULT 0, orig_uncoll_tips_tax INTEGER NOT NULL DEFAULT 0, corr_uncoll_tips_tax INTEGER NOT NULL DEFAULT 0, orig_code_r INTEGER NOT NULL DEFAULT 0, corr_code_r INTEGER NOT NULL DEFAULT 0, orig_code_s INTEGER NOT NULL DEFAULT 0, corr_code_s INTEGER NOT NULL DEFAULT 0, orig_code_t INTEGER NOT NULL DEFAULT 0, corr_code_t INTEGER NOT NULL DEFAULT 0, orig_code_m INTEGER NOT NULL DEFAULT 0, corr_code_m INTEGER NOT NULL DEFAULT 0, orig_code_n INTEGER NOT NULL DEFAULT 0, corr_code_n INTEGER NOT NULL DEFAULT 0, orig_code_z INTEGER NOT NULL DEFAULT 0, corr_code_z INTEGER NOT NULL DEFAULT 0, country_code           TEXT NOT NULL DEFAULT '', foreign_state_province TEXT NOT NULL DEFAULT '', foreign_postal_code    TEXT NOT NULL DEFAULT '', orig_code_ii INTEGER NOT NULL DEFAULT 0, corr_code_ii INTEGER NOT NULL DEFAULT 0);
 -- Dbmate schema migrations
 INSERT INTO "schema_migrations" (version) VALUES
   ('20260228000001'),
   ('20260228000002'),
   ('20260301170046'),
   ('20260302000001'),
-  ('20260302000002');
+  ('20260302000002'),
+  ('20261014000001'),
+  ('20261014000002'),
+  ('20261014000003'),
+  ('20261014000004'),
+  ('20261014000005'),
+  ('20261014000006'),
+  ('20261014000007'),
+  ('20261014000008'),
+  ('20261014000009'),
+  ('20261014000010'),
+  ('20261014000011'),
+  ('20261014000012'),
+  ('20261014000013');
@@ -0,0 +1,51 @@
+package efw2c
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// RecordDump is one annotated record; see domain.RecordDump.
+type RecordDump = domain.RecordDump
+
+// Annotate runs (*Generator).Annotate with default options; the layout
+// comes from s's tax year.
+func Annotate(s *domain.Submission) ([]RecordDump, error) {
+	return MustNew(spec.DefaultYear).Annotate(s)
+}
+
+// Annotate generates s and splits every record into the spec.Field
+// segments of its layout, so a rejected position can be read off by field
+// name. It fails wherever Generate would. Satisfies ports.EFW2CGenerator.
+func (g *Generator) Annotate(s *domain.Submission) ([]RecordDump, error) {
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), s, &buf); err != nil {
+		return nil, err
+	}
+	layouts := g.forSubmission(s).yspec.Records()
+	// readRecords drops any terminators added by WithLineEnding.
+	records, err := readRecords(&buf)
+	if err != nil {
+		return nil, err
+	}
+	dumps := make([]RecordDump, len(records))
+	for i, rec := range records {
+		fields, ok := layouts[rec[:3]]
+		if !ok {
+			return nil, fmt.Errorf("efw2c: record %d has unknown identifier %q", i+1, rec[:3])
+		}
+		d := RecordDump{Index: i, ID: rec[:3], Fields: make([]domain.FieldDump, len(fields))}
+		for j, f := range fields {
+			d.Fields[j] = domain.FieldDump{
+				Name: f.Name, Start: f.Start, End: f.End,
+				Type: f.Type.String(), Value: rec[f.Start-1 : f.End],
+			}
+		}
+		dumps[i] = d
+	}
+	return dumps, nil
+}
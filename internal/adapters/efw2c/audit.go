@@ -0,0 +1,268 @@
+package efw2c
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Pre-submission audit
+// ---------------------------------------------------------------------------
+
+// Audit-only finding codes; the rest come from Validate, Check and Generate.
+const (
+	CodeUnsupportedYear    = "unsupported_tax_year"
+	CodeEmploymentMismatch = "employment_code_mismatch"
+	CodeTotalsMismatch     = "totals_mismatch"
+	CodeGenerateFailed     = "generate_failed"
+	CodeDefaultApplied     = "default_applied"
+	CodeRecordCounts       = "record_counts"
+	CodeThirdPartySick     = "third_party_sick_mismatch"
+)
+
+// AuditReport is a pre-submission checklist; see domain.AuditReport.
+type AuditReport = domain.AuditReport
+
+// AuditFinding is one checklist item; see domain.AuditFinding.
+type AuditFinding = domain.AuditFinding
+
+// Audit runs (*Generator).Audit with default options; the layout comes from
+// s's tax year.
+func Audit(s *domain.Submission) *AuditReport {
+	return MustNew(spec.DefaultYear).Audit(s)
+}
+
+// Audit collects everything AccuWage Online would flag in s into one
+// report, so it can be fixed before upload: Validate's identifier errors,
+// the blocking problems Generate checks for, the RCT totals of the file it
+// would write against the sum of its RCWs, employment-code conflicts, an
+// RCE third-party sick pay indicator at odds with the employees' Box 13,
+// and Check's reconciliation warnings. Findings are ordered errors, warnings,
+// info. Satisfies ports.EFW2CGenerator.
+func (g *Generator) Audit(s *domain.Submission) *AuditReport {
+	local := g.forSubmission(s)
+	rep := &AuditReport{}
+	add := func(sev domain.Severity, code, field string, employee int, msg string) {
+		rep.Findings = append(rep.Findings, AuditFinding{
+			Severity: sev, Code: code, Record: local.recordFor(field, employee),
+			Field: field, Employee: employee, Message: msg,
+		})
+	}
+
+	if year, _ := strconv.Atoi(s.Employer.TaxYear); !g.fixedSpec {
+		if _, exact := spec.ForYear(year); !exact {
+			add(domain.SeverityError, CodeUnsupportedYear, "TaxYear", -1,
+				fmt.Sprintf("Tax year %q has no EFW2C layout; the TY%d layout would be used", s.Employer.TaxYear, local.yspec.TaxYear))
+		}
+	}
+	if len(s.Employees) == 0 {
+		add(domain.SeverityError, CodeRecordCounts, "Employees", -1, "Submission has no employees; SSA requires at least one RCW")
+	}
+
+	// Generate repeats the truncation errors Validate reports.
+	seen := make(map[string]bool)
+	addErrs := func(errs ValidationErrors) {
+		for _, e := range errs {
+			key := fmt.Sprint(e.Code, e.Employee, e.Field)
+			if !seen[key] {
+				seen[key] = true
+				add(domain.SeverityError, e.Code, e.Field, e.Employee, e.Message)
+			}
+		}
+	}
+	addErrs(g.Validate(s))
+
+	var out bytes.Buffer
+	res, err := g.generate(context.Background(), &out, s)
+	var verrs ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		addErrs(verrs)
+	case err != nil && len(s.Employees) > 0:
+		add(domain.SeverityError, CodeGenerateFailed, "", -1, err.Error())
+	case err == nil:
+		for _, m := range local.checkTotals(out.Bytes()) {
+			add(domain.SeverityError, CodeTotalsMismatch, m.field, -1, m.msg)
+		}
+	}
+
+	for i := range s.Employees {
+		for _, m := range employmentConflicts(s.Employer.EmploymentCode, &s.Employees[i].Amounts) {
+			add(domain.SeverityError, CodeEmploymentMismatch, m.field, i, m.msg)
+		}
+	}
+
+	if msg := thirdPartySickConflict(s); msg != "" {
+		add(domain.SeverityWarning, CodeThirdPartySick, "CorrectThirdPartySick", -1, msg)
+	}
+
+	for _, w := range g.Check(s) {
+		add(domain.SeverityWarning, w.Code, w.Field, w.Employee, w.Message)
+	}
+
+	if s.Employer.EmploymentCode == "" {
+		add(domain.SeverityInfo, CodeDefaultApplied, "EmploymentCode", -1, "Employment code is blank; R (Regular) will be written")
+	}
+	if s.Employer.KindOfEmployer == "" {
+		add(domain.SeverityInfo, CodeDefaultApplied, "KindOfEmployer", -1, "Kind of employer is blank; N (None apply) will be written")
+	}
+	if err == nil {
+		sum := s.Summary()
+		add(domain.SeverityInfo, CodeRecordCounts, "", -1, fmt.Sprintf(
+			"File has %d records (%d bytes): %d RCW, %d RCO, %d RCS",
+			res.RecordCount, res.ByteCount, sum.RCWRecords, sum.RCORecords, sum.RCSRecords))
+	}
+
+	rank := map[domain.Severity]int{domain.SeverityError: 0, domain.SeverityWarning: 1, domain.SeverityInfo: 2}
+	slices.SortStableFunc(rep.Findings, func(a, b AuditFinding) int {
+		return rank[a.Severity] - rank[b.Severity]
+	})
+	return rep
+}
+
+// recordFor names the record a finding's field is written to. Employee
+// fields live in the RCW unless only the RCO or RCS carries them.
+func (g *Generator) recordFor(field string, employee int) string {
+	has := func(fields []spec.Field) bool {
+		return slices.ContainsFunc(fields, func(f spec.Field) bool {
+			return f.Name == field || f.Name == "Orig"+field || f.Name == "Correct"+field
+		})
+	}
+	switch {
+	case employee >= 0 && !has(g.yspec.RCW) && has(g.yspec.RCO):
+		return "RCO"
+	case employee >= 0 && !has(g.yspec.RCW) && has(g.yspec.RCS):
+		return "RCS"
+	case employee >= 0:
+		return "RCW"
+	case field == "BSOUID" || has(g.yspec.RCA) && !has(g.yspec.RCE):
+		return "RCA"
+	}
+	return "RCE"
+}
+
+// auditMsg is a field-level audit problem before it becomes a finding.
+type auditMsg struct{ field, msg string }
+
+// employmentConflicts reports amounts that SSA does not accept under the
+// employer's employment code: Social Security wages, tax or tips for
+// Medicare-qualified government employment (Q), and Social Security or
+// Medicare amounts for railroad employment (X), which is reported under
+// RRTA instead.
+func employmentConflicts(code string, a *domain.MonetaryAmounts) []auditMsg {
+	type amount struct {
+		field      string
+		orig, corr int64
+	}
+	ss := []amount{
+		{"SocialSecurityWages", a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages},
+		{"SocialSecurityTax", a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax},
+		{"SocialSecurityTips", a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips},
+	}
+	med := []amount{
+		{"MedicareWages", a.OriginalMedicareWages, a.CorrectMedicareWages},
+		{"MedicareTax", a.OriginalMedicareTax, a.CorrectMedicareTax},
+	}
+	var check []amount
+	var why string
+	switch code {
+	case "Q":
+		check, why = ss, "employment code Q (Medicare qualified government) has no Social Security"
+	case "X":
+		check, why = append(ss, med...), "employment code X (railroad) is reported under RRTA"
+	}
+	var out []auditMsg
+	for _, am := range check {
+		if am.orig != 0 || am.corr != 0 {
+			out = append(out, auditMsg{am.field, fmt.Sprintf("%s must be zero: %s", am.field, why)})
+		}
+	}
+	return out
+}
+
+// thirdPartySickConflict reports an RCE third-party sick pay indicator that
+// disagrees with the employees' Box 13 corrections: an employee corrected
+// to third-party sick pay needs the RCE's correct indicator set to "1", and
+// an RCE corrected to "1" needs at least one such employee.
+func thirdPartySickConflict(s *domain.Submission) string {
+	n := 0
+	for i := range s.Employees {
+		if p := s.Employees[i].Box13.CorrectThirdPartySickPay; p != nil && *p {
+			n++
+		}
+	}
+	switch ind := s.Employer.CorrectThirdPartySick; {
+	case n > 0 && ind == "":
+		return fmt.Sprintf("RCE indicator missing: %d employee(s) corrected to Box 13 third-party sick pay, but the RCE third-party sick pay indicator is not being corrected", n)
+	case n > 0 && ind != "1":
+		return fmt.Sprintf("%d employee(s) corrected to Box 13 third-party sick pay, but the RCE third-party sick pay indicator is corrected to %q", n, ind)
+	case n == 0 && ind == "1":
+		return "RCE third-party sick pay indicator is corrected to 1, but no employee's Box 13 third-party sick pay is corrected to checked"
+	}
+	return ""
+}
+
+// rctBoxes pairs each RCW Box 1-7 field with its RCT total.
+var rctBoxes = []struct{ rcw, rct, field string }{
+	{"WagesTipsOther", "TotalWagesTips", "WagesTipsOther"},
+	{"FedIncomeTax", "TotalFedIncomeTax", "FederalIncomeTax"},
+	{"SSWages", "TotalSSWages", "SocialSecurityWages"},
+	{"SSTax", "TotalSSTax", "SocialSecurityTax"},
+	{"MedicareWages", "TotalMedicareWages", "MedicareWages"},
+	{"MedicareTax", "TotalMedicareTax", "MedicareTax"},
+	{"SSTips", "TotalSSTips", "SocialSecurityTips"},
+}
+
+// checkTotals re-reads a generated file the way AccuWage does: each RCT's
+// RCW count and Box 1-7 totals must equal the RCWs written since its RCE.
+func (g *Generator) checkTotals(file []byte) []auditMsg {
+	records, err := readRecords(bytes.NewReader(file))
+	if err != nil {
+		return []auditMsg{{"", "generated file is unreadable: " + err.Error()}}
+	}
+	var out []auditMsg
+	sums := make([]moneyTotal, len(rctBoxes))
+	rcw := 0
+	for _, rec := range records {
+		switch rec[:3] {
+		case "RCE":
+			sums, rcw = make([]moneyTotal, len(rctBoxes)), 0
+		case "RCW":
+			rcw++
+			m := moneyReader{rec: rec, fields: g.yspec.RCW}
+			for i, b := range rctBoxes {
+				sums[i].add(m.money("Orig"+b.rcw), m.money("Correct"+b.rcw))
+			}
+			if m.err != nil {
+				out = append(out, auditMsg{"", "RCW " + m.err.Error()})
+			}
+		case "RCT":
+			if n, _ := strconv.Atoi(field(rec, g.yspec.RCT, "TotalRCWRecords")); n != rcw {
+				out = append(out, auditMsg{"TotalRCWRecords", fmt.Sprintf("RCT counts %d RCW records, file has %d", n, rcw)})
+			}
+			m := moneyReader{rec: rec, fields: g.yspec.RCT}
+			for i, b := range rctBoxes {
+				for _, side := range []struct {
+					prefix string
+					want   int64
+				}{{"Orig", sums[i].orig}, {"Correct", sums[i].corr}} {
+					if got := m.money(side.prefix + b.rct); got != side.want {
+						out = append(out, auditMsg{b.field, fmt.Sprintf("RCT %s%s is %s, RCWs sum to %s",
+							side.prefix, b.rct, dollars(got), dollars(side.want))})
+					}
+				}
+			}
+			if m.err != nil {
+				out = append(out, auditMsg{"", "RCT " + m.err.Error()})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package efw2c
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// CoverageReport maps each record identifier (RCA, RCE, RCW, RCO, RCS, RCT,
+// RCF) to the names of spec fields that were left at their blank default in
+// every emitted record of that type. Blank-type filler fields are never
+// listed. Every record identifier is present, with an empty (non-nil) slice
+// when all of its fields were written.
+type CoverageReport map[string][]string
+
+// Coverage generates s and reports which spec fields the generator did not
+// populate. Run it against a maximal submission (every domain field set) to
+// find fields the generator cannot write at all.
+func (g *Generator) Coverage(ctx context.Context, s *domain.Submission) (CoverageReport, error) {
+	var buf bytes.Buffer
+	if err := g.Generate(ctx, s, &buf); err != nil {
+		return nil, err
+	}
+
+	// Resolve the same layout Generate used.
+	local := g.forSubmission(s)
+	layouts := local.yspec.Records()
+
+	written := make(map[string]map[string]bool, len(layouts))
+	for id := range layouts {
+		written[id] = map[string]bool{}
+	}
+	// readRecords drops any terminators added by WithLineEnding.
+	records, err := readRecords(&buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		id := rec[:3]
+		fields, ok := layouts[id]
+		if !ok {
+			continue
+		}
+		for _, f := range fields {
+			if strings.TrimSpace(rec[f.Start-1:f.End]) != "" {
+				written[id][f.Name] = true
+			}
+		}
+	}
+
+	report := make(CoverageReport, len(layouts))
+	for id, fields := range layouts {
+		missing := []string{}
+		for _, f := range fields {
+			if f.Type == spec.Blank || written[id][f.Name] {
+				continue
+			}
+			missing = append(missing, f.Name)
+		}
+		report[id] = missing
+	}
+	return report, nil
+}
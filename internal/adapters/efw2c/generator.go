@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -15,25 +17,218 @@ import (
 type Generator struct {
 	year  int
 	yspec *spec.YearSpec
+
+	// fixedSpec is set by NewWithSpec: yspec is used for every submission
+	// instead of resolving the layout from the submission's tax year.
+	fixedSpec bool
+
+	strictPairing bool
+
+	// lowercaseBSOUID lets Validate accept a lower-case BSO User ID; see
+	// WithLowercaseBSOUID.
+	lowercaseBSOUID bool
+
+	// sandboxMarker, when set, is written to the RCA ResubWFID field with
+	// ResubIndicator "1"; see WithSandboxMarker.
+	sandboxMarker string
+
+	// lineEnding is written after every record; see WithLineEnding.
+	lineEnding LineEnding
+
+	// trimPolicy decides whether over-length text blocks generation or is
+	// cut to fit; see WithTrimPolicy.
+	trimPolicy TrimPolicy
+
+	// omitRCT leaves the RCT out of each employer block; see WithoutRCT.
+	omitRCT bool
+
+	// rcsLocality writes the Box 20 locality name to the RCS; see
+	// WithRCSLocality.
+	rcsLocality bool
+
+	// blockSize, when positive, pads the file with spaces to a multiple of
+	// it; see WithBlockPadding.
+	blockSize int
+
+	// truncs, when set, collects every value put cut to fit its field.
+	// Only per-call copies from forSubmission set it.
+	truncs *truncLog
+}
+
+// Option configures optional Generator behaviour.
+type Option func(*Generator)
+
+// WithStrictPairing makes one-sided corrections (corrected amount set,
+// original zero) on Boxes 2, 4 and 6 a hard error from Generate instead of
+// a warning from CheckPairing.
+func WithStrictPairing() Option {
+	return func(g *Generator) { g.strictPairing = true }
+}
+
+// WithLowercaseBSOUID makes Validate accept a BSO User ID typed in lower
+// case, which is upper-cased when the RCA is written. By default only the
+// upper-case form SSA issues is accepted.
+func WithLowercaseBSOUID() Option {
+	return func(g *Generator) { g.lowercaseBSOUID = true }
+}
+
+// DefaultSandboxMarker is the RCA ResubWFID written by WithSandboxMarker("").
+const DefaultSandboxMarker = "SANDBX"
+
+// WithSandboxMarker tags generated files for SSA sandbox/AccuW2C testing.
+// EFW2C has no dedicated test-file indicator, so the marker rides in the
+// RCA resubmission fields: ResubIndicator is set to "1" and ResubWFID to
+// marker (up to 6 chars; DefaultSandboxMarker when empty). Record
+// identifiers and every other record are left exactly as in production.
+// Never use this for a production upload: SSA would read the file as a
+// resubmission of wage file marker.
+func WithSandboxMarker(marker string) Option {
+	if marker == "" {
+		marker = DefaultSandboxMarker
+	}
+	return func(g *Generator) { g.sandboxMarker = marker }
+}
+
+// LineEnding selects the terminator Generate writes after each record.
+type LineEnding int
+
+const (
+	// None writes records back to back, as SSA BSO upload expects.
+	None LineEnding = iota
+	// LF follows each record with "\n".
+	LF
+	// CRLF follows each record with "\r\n".
+	CRLF
+)
+
+// terminator returns the bytes written after each record.
+func (l LineEnding) terminator() string {
+	switch l {
+	case LF:
+		return "\n"
+	case CRLF:
+		return "\r\n"
+	}
+	return ""
+}
+
+// WithLineEnding follows every record with l's terminator, for state
+// agencies and diff tools that want newline-delimited records. Records are
+// still exactly spec.RecordLen bytes excluding the terminator. The default,
+// None, is what SSA accepts.
+func WithLineEnding(l LineEnding) Option {
+	return func(g *Generator) { g.lineEnding = l }
+}
+
+// WithoutRCT leaves the RCT totals record out of every employer block, for
+// state agencies that take the SSA layout but reject the RCT. The RCF still
+// counts every RCW. SSA requires the RCT, so this is a state output mode
+// only: it must be combined with WithLineEnding(LF) or WithLineEnding(CRLF),
+// and Generate refuses to write an SSA-style file (LineEnding None)
+// without one.
+func WithoutRCT() Option {
+	return func(g *Generator) { g.omitRCT = true }
+}
+
+// WithRCSLocality writes each employee's Box 20 locality name, corrected
+// name first, to RCS Supplemental Data 1. The RCS has no locality position
+// and that area is state-defined, so by default it is left blank; use this
+// only for a state that reads the locality there.
+func WithRCSLocality() Option {
+	return func(g *Generator) { g.rcsLocality = true }
+}
+
+// TrimPolicy decides what happens to a text value longer than its field.
+type TrimPolicy int
+
+const (
+	// TrimReject makes an over-length value a ValidationError (code
+	// field_overflow) from Validate and Generate. It is the default, so
+	// names and addresses are never silently shortened.
+	TrimReject TrimPolicy = iota
+	// TrimTruncate cuts the value to the field width and reports a
+	// field_truncated warning from Check.
+	TrimTruncate
+)
+
+// WithTrimPolicy selects how over-length text values are handled.
+func WithTrimPolicy(p TrimPolicy) Option {
+	return func(g *Generator) { g.trimPolicy = p }
+}
+
+// WithBlockPadding pads the finished file with spaces to the next multiple
+// of n bytes, for intake systems that read fixed-size blocks (e.g. 23552).
+// The padding comes after the RCF and is blank throughout, so it cannot be
+// taken for a record: it has no record identifier, and Parse ignores it.
+// n <= 0, the default, writes no padding.
+func WithBlockPadding(n int) Option {
+	return func(g *Generator) { g.blockSize = n }
 }
 
-func New(year int) (*Generator, error) {
+func New(year int, opts ...Option) (*Generator, error) {
 	if year == 0 {
 		year = spec.DefaultYear
 	}
 	yspec, exact := spec.ForYear(year)
+	g := &Generator{year: year, yspec: yspec}
+	for _, opt := range opts {
+		opt(g)
+	}
 	if !exact {
-		return &Generator{year: year, yspec: yspec},
-			fmt.Errorf("no exact spec for TY%d; using TY%d layout as fallback", year, spec.DefaultYear)
+		return g, fmt.Errorf("no exact spec for TY%d; using TY%d layout as fallback", year, spec.DefaultYear)
 	}
-	return &Generator{year: year, yspec: yspec}, nil
+	return g, nil
 }
 
-func MustNew(year int) *Generator {
+func MustNew(year int, opts ...Option) *Generator {
+	if year == 0 {
+		year = spec.DefaultYear
+	}
 	yspec, _ := spec.ForYear(year)
-	return &Generator{year: year, yspec: yspec}
+	g := &Generator{year: year, yspec: yspec}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// ParseDefaultYear reads a default tax year setting such as the
+// DEFAULT_TAX_YEAR environment variable. Blank means spec.DefaultYear; a
+// value that is not a supported year is logged as a warning and also
+// falls back to spec.DefaultYear, so a stale setting cannot stop the
+// server starting.
+func ParseDefaultYear(v string) int {
+	if v == "" {
+		return spec.DefaultYear
+	}
+	year, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || !slices.Contains(spec.Supported(), year) {
+		slog.Warn("unsupported default tax year; using the spec default",
+			"value", v, "default", spec.DefaultYear, "supported", spec.Supported())
+		return spec.DefaultYear
+	}
+	return year
+}
+
+// NewWithSpec returns a generator that always uses ys, regardless of the
+// submission's tax year. It is intended for tests and spec overrides; ys must
+// be a gapless 1..1024 layout for every record.
+func NewWithSpec(year int, ys *spec.YearSpec, opts ...Option) (*Generator, error) {
+	if ys == nil {
+		return nil, fmt.Errorf("efw2c: nil spec")
+	}
+	if err := ys.CheckLayout(); err != nil {
+		return nil, err
+	}
+	g := &Generator{year: year, yspec: ys, fixedSpec: true}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
 }
 
+// Year returns the default tax year, the one new submissions start from.
+// Satisfies ports.EFW2CGenerator.
 func (g *Generator) Year() int            { return g.year }
 func (g *Generator) Spec() *spec.YearSpec { return g.yspec }
 
@@ -52,113 +247,244 @@ func (g *Generator) SupportedYears() []domain.TaxYearInfo {
 	return out
 }
 
-// Generate writes a complete EFW2C byte stream (no CR/LF between records).
-// Record order per spec: RCA, RCE, [RCW (RCO?) (RCS?)...], RCT, RCF.
+// Generate writes a complete EFW2C byte stream (no CR/LF between records
+// unless WithLineEnding is set).
+// Record order per spec: RCA, RCE, [RCW (RCO?) (RCS?)...], RCT, (RCU?), RCF,
+// with the RCE … RCU block repeated for each of s.AdditionalEmployers
+// (the RCT is left out under WithoutRCT).
+// Additional employers with no tax year take s's; pairing and width errors
+// index employees within their own block.
+//
+// ctx is checked before each employee and each record written; once it is
+// done Generate stops and returns ctx.Err(), with w possibly holding a
+// partial file.
 func (g *Generator) Generate(ctx context.Context, s *domain.Submission, w io.Writer) error {
-	// Resolve the correct spec for this submission's tax year.
-	yearInt, _ := strconv.Atoi(s.Employer.TaxYear)
-	yspec, _ := spec.ForYear(yearInt)
-	local := &Generator{year: yearInt, yspec: yspec}
-
-	records := []string{
-		local.buildRCA(s),
-		local.buildRCE(s),
-	}
-
-	// Accumulators for RCT totals (only track what we actually write in RCW)
-	var (
-		origWages, corrWages                           int64
-		origFed, corrFed                               int64
-		origSS, corrSS                                 int64
-		origSSTax, corrSSTax                           int64
-		origMed, corrMed                               int64
-		origMedTax, corrMedTax                         int64
-		origSSTips, corrSSTips                         int64
-		origDepCare, corrDepCare                       int64
-		origNQ457, corrNQ457                           int64
-		origNQNot457, corrNQNot457                     int64
-		origD, corrD                                   int64
-		origE, corrE                                   int64
-		origG, corrG                                   int64
-		origW, corrW                                   int64
-		origAA, corrAA                                 int64
-		origBB, corrBB                                 int64
-		origDD, corrDD                                 int64
-	)
+	_, err := g.GenerateResult(ctx, s, w)
+	return err
+}
 
-	for i := range s.Employees {
-		e := &s.Employees[i]
-		records = append(records, local.buildRCW(e))
+// GenerateResult is Generate, also reporting what was written: record,
+// byte and RCW counts, any truncated values, and s's Check warnings.
+// Satisfies ports.EFW2CGenerator.
+func (g *Generator) GenerateResult(ctx context.Context, s *domain.Submission, w io.Writer) (GenerateResult, error) {
+	return g.generate(ctx, w, blockSubmissions(s)...)
+}
 
-		// Emit RCO if any optional fields are non-zero
-		if local.hasRCOData(e) {
-			records = append(records, local.buildRCO(e))
+// blockSubmissions splits s into one submission per employer block, in
+// file order, each keeping s's submitter; an additional employer with no
+// tax year takes s's. s without additional employers is returned as is.
+func blockSubmissions(s *domain.Submission) []*domain.Submission {
+	if len(s.AdditionalEmployers) == 0 {
+		return []*domain.Submission{s}
+	}
+	blocks := s.EmployerBlocks()
+	subs := make([]*domain.Submission, len(blocks))
+	for i, b := range blocks {
+		sub := *s
+		sub.Employer, sub.Employees, sub.AdditionalEmployers = b.Employer, b.Employees, nil
+		if sub.Employer.TaxYear == "" {
+			sub.Employer.TaxYear = s.Employer.TaxYear
 		}
-		// Emit RCS if state/local data present
-		if local.hasRCSData(e) {
-			records = append(records, local.buildRCS(e))
+		subs[i] = &sub
+	}
+	return subs
+}
+
+// GenerateMulti writes one EFW2C file covering several employers (or
+// establishments), for intake systems that want a single upload: one RCA
+// from submitter, then an RCE … RCT (RCU) block per submission, then one
+// RCF counting every RCW. Each RCT/RCU totals only its own RCE's records,
+// and the submissions' own submitters are ignored. The RCA falls back to
+// the first submission's employer wherever submitter is blank, as in
+// Generate. All submissions must share a tax year.
+//
+// A spec field that falls outside the 1024-byte record is reported as an
+// error before anything is written, never as a truncated or shifted record.
+func (g *Generator) GenerateMulti(ctx context.Context, submitter domain.SubmitterInfo, subs []*domain.Submission, w io.Writer) error {
+	if len(subs) == 0 {
+		return fmt.Errorf("efw2c: no submissions to generate")
+	}
+	// Only the RCA reads Submitter, and only from the first submission.
+	first := *subs[0]
+	first.Submitter = submitter
+	_, err := g.generate(ctx, w, append([]*domain.Submission{&first}, subs[1:]...)...)
+	return err
+}
+
+// generate builds and writes the file for subs; see GenerateMulti. Nothing
+// is written unless every record was built: a dry run builds and discards
+// each record first, then the records are built again and streamed to w
+// one at a time, so memory does not grow with the number of employees.
+func (g *Generator) generate(ctx context.Context, w io.Writer, subs ...*domain.Submission) (res GenerateResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			be, ok := r.(*boundsError)
+			if !ok {
+				panic(r)
+			}
+			res, err = GenerateResult{}, be
+		}
+	}()
+	if len(subs) == 0 {
+		return res, fmt.Errorf("efw2c: no submissions to generate")
+	}
+	for _, s := range subs[1:] {
+		if s.Employer.TaxYear != subs[0].Employer.TaxYear {
+			return res, fmt.Errorf("efw2c: employer %s is TY%s, want TY%s (one tax year per file)",
+				s.Employer.EIN, s.Employer.TaxYear, subs[0].Employer.TaxYear)
 		}
+	}
 
-		origWages += e.Amounts.OriginalWagesTipsOther
-		corrWages += e.Amounts.CorrectWagesTipsOther
-		origFed += e.Amounts.OriginalFederalIncomeTax
-		corrFed += e.Amounts.CorrectFederalIncomeTax
-		origSS += e.Amounts.OriginalSocialSecurityWages
-		corrSS += e.Amounts.CorrectSocialSecurityWages
-		origSSTax += e.Amounts.OriginalSocialSecurityTax
-		corrSSTax += e.Amounts.CorrectSocialSecurityTax
-		origMed += e.Amounts.OriginalMedicareWages
-		corrMed += e.Amounts.CorrectMedicareWages
-		origMedTax += e.Amounts.OriginalMedicareTax
-		corrMedTax += e.Amounts.CorrectMedicareTax
-		origSSTips += e.Amounts.OriginalSocialSecurityTips
-		corrSSTips += e.Amounts.CorrectSocialSecurityTips
-		origDepCare += e.Amounts.OriginalDependentCare
-		corrDepCare += e.Amounts.CorrectDependentCare
-		origNQ457 += e.Amounts.OriginalNonqualPlan457
-		corrNQ457 += e.Amounts.CorrectNonqualPlan457
-		origNQNot457 += e.Amounts.OriginalNonqualNotSection457
-		corrNQNot457 += e.Amounts.CorrectNonqualNotSection457
-		origD += e.Amounts.OriginalCode401k
-		corrD += e.Amounts.CorrectCode401k
-		origE += e.Amounts.OriginalCode403b
-		corrE += e.Amounts.CorrectCode403b
-		origG += e.Amounts.OriginalCode457bGovt
-		corrG += e.Amounts.CorrectCode457bGovt
-		origW += e.Amounts.OriginalCodeW_HSA
-		corrW += e.Amounts.CorrectCodeW_HSA
-		origAA += e.Amounts.OriginalCodeAA_Roth401k
-		corrAA += e.Amounts.CorrectCodeAA_Roth401k
-		origBB += e.Amounts.OriginalCodeBB_Roth403b
-		corrBB += e.Amounts.CorrectCodeBB_Roth403b
-		origDD += e.Amounts.OriginalCodeDD_EmpHealth
-		corrDD += e.Amounts.CorrectCodeDD_EmpHealth
-	}
-
-	records = append(records,
-		local.buildRCT(
-			origWages, corrWages, origFed, corrFed,
-			origSS, corrSS, origSSTax, corrSSTax,
-			origMed, corrMed, origMedTax, corrMedTax,
-			origSSTips, corrSSTips,
-			origDepCare, corrDepCare,
-			origNQ457, corrNQ457, origNQNot457, corrNQNot457,
-			origD, corrD, origE, corrE, origG, corrG,
-			origW, corrW, origAA, corrAA, origBB, corrBB,
-			origDD, corrDD,
-		),
-		local.buildRCF(len(s.Employees)),
-	)
-
-	for _, r := range records {
+	local := g.forSubmission(subs[0])
+	if local.omitRCT && local.lineEnding == None {
+		return res, fmt.Errorf("efw2c: WithoutRCT needs a state output mode (WithLineEnding LF or CRLF); SSA requires the RCT")
+	}
+	var errs ValidationErrors
+	for _, s := range subs {
+		pairing, _ := local.CheckPairing(s)
+		errs = append(errs, pairing...)
+		errs = append(errs, CheckWidths(s)...)
+		errs = append(errs, CheckNegative(s)...)
+	}
+	if len(errs) > 0 {
+		return res, errs
+	}
+
+	// The dry run panics with any *boundsError and collects truncations.
+	truncs := &truncLog{employee: -1}
+	local.truncs = truncs
+	if _, err := local.build(ctx, subs, func(r string) error {
 		if len(r) != spec.RecordLen {
 			return fmt.Errorf("record %q is %d bytes (want %d)", r[:3], len(r), spec.RecordLen)
 		}
-		if _, err := io.WriteString(w, r); err != nil {
+		return nil
+	}); err != nil {
+		return res, err
+	}
+	local.truncs = nil
+	if local.trimPolicy == TrimReject && len(truncs.items) > 0 {
+		return res, truncs.items
+	}
+	for _, t := range truncs.items {
+		res.Truncations = append(res.Truncations, truncationWarning(t))
+	}
+
+	term := local.lineEnding.terminator()
+	res.RCWCount, err = local.build(ctx, subs, func(r string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := io.WriteString(w, r+term)
+		res.ByteCount += n
+		if err != nil {
 			return err
 		}
+		res.RecordCount++
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	if n := local.blockSize; n > 0 && res.ByteCount%n != 0 {
+		pad, err := io.WriteString(w, strings.Repeat(" ", n-res.ByteCount%n))
+		res.ByteCount += pad
+		if err != nil {
+			return res, err
+		}
+	}
+	res.Warnings = g.Check(subs[0])
+	for _, s := range subs[1:] {
+		res.Warnings = append(res.Warnings, g.Check(s)...)
+	}
+	return res, nil
+}
+
+// build passes every record of the file for subs to emit, in order: the
+// RCA, an RCE … RCT (RCU) block per submission, then the RCF. It stops at
+// the first emit error, or once ctx is done, and returns the total RCW
+// count.
+func (g *Generator) build(ctx context.Context, subs []*domain.Submission, emit func(string) error) (int, error) {
+	if err := emit(g.buildRCA(subs[0])); err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, s := range subs {
+		n, err := g.employerRecords(ctx, s, emit)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, emit(g.buildRCF(total))
+}
+
+// employerRecords passes the RCE … RCT (RCU) block for s to emit and
+// returns its RCW count. The RCT/RCU accumulators are local, so each RCE's
+// totals cover only its own employees.
+func (g *Generator) employerRecords(ctx context.Context, s *domain.Submission, emit func(string) error) (int, error) {
+	g.truncs.at(-1)
+	if err := emit(g.buildRCE(s)); err != nil {
+		return 0, err
+	}
+
+	// RCT totals cover only what this RCE's RCW records carry.
+	var totals rctTotals
+	// rcwCount is the number of RCW records under this RCE; RCO and RCS
+	// records do not count toward RCT/RCF totals.
+	rcwCount := 0
+	// RCU totals, accumulated from the RCO records actually written.
+	var optTotals rcuTotals
+
+	for i := range s.Employees {
+		e := &s.Employees[i]
+		if err := ctx.Err(); err != nil {
+			return rcwCount, err
+		}
+		g.truncs.at(i)
+		if err := emit(g.buildRCW(e)); err != nil {
+			return rcwCount, err
+		}
+		rcwCount++
+		totals.add(&e.Amounts)
+
+		// Emit RCO if any optional fields are non-zero
+		if g.hasRCOData(e) {
+			if err := emit(g.buildRCO(e)); err != nil {
+				return rcwCount, err
+			}
+			optTotals.add(&e.Amounts)
+		}
+		// Emit RCS if state/local data present
+		if g.hasRCSData(e) {
+			if err := emit(g.buildRCS(e, s.Employer.State)); err != nil {
+				return rcwCount, err
+			}
+		}
+	}
+
+	g.truncs.at(-1)
+	if !g.omitRCT {
+		if err := emit(g.buildRCT(rcwCount, &totals)); err != nil {
+			return rcwCount, err
+		}
+	}
+	// RCU follows the RCT (and so the last RCO) only when an RCO was written.
+	if optTotals.rcoCount > 0 {
+		return rcwCount, emit(g.buildRCU(&optTotals))
+	}
+	return rcwCount, nil
+}
+
+// forSubmission returns a copy of g (options included) bound to the
+// correct spec for the submission's tax year.
+func (g *Generator) forSubmission(s *domain.Submission) *Generator {
+	local := *g
+	if g.fixedSpec {
+		return &local
 	}
-	return nil
+	local.year, _ = strconv.Atoi(s.Employer.TaxYear)
+	local.yspec, _ = spec.ForYear(local.year)
+	return &local
 }
 
 // ---------------------------------------------------------------------------
@@ -166,14 +492,18 @@ func (g *Generator) Generate(ctx context.Context, s *domain.Submission, w io.Wri
 // ---------------------------------------------------------------------------
 
 func (g *Generator) hasRCOData(e *domain.EmployeeRecord) bool {
-	a := &e.Amounts
-	return a.OriginalAllocatedTips != 0 || a.CorrectAllocatedTips != 0
+	if !hasField(g.yspec.RCO, "OrigMedicaidWaiver") {
+		// Before TY2024 the RCO has no Code II positions, so Code II alone
+		// would write an empty record.
+		a := e.Amounts
+		a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver = 0, 0
+		return (&domain.EmployeeRecord{Amounts: a}).HasRCOData()
+	}
+	return e.HasRCOData()
 }
 
 func (g *Generator) hasRCSData(e *domain.EmployeeRecord) bool {
-	return e.OriginalStateCode != "" || e.CorrectStateCode != "" ||
-		e.Amounts.OriginalStateWages != 0 || e.Amounts.CorrectStateWages != 0 ||
-		e.Amounts.OriginalStateIncomeTax != 0 || e.Amounts.CorrectStateIncomeTax != 0
+	return e.HasRCSData()
 }
 
 // ---------------------------------------------------------------------------
@@ -192,33 +522,57 @@ func (g *Generator) buildRCA(s *domain.Submission) string {
 		resubIndicator = "0"
 	}
 
-	b := newBuf()
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCA, "RCA")
 	b.put("SubmitterEIN", g.yspec.RCA, cleanDigits(s.Employer.EIN, 9))
-	b.put("BSOUID", g.yspec.RCA, padAlpha(sub.BSOUID, 8))
-	// SoftwareVendorCode and SoftwareCode left blank — not a software vendor
-	// CompanyName: 57 chars at positions 32-88 per TY2024 §5.5
-	b.put("CompanyName", g.yspec.RCA, padAlpha(s.Employer.Name, 57))
-	b.put("LocationAddress", g.yspec.RCA, padAlpha(s.Employer.AddressLine1, 22))
-	b.put("DeliveryAddress", g.yspec.RCA, padAlpha(s.Employer.AddressLine2, 22))
-	b.put("City", g.yspec.RCA, padAlpha(s.Employer.City, 22))
-	b.put("StateAbbrev", g.yspec.RCA, padAlpha(s.Employer.State, 2))
-	b.put("ZIPCode", g.yspec.RCA, padNumeric(s.Employer.ZIP, 5))
-	b.put("ZIPExtension", g.yspec.RCA, padNumeric(s.Employer.ZIPExtension, 4))
-	b.put("ContactName", g.yspec.RCA, padAlpha(sub.ContactName, 27))
-	b.put("ContactPhone", g.yspec.RCA, padNumeric(sub.ContactPhone, 15))
-	b.put("ContactEmail", g.yspec.RCA, padEmail(sub.ContactEmail, 40))
+	b.fill("BSOUID", g.yspec.RCA, sub.BSOUID)
+	// SoftwareCode is blank unless set; the vendor code only goes with 99.
+	if sub.SoftwareCode != "" {
+		b.fill("SoftwareCode", g.yspec.RCA, sub.SoftwareCode)
+	}
+	if sub.SoftwareCode == "99" {
+		b.fill("SoftwareVendorCode", g.yspec.RCA, sub.SoftwareVendorCode)
+	}
+	// CompanyName: 57 chars at positions 32-88 per TY2024 §5.5. RCA is the
+	// submitter record; fall back to the employer for self-prepared files.
+	b.fill("CompanyName", g.yspec.RCA, defaultStr(sub.CompanyName, s.Employer.Name))
+	// Submitter address; the employer's is used as a whole when none is set
+	// so the two are never mixed field-by-field.
+	addr1, addr2 := s.Employer.AddressLine1, s.Employer.AddressLine2
+	city, state := s.Employer.City, s.Employer.State
+	zip, zipExt := s.Employer.ZIP, s.Employer.ZIPExtension
+	foreign := s.Employer.HasForeignAddress()
+	if sub.HasAddress() {
+		addr1, addr2 = sub.AddressLine1, sub.AddressLine2
+		city, state = sub.City, sub.State
+		zip, zipExt = sub.ZIP, sub.ZIPExtension
+		foreign = false
+	}
+	b.fill("LocationAddress", g.yspec.RCA, addr1)
+	b.fill("DeliveryAddress", g.yspec.RCA, addr2)
+	b.fill("City", g.yspec.RCA, city)
+	putRegion(b, g.yspec.RCA, foreign, state, zip, zipExt,
+		s.Employer.ForeignStateProvince, s.Employer.ForeignPostalCode, s.Employer.CountryCode)
+	b.fill("ContactName", g.yspec.RCA, cleanContactName(sub.ContactName))
+	b.fillWith("ContactPhone", g.yspec.RCA, sub.ContactPhone, padNumeric)
+	b.fill("PhoneExtension", g.yspec.RCA, sub.PhoneExtension)
+	b.fill("ContactFax", g.yspec.RCA, sub.ContactFax)
+	b.fillWith("ContactEmail", g.yspec.RCA, sub.ContactEmail, padEmail)
 	b.put("PreparerCode", g.yspec.RCA, preparerCode)
 	b.put("ResubIndicator", g.yspec.RCA, resubIndicator)
 	if sub.ResubWFID != "" {
 		// ResubWFID is 6 chars per TY2024 §5.5 (positions 318-323)
-		b.put("ResubWFID", g.yspec.RCA, padAlpha(sub.ResubWFID, 6))
+		b.fill("ResubWFID", g.yspec.RCA, sub.ResubWFID)
+	}
+	if g.sandboxMarker != "" {
+		b.put("ResubIndicator", g.yspec.RCA, "1")
+		b.fill("ResubWFID", g.yspec.RCA, g.sandboxMarker)
 	}
 	return b.String()
 }
 
 func (g *Generator) buildRCE(s *domain.Submission) string {
-	b := newBuf()
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCE, "RCE")
 	b.put("TaxYear", g.yspec.RCE, s.Employer.TaxYear)
 	if s.Employer.OriginalEIN != "" {
@@ -233,82 +587,84 @@ func (g *Generator) buildRCE(s *domain.Submission) string {
 		b.put("AgentForEIN", g.yspec.RCE, cleanDigits(s.Employer.AgentEIN, 9))
 	}
 	// EmployerName: 57 chars at positions 44-100 per TY2024 §5.6
-	b.put("EmployerName", g.yspec.RCE, padAlpha(s.Employer.Name, 57))
-	b.put("LocationAddress", g.yspec.RCE, padAlpha(s.Employer.AddressLine1, 22))
-	b.put("DeliveryAddress", g.yspec.RCE, padAlpha(s.Employer.AddressLine2, 22))
-	b.put("City", g.yspec.RCE, padAlpha(s.Employer.City, 22))
-	b.put("StateAbbrev", g.yspec.RCE, padAlpha(s.Employer.State, 2))
-	b.put("ZIPCode", g.yspec.RCE, padNumeric(s.Employer.ZIP, 5))
-	b.put("ZIPExtension", g.yspec.RCE, padNumeric(s.Employer.ZIPExtension, 4))
+	b.fill("EmployerName", g.yspec.RCE, s.Employer.Name)
+	b.fill("LocationAddress", g.yspec.RCE, s.Employer.AddressLine1)
+	b.fill("DeliveryAddress", g.yspec.RCE, s.Employer.AddressLine2)
+	b.fill("City", g.yspec.RCE, s.Employer.City)
+	putRegion(b, g.yspec.RCE, s.Employer.HasForeignAddress(), s.Employer.State, s.Employer.ZIP, s.Employer.ZIPExtension,
+		s.Employer.ForeignStateProvince, s.Employer.ForeignPostalCode, s.Employer.CountryCode)
 	// CorrectEmploymentCode at position 223; OrigEmploymentCode at 222 (leave blank unless correcting)
 	b.put("CorrectEmploymentCode", g.yspec.RCE, defaultStr(s.Employer.EmploymentCode, "R"))
 	b.put("KindOfEmployer", g.yspec.RCE, defaultStr(s.Employer.KindOfEmployer, "N"))
 	// Employer contact fields at positions 228-324 per TY2024 §5.6
 	if s.Employer.ContactName != "" {
-		b.put("ContactName", g.yspec.RCE, padAlpha(s.Employer.ContactName, 27))
+		b.fill("ContactName", g.yspec.RCE, cleanContactName(s.Employer.ContactName))
 	}
 	if s.Employer.ContactPhone != "" {
-		b.put("ContactPhone", g.yspec.RCE, padNumeric(s.Employer.ContactPhone, 15))
+		b.fillWith("ContactPhone", g.yspec.RCE, s.Employer.ContactPhone, padNumeric)
+	}
+	if s.Employer.ContactPhoneExtension != "" {
+		b.fill("PhoneExtension", g.yspec.RCE, s.Employer.ContactPhoneExtension)
 	}
 	if s.Employer.ContactEmail != "" {
-		b.put("ContactEmail", g.yspec.RCE, padEmail(s.Employer.ContactEmail, 40))
+		b.fillWith("ContactEmail", g.yspec.RCE, s.Employer.ContactEmail, padEmail)
 	}
+	b.fill("OrigThirdPartySick", g.yspec.RCE, s.Employer.OriginalThirdPartySick)
+	b.fill("CorrectThirdPartySick", g.yspec.RCE, s.Employer.CorrectThirdPartySick)
 	return b.String()
 }
 
 func (g *Generator) buildRCW(e *domain.EmployeeRecord) string {
-	b := newBuf()
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCW, "RCW")
 
+	// SSN and name corrections are independent; both blocks always run so
+	// an employee can correct either, both, or neither.
+
 	// SSN: OrigSSN = previously reported (or current if no SSN correction)
 	//      CorrectSSN = new SSN (only if correcting SSN)
-	b.put("OrigSSN", g.yspec.RCW, cleanDigits(e.SSN, 9))
+	origSSN := e.SSN
 	if e.OriginalSSN != "" {
 		// Correcting SSN: OrigSSN gets the old wrong SSN, CorrectSSN gets the right one
-		b.put("OrigSSN", g.yspec.RCW, cleanDigits(e.OriginalSSN, 9))
+		origSSN = e.OriginalSSN
 		b.put("CorrectSSN", g.yspec.RCW, cleanDigits(e.SSN, 9))
 	}
+	b.put("OrigSSN", g.yspec.RCW, cleanDigits(origSSN, 9))
 
-	// Names: write Orig/Correct pairs when correcting name; otherwise put current name in CorrectFirstName etc.
+	// Names: the correct name is always written per spec; the Orig fields
+	// carry the previously reported name only when correcting it.
 	if e.OriginalFirstName != "" || e.OriginalLastName != "" {
-		// Name correction: orig = previously wrong, correct = new correct name
-		b.put("OrigFirstName", g.yspec.RCW, padAlpha(e.OriginalFirstName, 15))
-		b.put("OrigMiddleName", g.yspec.RCW, padAlpha(e.OriginalMiddleName, 15))
-		b.put("OrigLastName", g.yspec.RCW, padAlpha(e.OriginalLastName, 20))
-		b.put("CorrectFirstName", g.yspec.RCW, padAlpha(e.FirstName, 15))
-		b.put("CorrectMiddleName", g.yspec.RCW, padAlpha(e.MiddleName, 15))
-		b.put("CorrectLastName", g.yspec.RCW, padAlpha(e.LastName, 20))
-	} else {
-		// No name correction: still write correct name in the Correct fields per spec
-		b.put("CorrectFirstName", g.yspec.RCW, padAlpha(e.FirstName, 15))
-		b.put("CorrectMiddleName", g.yspec.RCW, padAlpha(e.MiddleName, 15))
-		b.put("CorrectLastName", g.yspec.RCW, padAlpha(e.LastName, 20))
+		b.fill("OrigFirstName", g.yspec.RCW, cleanName(e.OriginalFirstName))
+		b.fill("OrigMiddleName", g.yspec.RCW, cleanName(e.OriginalMiddleName))
+		b.fill("OrigLastName", g.yspec.RCW, cleanName(withSuffix(e.OriginalLastName, e.OriginalSuffix)))
 	}
+	b.fill("CorrectFirstName", g.yspec.RCW, cleanName(e.FirstName))
+	b.fill("CorrectMiddleName", g.yspec.RCW, cleanName(e.MiddleName))
+	b.fill("CorrectLastName", g.yspec.RCW, cleanName(withSuffix(e.LastName, e.Suffix)))
 
 	// Address
-	b.put("LocationAddress", g.yspec.RCW, padAlpha(e.AddressLine1, 22))
-	b.put("DeliveryAddress", g.yspec.RCW, padAlpha(e.AddressLine2, 22))
-	b.put("City", g.yspec.RCW, padAlpha(e.City, 22))
-	b.put("StateAbbrev", g.yspec.RCW, padAlpha(e.State, 2))
-	b.put("ZIPCode", g.yspec.RCW, padNumeric(e.ZIP, 5))
-	b.put("ZIPExtension", g.yspec.RCW, padNumeric(e.ZIPExtension, 4))
+	b.fill("LocationAddress", g.yspec.RCW, e.AddressLine1)
+	b.fill("DeliveryAddress", g.yspec.RCW, e.AddressLine2)
+	b.fill("City", g.yspec.RCW, e.City)
+	putRegion(b, g.yspec.RCW, e.HasForeignAddress(), e.State, e.ZIP, e.ZIPExtension,
+		e.ForeignStateProvince, e.ForeignPostalCode, e.CountryCode)
 
 	// Boxes 1–7 (always write; fill with zeros if no correction)
 	a := &e.Amounts
-	b.put("OrigWagesTipsOther", g.yspec.RCW, money11(a.OriginalWagesTipsOther))
-	b.put("CorrectWagesTipsOther", g.yspec.RCW, money11(a.CorrectWagesTipsOther))
-	b.put("OrigFedIncomeTax", g.yspec.RCW, money11(a.OriginalFederalIncomeTax))
-	b.put("CorrectFedIncomeTax", g.yspec.RCW, money11(a.CorrectFederalIncomeTax))
-	b.put("OrigSSWages", g.yspec.RCW, money11(a.OriginalSocialSecurityWages))
-	b.put("CorrectSSWages", g.yspec.RCW, money11(a.CorrectSocialSecurityWages))
-	b.put("OrigSSTax", g.yspec.RCW, money11(a.OriginalSocialSecurityTax))
-	b.put("CorrectSSTax", g.yspec.RCW, money11(a.CorrectSocialSecurityTax))
-	b.put("OrigMedicareWages", g.yspec.RCW, money11(a.OriginalMedicareWages))
-	b.put("CorrectMedicareWages", g.yspec.RCW, money11(a.CorrectMedicareWages))
-	b.put("OrigMedicareTax", g.yspec.RCW, money11(a.OriginalMedicareTax))
-	b.put("CorrectMedicareTax", g.yspec.RCW, money11(a.CorrectMedicareTax))
-	b.put("OrigSSTips", g.yspec.RCW, money11(a.OriginalSocialSecurityTips))
-	b.put("CorrectSSTips", g.yspec.RCW, money11(a.CorrectSocialSecurityTips))
+	b.putMoney("OrigWagesTipsOther", g.yspec.RCW, a.OriginalWagesTipsOther)
+	b.putMoney("CorrectWagesTipsOther", g.yspec.RCW, a.CorrectWagesTipsOther)
+	b.putMoney("OrigFedIncomeTax", g.yspec.RCW, a.OriginalFederalIncomeTax)
+	b.putMoney("CorrectFedIncomeTax", g.yspec.RCW, a.CorrectFederalIncomeTax)
+	b.putMoney("OrigSSWages", g.yspec.RCW, a.OriginalSocialSecurityWages)
+	b.putMoney("CorrectSSWages", g.yspec.RCW, a.CorrectSocialSecurityWages)
+	b.putMoney("OrigSSTax", g.yspec.RCW, a.OriginalSocialSecurityTax)
+	b.putMoney("CorrectSSTax", g.yspec.RCW, a.CorrectSocialSecurityTax)
+	b.putMoney("OrigMedicareWages", g.yspec.RCW, a.OriginalMedicareWages)
+	b.putMoney("CorrectMedicareWages", g.yspec.RCW, a.CorrectMedicareWages)
+	b.putMoney("OrigMedicareTax", g.yspec.RCW, a.OriginalMedicareTax)
+	b.putMoney("CorrectMedicareTax", g.yspec.RCW, a.CorrectMedicareTax)
+	b.putMoney("OrigSSTips", g.yspec.RCW, a.OriginalSocialSecurityTips)
+	b.putMoney("CorrectSSTips", g.yspec.RCW, a.CorrectSocialSecurityTips)
 
 	// Box 10 — Dependent Care
 	putMoney11Pair(b, g.yspec.RCW, "OrigDependentCare", "CorrectDependentCare",
@@ -329,6 +685,20 @@ func (g *Generator) buildRCW(e *domain.EmployeeRecord) string {
 		a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b)
 	putMoney11Pair(b, g.yspec.RCW, "OrigCodeDD_EmpHealth", "CorrectCodeDD_EmpHealth",
 		a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeC", "CorrectCodeC",
+		a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeF", "CorrectCodeF",
+		a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeH", "CorrectCodeH",
+		a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeQ", "CorrectCodeQ",
+		a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeV", "CorrectCodeV",
+		a.OriginalCodeV_NSO, a.CorrectCodeV_NSO)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeY", "CorrectCodeY",
+		a.OriginalCodeY_409A, a.CorrectCodeY_409A)
+	putMoney11Pair(b, g.yspec.RCW, "OrigCodeFF_QSEHRA", "CorrectCodeFF_QSEHRA",
+		a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA)
 
 	// Box 11 — Nonqualified Plans (two components)
 	putMoney11Pair(b, g.yspec.RCW, "OrigNonqualPlan457", "CorrectNonqualPlan457",
@@ -349,101 +719,241 @@ func (g *Generator) buildRCW(e *domain.EmployeeRecord) string {
 }
 
 func (g *Generator) buildRCO(e *domain.EmployeeRecord) string {
-	b := newBuf()
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCO, "RCO")
 	a := &e.Amounts
 	putMoney11Pair(b, g.yspec.RCO, "OrigAllocatedTips", "CorrectAllocatedTips",
 		a.OriginalAllocatedTips, a.CorrectAllocatedTips)
+	putMoney11Pair(b, g.yspec.RCO, "OrigUncollectedEETax", "CorrectUncollectedEETax",
+		a.OriginalUncollectedEETax, a.CorrectUncollectedEETax)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeR_MSA", "CorrectCodeR_MSA",
+		a.OriginalCodeR_MSA, a.CorrectCodeR_MSA)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeS_SIMPLE", "CorrectCodeS_SIMPLE",
+		a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeT_Adoption", "CorrectCodeT_Adoption",
+		a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeM_UncollSS", "CorrectCodeM_UncollSS",
+		a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeN_UncollMed", "CorrectCodeN_UncollMed",
+		a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed)
+	putMoney11Pair(b, g.yspec.RCO, "OrigCodeZ_409A", "CorrectCodeZ_409A",
+		a.OriginalCodeZ_409A, a.CorrectCodeZ_409A)
+	if hasField(g.yspec.RCO, "OrigMedicaidWaiver") {
+		putMoney11Pair(b, g.yspec.RCO, "OrigMedicaidWaiver", "CorrectMedicaidWaiver",
+			a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver)
+	}
 	return b.String()
 }
 
-func (g *Generator) buildRCS(e *domain.EmployeeRecord) string {
-	b := newBuf()
-	b.put("RecordIdentifier", g.yspec.RCS, "RCS")
-	// State code from CorrectStateCode (or OriginalStateCode if no correction)
-	sc := e.CorrectStateCode
-	if sc == "" {
-		sc = e.OriginalStateCode
+// rcsState returns the postal state code an employee's RCS is written
+// for, and the field it came from: CorrectStateCode, else
+// OriginalStateCode if there is no correction, else the employer's state
+// of operations (field "State").
+func rcsState(e *domain.EmployeeRecord, employerState string) (field, code string) {
+	switch {
+	case e.CorrectStateCode != "":
+		return "CorrectStateCode", e.CorrectStateCode
+	case e.OriginalStateCode != "":
+		return "OriginalStateCode", e.OriginalStateCode
 	}
-	b.put("StateCode", g.yspec.RCS, padNumeric(statePostalToNumeric(sc), 2))
+	return "State", employerState
+}
+
+// buildRCS writes the state record. employerState is the fallback state
+// code for an employee with state amounts but no state code of their own,
+// typically a single-state employer.
+func (g *Generator) buildRCS(e *domain.EmployeeRecord, employerState string) string {
+	b := g.newBuf()
+	b.put("RecordIdentifier", g.yspec.RCS, "RCS")
+	// An unknown state stays blank; Validate reports it before we get here.
+	_, sc := rcsState(e, employerState)
+	num, _ := spec.StateNumericCode(sc)
+	b.put("StateCode", g.yspec.RCS, zeroPadNumeric(num, 2))
 	b.put("CorrectSSN", g.yspec.RCS, cleanDigits(e.SSN, 9))
-	b.put("CorrectFirstName", g.yspec.RCS, padAlpha(e.FirstName, 15))
-	b.put("CorrectMiddleName", g.yspec.RCS, padAlpha(e.MiddleName, 15))
-	b.put("CorrectLastName", g.yspec.RCS, padAlpha(e.LastName, 20))
-	b.put("StateCode2", g.yspec.RCS, padNumeric(statePostalToNumeric(sc), 2))
+	b.fill("CorrectFirstName", g.yspec.RCS, cleanName(e.FirstName))
+	b.fill("CorrectMiddleName", g.yspec.RCS, cleanName(e.MiddleName))
+	b.fill("CorrectLastName", g.yspec.RCS, cleanName(withSuffix(e.LastName, e.Suffix)))
+	b.put("StateCode2", g.yspec.RCS, zeroPadNumeric(num, 2))
 	a := &e.Amounts
 	putMoney11Pair(b, g.yspec.RCS, "OrigStateWages", "CorrectStateWages",
 		a.OriginalStateWages, a.CorrectStateWages)
 	putMoney11Pair(b, g.yspec.RCS, "OrigStateIncomeTax", "CorrectStateIncomeTax",
 		a.OriginalStateIncomeTax, a.CorrectStateIncomeTax)
+	putMoney11Pair(b, g.yspec.RCS, "OrigLocalWages", "CorrectLocalWages",
+		a.OriginalLocalWages, a.CorrectLocalWages)
+	putMoney11Pair(b, g.yspec.RCS, "OrigLocalIncomeTax", "CorrectLocalIncomeTax",
+		a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax)
+	if g.rcsLocality {
+		locality := e.CorrectLocalityName
+		if locality == "" {
+			locality = e.OriginalLocalityName
+		}
+		b.fill("SupplementalData1", g.yspec.RCS, locality)
+	}
 	return b.String()
 }
 
-func (g *Generator) buildRCT(
-	origWages, corrWages,
-	origFed, corrFed,
-	origSS, corrSS,
-	origSSTax, corrSSTax,
-	origMed, corrMed,
-	origMedTax, corrMedTax,
-	origSSTips, corrSSTips,
-	origDepCare, corrDepCare,
-	origNQ457, corrNQ457,
-	origNQNot457, corrNQNot457,
-	origD, corrD,
-	origE, corrE,
-	origG, corrG,
-	origW, corrW,
-	origAA, corrAA,
-	origBB, corrBB,
-	origDD, corrDD int64,
-) string {
-	b := newBuf()
+// moneyTotal is an original/correct pair of summed amounts, in cents.
+type moneyTotal struct{ orig, corr int64 }
+
+func (t *moneyTotal) add(orig, corr int64) {
+	t.orig += orig
+	t.corr += corr
+}
+
+// rctTotals accumulates every RCW money field that has an RCT total
+// position, for the employees under one RCE.
+type rctTotals struct {
+	// Boxes 1–7, always written; shared with Submission.Totals.
+	boxes domain.BoxTotals
+
+	// Box 10 and Box 11, written only when non-zero.
+	depCare, nq457, nqNot457 moneyTotal
+
+	// Box 12 codes, written only when non-zero.
+	codeC, codeD, codeE, codeF, codeG, codeH, codeQ, codeV, codeW, codeY moneyTotal
+	codeAA, codeBB, codeDD, codeFF                                       moneyTotal
+}
+
+func (t *rctTotals) add(a *domain.MonetaryAmounts) {
+	t.boxes.Add(a)
+
+	t.depCare.add(a.OriginalDependentCare, a.CorrectDependentCare)
+	t.nq457.add(a.OriginalNonqualPlan457, a.CorrectNonqualPlan457)
+	t.nqNot457.add(a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457)
+
+	t.codeC.add(a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife)
+	t.codeD.add(a.OriginalCode401k, a.CorrectCode401k)
+	t.codeE.add(a.OriginalCode403b, a.CorrectCode403b)
+	t.codeF.add(a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP)
+	t.codeG.add(a.OriginalCode457bGovt, a.CorrectCode457bGovt)
+	t.codeH.add(a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D)
+	t.codeQ.add(a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay)
+	t.codeV.add(a.OriginalCodeV_NSO, a.CorrectCodeV_NSO)
+	t.codeW.add(a.OriginalCodeW_HSA, a.CorrectCodeW_HSA)
+	t.codeY.add(a.OriginalCodeY_409A, a.CorrectCodeY_409A)
+	t.codeAA.add(a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k)
+	t.codeBB.add(a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b)
+	t.codeDD.add(a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth)
+	t.codeFF.add(a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA)
+}
+
+// buildRCT writes the employer total record. rcwCount is the number of RCW
+// records written since the RCE.
+func (g *Generator) buildRCT(rcwCount int, t *rctTotals) string {
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCT, "RCT")
-	b.put("TotalRCWRecords", g.yspec.RCT, fmt.Sprintf("%07d", 0)) // placeholder; overwritten below
+	b.put("TotalRCWRecords", g.yspec.RCT, fmt.Sprintf("%07d", rcwCount))
 
 	// Boxes 1-7 totals (always written)
-	b.put("OrigTotalWagesTips", g.yspec.RCT, money15(origWages))
-	b.put("CorrectTotalWagesTips", g.yspec.RCT, money15(corrWages))
-	b.put("OrigTotalFedIncomeTax", g.yspec.RCT, money15(origFed))
-	b.put("CorrectTotalFedIncomeTax", g.yspec.RCT, money15(corrFed))
-	b.put("OrigTotalSSWages", g.yspec.RCT, money15(origSS))
-	b.put("CorrectTotalSSWages", g.yspec.RCT, money15(corrSS))
-	b.put("OrigTotalSSTax", g.yspec.RCT, money15(origSSTax))
-	b.put("CorrectTotalSSTax", g.yspec.RCT, money15(corrSSTax))
-	b.put("OrigTotalMedicareWages", g.yspec.RCT, money15(origMed))
-	b.put("CorrectTotalMedicareWages", g.yspec.RCT, money15(corrMed))
-	b.put("OrigTotalMedicareTax", g.yspec.RCT, money15(origMedTax))
-	b.put("CorrectTotalMedicareTax", g.yspec.RCT, money15(corrMedTax))
-	b.put("OrigTotalSSTips", g.yspec.RCT, money15(origSSTips))
-	b.put("CorrectTotalSSTips", g.yspec.RCT, money15(corrSSTips))
+	for _, f := range []struct {
+		orig, corr string
+		total      domain.BoxTotal
+	}{
+		{"OrigTotalWagesTips", "CorrectTotalWagesTips", t.boxes.WagesTipsOther},
+		{"OrigTotalFedIncomeTax", "CorrectTotalFedIncomeTax", t.boxes.FederalIncomeTax},
+		{"OrigTotalSSWages", "CorrectTotalSSWages", t.boxes.SocialSecurityWages},
+		{"OrigTotalSSTax", "CorrectTotalSSTax", t.boxes.SocialSecurityTax},
+		{"OrigTotalMedicareWages", "CorrectTotalMedicareWages", t.boxes.MedicareWages},
+		{"OrigTotalMedicareTax", "CorrectTotalMedicareTax", t.boxes.MedicareTax},
+		{"OrigTotalSSTips", "CorrectTotalSSTips", t.boxes.SocialSecurityTips},
+	} {
+		b.put(f.orig, g.yspec.RCT, money15(f.total.Original))
+		b.put(f.corr, g.yspec.RCT, money15(f.total.Correct))
+	}
 
 	// Optional totals (only write if non-zero)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalDependentCare", "CorrectTotalDependentCare",
-		origDepCare, corrDepCare)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCode401k", "CorrectTotalCode401k", origD, corrD)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCode403b", "CorrectTotalCode403b", origE, corrE)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCode457bGovt", "CorrectTotalCode457bGovt", origG, corrG)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCodeW_HSA", "CorrectTotalCodeW_HSA", origW, corrW)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalNonqualPlan457", "CorrectTotalNonqualPlan457",
-		origNQ457, corrNQ457)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalNonqualNotSection457", "CorrectTotalNonqualNotSection457",
-		origNQNot457, corrNQNot457)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCodeAA_Roth401k", "CorrectTotalCodeAA_Roth401k",
-		origAA, corrAA)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCodeBB_Roth403b", "CorrectTotalCodeBB_Roth403b",
-		origBB, corrBB)
-	putMoney15Pair(b, g.yspec.RCT, "OrigTotalCodeDD_EmpHealth", "CorrectTotalCodeDD_EmpHealth",
-		origDD, corrDD)
+	for _, f := range []struct {
+		orig, corr string
+		total      moneyTotal
+	}{
+		{"OrigTotalDependentCare", "CorrectTotalDependentCare", t.depCare},
+		{"OrigTotalCode401k", "CorrectTotalCode401k", t.codeD},
+		{"OrigTotalCode403b", "CorrectTotalCode403b", t.codeE},
+		{"OrigTotalCodeF", "CorrectTotalCodeF", t.codeF},
+		{"OrigTotalCode457bGovt", "CorrectTotalCode457bGovt", t.codeG},
+		{"OrigTotalCodeH", "CorrectTotalCodeH", t.codeH},
+		{"OrigTotalNonqualPlan457", "CorrectTotalNonqualPlan457", t.nq457},
+		{"OrigTotalCodeW_HSA", "CorrectTotalCodeW_HSA", t.codeW},
+		{"OrigTotalNonqualNotSection457", "CorrectTotalNonqualNotSection457", t.nqNot457},
+		{"OrigTotalCodeQ", "CorrectTotalCodeQ", t.codeQ},
+		{"OrigTotalCodeC", "CorrectTotalCodeC", t.codeC},
+		{"OrigTotalCodeV", "CorrectTotalCodeV", t.codeV},
+		{"OrigTotalCodeY", "CorrectTotalCodeY", t.codeY},
+		{"OrigTotalCodeAA_Roth401k", "CorrectTotalCodeAA_Roth401k", t.codeAA},
+		{"OrigTotalCodeBB_Roth403b", "CorrectTotalCodeBB_Roth403b", t.codeBB},
+		{"OrigTotalCodeDD_EmpHealth", "CorrectTotalCodeDD_EmpHealth", t.codeDD},
+		{"OrigTotalCodeFF_QSEHRA", "CorrectTotalCodeFF_QSEHRA", t.codeFF},
+	} {
+		putMoney15Pair(b, g.yspec.RCT, f.orig, f.corr, f.total.orig, f.total.corr)
+	}
+
+	return b.String()
+}
+
+// rcuTotals accumulates the RCO money fields for the employees under one
+// RCE that had an RCO record written.
+type rcuTotals struct {
+	rcoCount  int
+	allocTips moneyTotal
+	uncollTax moneyTotal
+	codeR     moneyTotal
+	codeS     moneyTotal
+	codeT     moneyTotal
+	codeM     moneyTotal
+	codeN     moneyTotal
+	codeZ     moneyTotal
+	codeII    moneyTotal
+}
 
+func (t *rcuTotals) add(a *domain.MonetaryAmounts) {
+	t.rcoCount++
+	t.allocTips.add(a.OriginalAllocatedTips, a.CorrectAllocatedTips)
+	t.uncollTax.add(a.OriginalUncollectedEETax, a.CorrectUncollectedEETax)
+	t.codeR.add(a.OriginalCodeR_MSA, a.CorrectCodeR_MSA)
+	t.codeS.add(a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE)
+	t.codeT.add(a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption)
+	t.codeM.add(a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS)
+	t.codeN.add(a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed)
+	t.codeZ.add(a.OriginalCodeZ_409A, a.CorrectCodeZ_409A)
+	t.codeII.add(a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver)
+}
+
+// buildRCU writes the Total Optional record for the RCO records under the
+// RCE. The Box 8 totals are always present, like the RCT Box 1-7 totals;
+// the Box 12 code totals are written only when an RCO carried them.
+func (g *Generator) buildRCU(t *rcuTotals) string {
+	b := g.newBuf()
+	b.put("RecordIdentifier", g.yspec.RCU, "RCU")
+	b.put("TotalRCORecords", g.yspec.RCU, fmt.Sprintf("%07d", t.rcoCount))
+	b.put("OrigTotalAllocatedTips", g.yspec.RCU, money15(t.allocTips.orig))
+	b.put("CorrectTotalAllocatedTips", g.yspec.RCU, money15(t.allocTips.corr))
+
+	for _, f := range []struct {
+		orig, corr string
+		total      moneyTotal
+	}{
+		{"OrigTotalUncollectedEETax", "CorrectTotalUncollectedEETax", t.uncollTax},
+		{"OrigTotalCodeR_MSA", "CorrectTotalCodeR_MSA", t.codeR},
+		{"OrigTotalCodeS_SIMPLE", "CorrectTotalCodeS_SIMPLE", t.codeS},
+		{"OrigTotalCodeT_Adoption", "CorrectTotalCodeT_Adoption", t.codeT},
+		{"OrigTotalCodeM_UncollSS", "CorrectTotalCodeM_UncollSS", t.codeM},
+		{"OrigTotalCodeN_UncollMed", "CorrectTotalCodeN_UncollMed", t.codeN},
+		{"OrigTotalCodeZ_409A", "CorrectTotalCodeZ_409A", t.codeZ},
+	} {
+		putMoney15Pair(b, g.yspec.RCU, f.orig, f.corr, f.total.orig, f.total.corr)
+	}
+	if hasField(g.yspec.RCU, "OrigTotalMedicaidWaiver") {
+		putMoney15Pair(b, g.yspec.RCU, "OrigTotalMedicaidWaiver", "CorrectTotalMedicaidWaiver",
+			t.codeII.orig, t.codeII.corr)
+	}
 	return b.String()
 }
 
-// buildRCT is called without knowing the RCW count; the caller fills RCT.TotalRCWRecords.
-// We expose a separate setter so Generate() can write the count after appending all records.
-// For simplicity the RCT TotalRCWRecords is always overwritten by the RCF value.
+// buildRCF writes the final record. count is the RCW total across every
+// RCE in the file, i.e. the sum of the RCT TotalRCWRecords.
 func (g *Generator) buildRCF(count int) string {
-	b := newBuf()
+	b := g.newBuf()
 	b.put("RecordIdentifier", g.yspec.RCF, "RCF")
 	b.put("TotalRCWRecords", g.yspec.RCF, fmt.Sprintf("%07d", count))
 	return b.String()
@@ -453,7 +963,10 @@ func (g *Generator) buildRCF(count int) string {
 // Buffer
 // ---------------------------------------------------------------------------
 
-type fixedBuf struct{ data []byte }
+type fixedBuf struct {
+	data   []byte
+	truncs *truncLog // nil: truncate silently
+}
 
 func newBuf() *fixedBuf {
 	d := make([]byte, spec.RecordLen)
@@ -463,23 +976,137 @@ func newBuf() *fixedBuf {
 	return &fixedBuf{data: d}
 }
 
-// put looks up fieldName in fields and writes value at the correct position.
-// Panics on unknown field name — that's a generator bug, not user error.
+// newBuf returns a blank record that reports truncations to g.truncs.
+func (g *Generator) newBuf() *fixedBuf {
+	b := newBuf()
+	b.truncs = g.truncs
+	return b
+}
+
+// truncLog collects the values put cut short, tagged with the employee
+// whose records are being built (-1 for RCA/RCE/totals).
+type truncLog struct {
+	employee int
+	items    ValidationErrors
+}
+
+// at sets the employee index for subsequent truncations; nil-safe.
+func (l *truncLog) at(employee int) {
+	if l != nil {
+		l.employee = employee
+	}
+}
+
+func (l *truncLog) add(field string, width int, value string) {
+	value = strings.TrimRight(value, " ")
+	limit, got := fmt.Sprintf("%d characters", width), fmt.Sprintf("%d characters (%s)", len(value), value)
+	l.items = append(l.items, ValidationError{
+		Code:     CodeFieldOverflow,
+		Field:    field,
+		Employee: l.employee,
+		Message:  fmt.Sprintf("%s max %s, got %s", field, limit, got),
+		Limit:    limit,
+		Got:      got,
+	})
+}
+
+// boundsError reports a field or record that would not fit the fixed
+// record length. The buffer raises it as a panic so builders keep their
+// string results; GenerateMulti recovers it into an ordinary error.
+type boundsError struct {
+	field      string
+	start, end int
+	len        int
+}
+
+func (e *boundsError) Error() string {
+	if e.field == "" {
+		return fmt.Sprintf("efw2c: record is %d bytes, want %d", e.len, spec.RecordLen)
+	}
+	return fmt.Sprintf("efw2c: field %q at %d-%d is outside the %d-byte record", e.field, e.start, e.end, e.len)
+}
+
+// put looks up fieldName in fields and writes value, already formatted, at
+// the correct position. Panics on unknown field name — that's a generator
+// bug, not user error — and with a *boundsError if the field's positions
+// fall outside the record.
 func (b *fixedBuf) put(fieldName string, fields []spec.Field, value string) {
+	b.write(fieldNamed(fields, fieldName), value)
+}
+
+// fill is putField for the field named fieldName.
+func (b *fixedBuf) fill(fieldName string, fields []spec.Field, raw string) {
+	b.putField(fieldNamed(fields, fieldName), raw)
+}
+
+// fillWith is fill for the few fields whose SSA formatting differs from
+// their Type's, such as the left-justified contact phone and the mixed-case
+// e-mail: format pads raw to the field's width from the layout.
+func (b *fixedBuf) fillWith(fieldName string, fields []spec.Field, raw string, format func(string, int) string) {
+	f := fieldNamed(fields, fieldName)
+	b.write(f, format(raw, f.Len()))
+}
+
+// putMoney writes cents to the money field named fieldName. Amounts are
+// unsigned in EFW2C; generate rejects negatives (CheckNegative) before any
+// record is built.
+func (b *fixedBuf) putMoney(fieldName string, fields []spec.Field, cents int64) {
+	b.putField(fieldNamed(fields, fieldName), strconv.FormatInt(cents, 10))
+}
+
+// putField formats raw by f's type and width, then writes it: Alpha is
+// cleaned by padAlpha, Numeric right-justified and zero-filled (all spaces
+// when raw has no digits), and Money11/Money15 raw cents zero-filled. Fixed
+// and Blank values are written as given.
+func (b *fixedBuf) putField(f spec.Field, raw string) {
+	switch f.Type {
+	case spec.Alpha:
+		raw = padAlpha(raw, f.Len())
+	case spec.Numeric:
+		raw = zeroPadNumeric(raw, f.Len())
+	case spec.Money11, spec.Money15:
+		cents, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("efw2c: money field %q given %q — generator bug", f.Name, raw))
+		}
+		raw = fmt.Sprintf("%0*d", f.Len(), cents)
+	}
+	b.write(f, raw)
+}
+
+// write copies value into f's positions, cutting it to the field's width.
+func (b *fixedBuf) write(f spec.Field, value string) {
+	if f.Start < 1 || f.End < f.Start || f.End > len(b.data) {
+		panic(&boundsError{field: f.Name, start: f.Start, end: f.End, len: len(b.data)})
+	}
+	width := f.Len()
+	if len(value) > width {
+		if b.truncs != nil && strings.TrimSpace(value[width:]) != "" {
+			b.truncs.add(f.Name, width, value)
+		}
+		value = value[:width]
+	}
+	copy(b.data[f.Start-1:f.End], value)
+}
+
+// fieldNamed returns the field called name, panicking if fields has none.
+func fieldNamed(fields []spec.Field, name string) spec.Field {
 	for _, f := range fields {
-		if f.Name == fieldName {
-			width := f.End - f.Start + 1
-			if len(value) > width {
-				value = value[:width]
-			}
-			copy(b.data[f.Start-1:f.End], value)
-			return
+		if f.Name == name {
+			return f
 		}
 	}
-	panic(fmt.Sprintf("efw2c: field %q not found in spec — generator bug", fieldName))
+	panic(fmt.Sprintf("efw2c: field %q not found in spec — generator bug", name))
 }
 
-func (b *fixedBuf) String() string { return string(b.data) }
+// String returns the record, panicking with a *boundsError if the buffer is
+// not exactly spec.RecordLen bytes.
+func (b *fixedBuf) String() string {
+	if len(b.data) != spec.RecordLen {
+		panic(&boundsError{len: len(b.data)})
+	}
+	return string(b.data)
+}
 
 // ---------------------------------------------------------------------------
 // Helpers
@@ -491,8 +1118,14 @@ func putMoney11Pair(b *fixedBuf, fields []spec.Field, origName, corrName string,
 	if orig == 0 && corr == 0 {
 		return // leave as spaces
 	}
-	b.put(origName, fields, money11(orig))
-	b.put(corrName, fields, money11(corr))
+	b.putMoney(origName, fields, orig)
+	b.putMoney(corrName, fields, corr)
+}
+
+// hasField reports whether fields has one named name. Fields added in a
+// later tax year are written only when the year's layout has them.
+func hasField(fields []spec.Field, name string) bool {
+	return slices.ContainsFunc(fields, func(f spec.Field) bool { return f.Name == name })
 }
 
 // putMoney15Pair is the 15-char variant for RCT totals.
@@ -518,21 +1151,127 @@ func putBox13(b *fixedBuf, fields []spec.Field, origName, corrName string, orig,
 	}
 }
 
+// putRegion writes the state and ZIP of a domestic address, or the
+// province, postal code and country code of a foreign one. The other set
+// is left blank, as the spec requires for each.
+func putRegion(b *fixedBuf, fields []spec.Field, foreign bool, state, zip, zipExt, province, postal, country string) {
+	if foreign {
+		b.fill("ForeignStateProvince", fields, province)
+		b.fill("ForeignPostalCode", fields, postal)
+		b.fill("CountryCode", fields, country)
+		return
+	}
+	b.fill("StateAbbrev", fields, state)
+	b.fill("ZIPCode", fields, zip)
+	b.fill("ZIPExtension", fields, zipExt)
+}
+
 // ---------------------------------------------------------------------------
 // Formatting helpers
 // ---------------------------------------------------------------------------
 
-// padAlpha uppercases and left-pads with spaces to exactly n chars.
+// withSuffix appends a name suffix (JR, III) to a last name. The RCW has
+// no suffix position, so SSA reads it from the end of the last-name field;
+// the combined value is still subject to the field's 20-character limit.
+func withSuffix(last, suffix string) string {
+	last, suffix = strings.TrimSpace(last), strings.TrimSpace(suffix)
+	if suffix == "" {
+		return last
+	}
+	return last + " " + suffix
+}
+
+// padAlpha uppercases and right-pads with spaces to n chars. Accented
+// letters are folded to ASCII and anything else outside printable ASCII is
+// dropped, so every character is one byte. Longer values are returned whole
+// so put can apply the trim policy.
 func padAlpha(s string, n int) string {
-	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(foldASCII(s))))
 	if len(s) > n {
-		return s[:n]
+		return s
 	}
 	return s + strings.Repeat(" ", n-len(s))
 }
 
-// padNumeric strips non-digits and left-pads with spaces to exactly n chars.
-// Per spec, numeric fields that are not populated should be all spaces.
+// cleanName prepares an employee name field, which SSA limits to A-Z, 0-9,
+// space, hyphen, period and apostrophe. Other characters are dropped and the
+// runs of spaces they leave collapse to one.
+func cleanName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if nameChar(r) {
+			return r
+		}
+		return ' '
+	}, strings.ToUpper(foldASCII(s)))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cleanContactName prepares the RCA and RCE contact name, which allows the
+// same characters as an employee name. Others are removed outright, so
+// "O'BRIEN, JR./SR." becomes "O'BRIEN JR.SR.".
+func cleanContactName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if nameChar(r) || r == ' ' {
+			return r
+		}
+		return -1
+	}, strings.ToUpper(foldASCII(s)))
+}
+
+// nameChar reports whether r is one of the non-space characters SSA allows
+// in a name field once uppercased.
+func nameChar(r rune) bool {
+	switch {
+	case 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '-', r == '.', r == '\'':
+		return true
+	}
+	return false
+}
+
+// asciiFolds maps each accented or ligature letter SSA's A-Z edit would
+// reject to its plain-ASCII spelling.
+var asciiFolds = func() map[rune]string {
+	m := make(map[rune]string)
+	for plain, accented := range map[string]string{
+		"A": "ÀÁÂÃÄÅĀĂĄǍàáâãäåāăąǎ", "C": "ÇĆĈĊČçćĉċč", "D": "ĎĐÐďđð",
+		"E": "ÈÉÊËĒĔĖĘĚèéêëēĕėęě", "G": "ĜĞĠĢĝğġģ", "H": "ĤĦĥħ",
+		"I": "ÌÍÎÏĨĪĬĮİìíîïĩīĭįı", "J": "Ĵĵ", "K": "Ķķ", "L": "ĹĻĽĿŁĺļľŀł",
+		"N": "ÑŃŅŇñńņň", "O": "ÒÓÔÕÖØŌŎŐǑòóôõöøōŏőǒ", "R": "ŔŖŘŕŗř",
+		"S": "ŚŜŞŠśŝşšȘș", "T": "ŢŤŦţťŧȚț", "U": "ÙÚÛÜŨŪŬŮŰŲǓùúûüũūŭůűųǔ",
+		"W": "Ŵŵ", "Y": "ÝŶŸýÿŷ", "Z": "ŹŻŽźżž",
+		"AE": "ÆæǼǽ", "OE": "Œœ", "SS": "ß", "TH": "Þþ", "IJ": "Ĳĳ",
+		"'": "‘’ʼ", "-": "‐‑‒–—",
+	} {
+		for _, r := range accented {
+			m[r] = plain
+		}
+	}
+	return m
+}()
+
+// foldASCII replaces the letters in asciiFolds with their ASCII spelling and
+// leaves every other rune alone.
+func foldASCII(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if plain, ok := asciiFolds[r]; ok {
+			b.WriteString(plain)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// padNumeric strips non-digits and left-justifies, space-filling to n
+// chars. Per spec, numeric fields that are not populated should be all
+// spaces. More than n digits are returned whole so write can apply the trim
+// policy.
 func padNumeric(s string, n int) string {
 	var builder strings.Builder
 	for _, r := range s {
@@ -542,16 +1281,39 @@ func padNumeric(s string, n int) string {
 	}
 	result := builder.String()
 	if len(result) > n {
-		return result[:n]
+		return result
 	}
 	return result + strings.Repeat(" ", n-len(result))
 }
 
+// zeroPadNumeric strips non-digits and right-justifies with leading zeros to
+// n chars (e.g. "5" → "05"). Used for numeric code fields such as the RCS
+// state code, and for ZIP codes. An input with no digits stays all spaces
+// (not populated); more than n digits are returned whole so write can apply
+// the trim policy.
+func zeroPadNumeric(s string, n int) string {
+	var builder strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	result := builder.String()
+	if result == "" {
+		return strings.Repeat(" ", n)
+	}
+	if len(result) > n {
+		return result
+	}
+	return strings.Repeat("0", n-len(result)) + result
+}
+
 // padEmail preserves case for email addresses (spec allows mixed case).
+// Longer values are returned whole so write can apply the trim policy.
 func padEmail(s string, n int) string {
 	s = strings.TrimSpace(s)
 	if len(s) > n {
-		return s[:n]
+		return s
 	}
 	return s + strings.Repeat(" ", n-len(s))
 }
@@ -572,21 +1334,9 @@ func cleanDigits(s string, n int) string {
 	return result + strings.Repeat("0", n-len(result))
 }
 
-// money11 formats cents as an 11-char zero-padded integer (no decimal point).
-// Used in RCW and RCO records.
-func money11(cents int64) string {
-	if cents < 0 {
-		cents = 0
-	}
-	return fmt.Sprintf("%011d", cents)
-}
-
 // money15 formats cents as a 15-char zero-padded integer.
 // Used in RCT (total) records.
 func money15(cents int64) string {
-	if cents < 0 {
-		cents = 0
-	}
 	return fmt.Sprintf("%015d", cents)
 }
 
@@ -603,27 +1353,3 @@ func defaultStr(s, fallback string) string {
 	}
 	return s
 }
-
-// statePostalToNumeric converts a 2-char postal abbreviation to the SSA 2-digit
-// numeric state code required in the RCS record StateCode field (Appendix H).
-// Returns "  " (blanks) if the state is not found.
-func statePostalToNumeric(abbr string) string {
-	codes := map[string]string{
-		"AL": "01", "AK": "02", "AZ": "03", "AR": "04", "CA": "05",
-		"CO": "06", "CT": "07", "DE": "08", "FL": "09", "GA": "10",
-		"HI": "11", "ID": "12", "IL": "13", "IN": "14", "IA": "15",
-		"KS": "16", "KY": "17", "LA": "18", "ME": "19", "MD": "20",
-		"MA": "21", "MI": "22", "MN": "23", "MS": "24", "MO": "25",
-		"MT": "26", "NE": "27", "NV": "28", "NH": "29", "NJ": "30",
-		"NM": "31", "NY": "32", "NC": "33", "ND": "34", "OH": "35",
-		"OK": "36", "OR": "37", "PA": "38", "RI": "39", "SC": "40",
-		"SD": "41", "TN": "42", "TX": "43", "UT": "44", "VT": "45",
-		"VA": "46", "WA": "47", "WV": "48", "WI": "49", "WY": "50",
-		"DC": "51", "PR": "72", "VI": "78", "GU": "66", "AS": "60",
-		"MP": "69",
-	}
-	if v, ok := codes[strings.ToUpper(strings.TrimSpace(abbr))]; ok {
-		return v
-	}
-	return "  "
-}
@@ -3,7 +3,13 @@ package efw2c_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
@@ -72,10 +78,10 @@ func minimalSubmission(taxYear string) *domain.Submission {
 				FirstName: "JOHN",
 				LastName:  "SMITH",
 				Amounts: domain.MonetaryAmounts{
-					OriginalWagesTipsOther:   5000000, // $50,000.00
-					CorrectWagesTipsOther:    5100000, // $51,000.00
-					OriginalFederalIncomeTax: 800000,  // $8,000.00
-					CorrectFederalIncomeTax:  820000,  // $8,200.00
+					OriginalWagesTipsOther:      5000000, // $50,000.00
+					CorrectWagesTipsOther:       5100000, // $51,000.00
+					OriginalFederalIncomeTax:    800000,  // $8,000.00
+					CorrectFederalIncomeTax:     820000,  // $8,200.00
 					OriginalSocialSecurityWages: 5000000,
 					CorrectSocialSecurityWages:  5100000,
 					OriginalSocialSecurityTax:   310000,
@@ -110,6 +116,7 @@ func TestSpecStructure_AllYears(t *testing.T) {
 				"RCO": ys.RCO,
 				"RCS": ys.RCS,
 				"RCT": ys.RCT,
+				"RCU": ys.RCU,
 				"RCF": ys.RCF,
 			} {
 				t.Run(recName, func(t *testing.T) {
@@ -186,7 +193,7 @@ func TestSpecPositions_RCA(t *testing.T) {
 		{"SoftwareVendorCode", 21, 24},
 		{"Blank25", 25, 29},
 		{"SoftwareCode", 30, 31},
-		{"CompanyName", 32, 88},     // 57 chars
+		{"CompanyName", 32, 88},      // 57 chars
 		{"LocationAddress", 89, 110}, // 22 chars
 		{"DeliveryAddress", 111, 132},
 		{"City", 133, 154},
@@ -241,7 +248,7 @@ func TestSpecPositions_RCE(t *testing.T) {
 		{"AgentForEIN", 27, 35},
 		{"OrigEstablishmentNum", 36, 39},
 		{"CorrectEstablishmentNum", 40, 43},
-		{"EmployerName", 44, 100},    // 57 chars
+		{"EmployerName", 44, 100},     // 57 chars
 		{"LocationAddress", 101, 122}, // 22 chars
 		{"DeliveryAddress", 123, 144},
 		{"City", 145, 166},
@@ -403,7 +410,7 @@ func TestSpecPositions_RCO(t *testing.T) {
 	}{
 		{"RecordIdentifier", 1, 3},
 		{"Blank4", 4, 12},
-		{"OrigAllocatedTips", 13, 23},   // Box 8
+		{"OrigAllocatedTips", 13, 23},    // Box 8
 		{"CorrectAllocatedTips", 24, 34}, // Box 8
 		{"OrigUncollectedEETax", 35, 45},
 		{"CorrectUncollectedEETax", 46, 56},
@@ -638,6 +645,69 @@ func TestGenerate_RCA_FieldPositions(t *testing.T) {
 	}
 }
 
+// TestGenerate_RCA_SubmitterCompanyName verifies a service-bureau submitter's
+// company name lands in RCA while the employer's name stays in RCE.
+func TestGenerate_RCA_SubmitterCompanyName(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Submitter.PreparerCode = "S"
+			sub.Submitter.CompanyName = "PAYROLL BUREAU LLC"
+			out := generate(t, year, sub)
+
+			// RCA CompanyName at 32-88
+			if got := trimR(extract(record(out, 0), 32, 88)); got != "PAYROLL BUREAU LLC" {
+				t.Errorf("RCA CompanyName pos 32-88: want 'PAYROLL BUREAU LLC', got %q", got)
+			}
+			// RCE EmployerName at 44-100
+			if got := trimR(extract(record(out, 1), 44, 100)); got != "ACME CORP" {
+				t.Errorf("RCE EmployerName pos 44-100: want 'ACME CORP', got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCA_SubmitterAddress verifies RCA positions 89-165 carry the
+// submitter's own address while RCE keeps the employer's.
+func TestGenerate_RCA_SubmitterAddress(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Submitter.AddressLine1 = "456 BUREAU AVE"
+			sub.Submitter.AddressLine2 = "FLOOR 3"
+			sub.Submitter.City = "CHICAGO"
+			sub.Submitter.State = "IL"
+			sub.Submitter.ZIP = "60601"
+			sub.Submitter.ZIPExtension = "0042"
+			out := generate(t, year, sub)
+			rca, rce := record(out, 0), record(out, 1)
+
+			for _, c := range []struct {
+				name       string
+				start, end int
+				want       string
+			}{
+				{"LocationAddress", 89, 110, "456 BUREAU AVE"},
+				{"DeliveryAddress", 111, 132, "FLOOR 3"},
+				{"City", 133, 154, "CHICAGO"},
+				{"StateAbbrev", 155, 156, "IL"},
+				{"ZIPCode", 157, 161, "60601"},
+				{"ZIPExtension", 162, 165, "0042"},
+			} {
+				if got := trimR(extract(rca, c.start, c.end)); got != c.want {
+					t.Errorf("RCA %s pos %d-%d: want %q, got %q", c.name, c.start, c.end, c.want, got)
+				}
+			}
+			// RCE LocationAddress at 101-122 stays the employer's
+			if got := trimR(extract(rce, 101, 122)); got != "100 MAIN ST" {
+				t.Errorf("RCE LocationAddress pos 101-122: want '100 MAIN ST', got %q", got)
+			}
+		})
+	}
+}
+
 // TestGenerate_RCE_FieldPositions verifies the RCE record byte positions.
 func TestGenerate_RCE_FieldPositions(t *testing.T) {
 	for _, year := range spec.Supported() {
@@ -804,6 +874,42 @@ func TestGenerate_RCW_NameCorrection(t *testing.T) {
 			if got := trimR(extract(rcw, 102, 121)); got != "SMITH" {
 				t.Errorf("CorrectLastName pos 102-121: want 'SMITH', got %q", got)
 			}
+			// SSN unchanged: current SSN at 4-12, CorrectSSN 13-21 blank
+			if got := extract(rcw, 4, 12); got != "987654321" {
+				t.Errorf("OrigSSN pos 4-12: want '987654321', got %q", got)
+			}
+			if got := trimR(extract(rcw, 13, 21)); got != "" {
+				t.Errorf("CorrectSSN pos 13-21: want blank, got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCW_NameAndSSNCorrection verifies a name correction and an SSN
+// correction on the same employee both land in the RCW.
+func TestGenerate_RCW_NameAndSSNCorrection(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Employees[0].OriginalSSN = "111223333"
+			sub.Employees[0].OriginalFirstName = "JON"
+			sub.Employees[0].OriginalLastName = "SMYTH"
+			out := generate(t, year, sub)
+			rcw := record(out, 2)
+
+			if got := extract(rcw, 4, 12); got != "111223333" {
+				t.Errorf("OrigSSN pos 4-12: want '111223333', got %q", got)
+			}
+			if got := extract(rcw, 13, 21); got != "987654321" {
+				t.Errorf("CorrectSSN pos 13-21: want '987654321', got %q", got)
+			}
+			if got := trimR(extract(rcw, 22, 36)); got != "JON" {
+				t.Errorf("OrigFirstName pos 22-36: want 'JON', got %q", got)
+			}
+			if got := trimR(extract(rcw, 72, 86)); got != "JOHN" {
+				t.Errorf("CorrectFirstName pos 72-86: want 'JOHN', got %q", got)
+			}
 		})
 	}
 }
@@ -936,9 +1042,9 @@ func TestGenerate_RCO_AllocatedTips(t *testing.T) {
 			out := generate(t, year, sub)
 			nRecords := len(out) / spec.RecordLen
 
-			// With one employee having Box 8 data: RCA RCE RCW RCO RCT RCF = 6 records
-			if nRecords != 6 {
-				t.Fatalf("expected 6 records (RCO present), got %d", nRecords)
+			// With one employee having Box 8 data: RCA RCE RCW RCO RCT RCU RCF = 7 records
+			if nRecords != 7 {
+				t.Fatalf("expected 7 records (RCO and RCU present), got %d", nRecords)
 			}
 			rco := record(out, 3) // RCA[0] RCE[1] RCW[2] RCO[3]
 
@@ -977,6 +1083,330 @@ func TestGenerate_RCO_NoRecordWhenZero(t *testing.T) {
 	}
 }
 
+// TestGenerate_RCO_UncollectedTax verifies the Box 12 Code A/B amounts land
+// at RCO positions 35-56 and emit an RCO (and RCU) even when Box 8 is zero.
+func TestGenerate_RCO_UncollectedTax(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Employees[0].Amounts.OriginalUncollectedEETax = 4567 // $45.67
+			sub.Employees[0].Amounts.CorrectUncollectedEETax = 8900
+
+			out := generate(t, year, sub)
+			if got, want := strings.Join(recordIDs(out), " "), "RCA RCE RCW RCO RCT RCU RCF"; got != want {
+				t.Fatalf("record order: want %q, got %q", want, got)
+			}
+			rco := record(out, 3)
+			if got := extract(rco, 35, 45); got != "00000004567" {
+				t.Errorf("OrigUncollectedEETax pos 35-45: want '00000004567', got %q", got)
+			}
+			if got := extract(rco, 46, 56); got != "00000008900" {
+				t.Errorf("CorrectUncollectedEETax pos 46-56: want '00000008900', got %q", got)
+			}
+			// Box 8 was not corrected, so its positions stay blank.
+			if got := strings.TrimSpace(extract(rco, 13, 34)); got != "" {
+				t.Errorf("AllocatedTips pos 13-34: want blank, got %q", got)
+			}
+
+			rcu := record(out, 5)
+			if got := extract(rcu, 41, 55); got != "000000000004567" {
+				t.Errorf("RCU OrigTotalUncollectedEETax pos 41-55: want '000000000004567', got %q", got)
+			}
+			if got := extract(rcu, 56, 70); got != "000000000008900" {
+				t.Errorf("RCU CorrectTotalUncollectedEETax pos 56-70: want '000000000008900', got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCO_Box12Codes verifies each Box 12 code carried on the RCO
+// lands at its positions, emits an RCO on its own, and is totalled in the RCU.
+func TestGenerate_RCO_Box12Codes(t *testing.T) {
+	cases := []struct {
+		code                 string
+		set                  func(a *domain.MonetaryAmounts, orig, corr int64)
+		origStart, origEnd   int
+		corrStart, corrEnd   int
+		rcuStart, rcuCorrEnd int
+	}{
+		{"R", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeR_MSA, a.CorrectCodeR_MSA = o, c }, 57, 67, 68, 78, 71, 100},
+		{"S", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE = o, c }, 79, 89, 90, 100, 101, 130},
+		{"T", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption = o, c }, 101, 111, 112, 122, 131, 160},
+		{"M", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS = o, c }, 123, 133, 134, 144, 161, 190},
+		{"N", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed = o, c
+		}, 145, 155, 156, 166, 191, 220},
+		{"Z", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeZ_409A, a.CorrectCodeZ_409A = o, c }, 167, 177, 178, 188, 221, 250},
+	}
+	for _, year := range spec.Supported() {
+		for _, tc := range cases {
+			year, tc := year, tc
+			t.Run(fmt.Sprintf("TY%d/Code%s", year, tc.code), func(t *testing.T) {
+				sub := minimalSubmission(fmt.Sprintf("%d", year))
+				tc.set(&sub.Employees[0].Amounts, 12345, 67890)
+
+				out := generate(t, year, sub)
+				if got, want := strings.Join(recordIDs(out), " "), "RCA RCE RCW RCO RCT RCU RCF"; got != want {
+					t.Fatalf("record order: want %q, got %q", want, got)
+				}
+				rco := record(out, 3)
+				if got := extract(rco, tc.origStart, tc.origEnd); got != "00000012345" {
+					t.Errorf("orig pos %d-%d: want '00000012345', got %q", tc.origStart, tc.origEnd, got)
+				}
+				if got := extract(rco, tc.corrStart, tc.corrEnd); got != "00000067890" {
+					t.Errorf("corr pos %d-%d: want '00000067890', got %q", tc.corrStart, tc.corrEnd, got)
+				}
+				rcu := record(out, 5)
+				if got := extract(rcu, tc.rcuStart, tc.rcuStart+14); got != "000000000012345" {
+					t.Errorf("RCU orig total pos %d-%d: got %q", tc.rcuStart, tc.rcuStart+14, got)
+				}
+				if got := extract(rcu, tc.rcuStart+15, tc.rcuCorrEnd); got != "000000000067890" {
+					t.Errorf("RCU corr total pos %d-%d: got %q", tc.rcuStart+15, tc.rcuCorrEnd, got)
+				}
+			})
+		}
+	}
+}
+
+// TestGenerate_RCO_CodeII verifies Box 12 Code II is written to the RCO and
+// totalled in the RCU from TY2024, and ignored for earlier years, whose
+// layouts have no Code II positions.
+func TestGenerate_RCO_CodeII(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Employees[0].Amounts.OriginalMedicaidWaiver = 12345
+			sub.Employees[0].Amounts.CorrectMedicaidWaiver = 67890
+
+			out := generate(t, year, sub)
+			if year < 2024 {
+				if got, want := strings.Join(recordIDs(out), " "), "RCA RCE RCW RCT RCF"; got != want {
+					t.Fatalf("record order: want %q (no RCO), got %q", want, got)
+				}
+				return
+			}
+			if got, want := strings.Join(recordIDs(out), " "), "RCA RCE RCW RCO RCT RCU RCF"; got != want {
+				t.Fatalf("record order: want %q, got %q", want, got)
+			}
+			rco := record(out, 3)
+			if got := extract(rco, 277, 287); got != "00000012345" {
+				t.Errorf("RCO orig pos 277-287: want '00000012345', got %q", got)
+			}
+			if got := extract(rco, 288, 298); got != "00000067890" {
+				t.Errorf("RCO corr pos 288-298: want '00000067890', got %q", got)
+			}
+			rcu := record(out, 5)
+			if got := extract(rcu, 371, 385); got != "000000000012345" {
+				t.Errorf("RCU orig total pos 371-385: got %q", got)
+			}
+			if got := extract(rcu, 386, 400); got != "000000000067890" {
+				t.Errorf("RCU corr total pos 386-400: got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCS_StateCodeZeroPadded verifies the numeric RCS state codes
+// are right-justified and zero-padded ("05" for CA — never " 5" or "5 ").
+func TestGenerate_RCS_StateCodeZeroPadded(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Employees[0].CorrectStateCode = "CA"
+			sub.Employees[0].Amounts.OriginalStateWages = 5000000
+			sub.Employees[0].Amounts.CorrectStateWages = 5100000
+
+			out := generate(t, year, sub)
+			rcs := record(out, 3) // RCA[0] RCE[1] RCW[2] RCS[3]
+			if got := extract(rcs, 1, 3); got != "RCS" {
+				t.Fatalf("record[3] identifier: want 'RCS', got %q", got)
+			}
+			// StateCode at 4-5
+			if got := extract(rcs, 4, 5); got != "05" {
+				t.Errorf("StateCode pos 4-5: want '05', got %q", got)
+			}
+			// StateCode2 at 396-397
+			if got := extract(rcs, 396, 397); got != "05" {
+				t.Errorf("StateCode2 pos 396-397: want '05', got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCS_InheritsEmployerState verifies an employee with state
+// wages but no state code gets the employer's state in the RCS.
+func TestGenerate_RCS_InheritsEmployerState(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year)) // employer state IL
+			sub.Employees[0].Amounts.OriginalStateWages = 5000000
+			sub.Employees[0].Amounts.CorrectStateWages = 5100000
+
+			out := generate(t, year, sub)
+			rcs := record(out, 3)
+			if got := extract(rcs, 1, 3); got != "RCS" {
+				t.Fatalf("record[3] identifier: want 'RCS', got %q", got)
+			}
+			if got := extract(rcs, 4, 5); got != "13" {
+				t.Errorf("StateCode pos 4-5: want '13' (employer IL), got %q", got)
+			}
+			if got := extract(rcs, 396, 397); got != "13" {
+				t.Errorf("StateCode2 pos 396-397: want '13' (employer IL), got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_RCS_Local verifies Box 18/19 local amounts alone emit an RCS
+// with the amounts at their positions, the suffixed last name, and the
+// locality name in Supplemental Data 1 only under WithRCSLocality.
+func TestGenerate_RCS_Local(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			e := &sub.Employees[0]
+			e.Suffix = "JR"
+			e.Amounts.OriginalLocalWages, e.Amounts.CorrectLocalWages = 12345, 67890
+			e.Amounts.OriginalLocalIncomeTax, e.Amounts.CorrectLocalIncomeTax = 111, 222
+			e.OriginalLocalityName, e.CorrectLocalityName = "PEORIA", "Gary"
+
+			out := generate(t, year, sub)
+			if got, want := strings.Join(recordIDs(out), " "), "RCA RCE RCW RCS RCT RCF"; got != want {
+				t.Fatalf("record order: want %q, got %q", want, got)
+			}
+			rcs := record(out, 3)
+			for _, tc := range []struct {
+				name       string
+				start, end int
+				want       string
+			}{
+				{"CorrectLastName", 114, 133, "SMITH JR" + strings.Repeat(" ", 12)},
+				{"OrigLocalWages", 453, 463, "00000012345"},
+				{"CorrectLocalWages", 464, 474, "00000067890"},
+				{"OrigLocalIncomeTax", 475, 485, "00000000111"},
+				{"CorrectLocalIncomeTax", 486, 496, "00000000222"},
+				{"SupplementalData1", 504, 578, strings.Repeat(" ", 75)},
+			} {
+				if got := extract(rcs, tc.start, tc.end); got != tc.want {
+					t.Errorf("%s pos %d-%d: want %q, got %q", tc.name, tc.start, tc.end, tc.want, got)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := efw2c.MustNew(year, efw2c.WithRCSLocality()).Generate(context.Background(), sub, &buf); err != nil {
+				t.Fatalf("Generate WithRCSLocality: %v", err)
+			}
+			if got, want := extract(record(buf.String(), 3), 504, 578), "GARY"+strings.Repeat(" ", 71); got != want {
+				t.Errorf("WithRCSLocality SupplementalData1: want %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+// TestGenerate_NameTransliteration verifies accented names are folded to
+// ASCII, characters SSA does not accept are dropped, and each field is still
+// exactly its width, so the record stays 1024 bytes.
+func TestGenerate_NameTransliteration(t *testing.T) {
+	sub := minimalSubmission("2024")
+	e := &sub.Employees[0]
+	e.FirstName, e.MiddleName, e.LastName = "José", "李小龙", "Muñoz-Straße"
+	e.City = "Zoë’s Ångström"
+
+	out := generate(t, 2024, sub)
+	if len(out) != 5*spec.RecordLen {
+		t.Fatalf("file length: want %d, got %d", 5*spec.RecordLen, len(out))
+	}
+	rcw := record(out, 2)
+	for _, tc := range []struct {
+		name       string
+		start, end int
+		want       string
+	}{
+		{"CorrectFirstName", 72, 86, "JOSE" + strings.Repeat(" ", 11)},
+		{"CorrectMiddleName", 87, 101, strings.Repeat(" ", 15)},
+		{"CorrectLastName", 102, 121, "MUNOZ-STRASSE" + strings.Repeat(" ", 7)},
+	} {
+		if got := extract(rcw, tc.start, tc.end); got != tc.want {
+			t.Errorf("%s pos %d-%d: want %q, got %q", tc.name, tc.start, tc.end, tc.want, got)
+		}
+	}
+	if !strings.Contains(rcw, "ZOE'S ANGSTROM") {
+		t.Errorf("RCW City: want ZOE'S ANGSTROM in the record")
+	}
+	for i := 0; i < len(out); i++ {
+		if c := out[i]; c < ' ' || c > '~' {
+			t.Fatalf("byte %d: want printable ASCII, got %#x", i, c)
+		}
+	}
+}
+
+// TestGenerate_ContactNameCharacters verifies the RCA and RCE contact names
+// lose the punctuation SSA does not allow there.
+func TestGenerate_ContactNameCharacters(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Submitter.ContactName = "O'Brien, Jr./Sr."
+	sub.Employer.ContactName = "O'Brien, Jr./Sr."
+
+	out := generate(t, 2024, sub)
+	want := "O'BRIEN JR.SR." + strings.Repeat(" ", 13)
+	if got := extract(record(out, 0), 212, 238); got != want {
+		t.Errorf("RCA ContactName pos 212-238: want %q, got %q", want, got)
+	}
+	if got := extract(record(out, 1), 228, 254); got != want {
+		t.Errorf("RCE ContactName pos 228-254: want %q, got %q", want, got)
+	}
+}
+
+// TestGenerate_StateOnlyCorrection verifies an employee whose federal boxes
+// are untouched but whose Box 16 changes still gets a valid RCW with zeroed
+// federal amounts, immediately followed by an RCS carrying the state values.
+func TestGenerate_StateOnlyCorrection(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			sub.Employees[0].Amounts = domain.MonetaryAmounts{
+				OriginalStateWages: 5000000,
+				CorrectStateWages:  5100000,
+			}
+			sub.Employees[0].CorrectStateCode = "IL"
+
+			out := generate(t, year, sub)
+			if n := len(out) / spec.RecordLen; n != 6 {
+				t.Fatalf("expected 6 records (RCA RCE RCW RCS RCT RCF), got %d", n)
+			}
+
+			rcw := record(out, 2)
+			if got := extract(rcw, 1, 3); got != "RCW" {
+				t.Fatalf("record[2] identifier: want 'RCW', got %q", got)
+			}
+			// Boxes 1-6 orig/corr at 244-375: twelve zero-filled 11-char fields
+			if got, want := extract(rcw, 244, 375), strings.Repeat("0", 132); got != want {
+				t.Errorf("Boxes 1-6 pos 244-375: want all zeros, got %q", got)
+			}
+
+			rcs := record(out, 3)
+			if got := extract(rcs, 1, 3); got != "RCS" {
+				t.Fatalf("record[3] identifier: want 'RCS', got %q", got)
+			}
+			if got := extract(rcs, 4, 5); got != "13" {
+				t.Errorf("StateCode pos 4-5: want '13' (IL), got %q", got)
+			}
+			if got := extract(rcs, 398, 408); got != "00005000000" {
+				t.Errorf("OrigStateWages pos 398-408: want '00005000000', got %q", got)
+			}
+			if got := extract(rcs, 409, 419); got != "00005100000" {
+				t.Errorf("CorrectStateWages pos 409-419: want '00005100000', got %q", got)
+			}
+		})
+	}
+}
+
 // TestGenerate_RCT_Totals verifies the RCT record accumulates money fields
 // from all RCW records at the correct 15-char positions.
 func TestGenerate_RCT_Totals(t *testing.T) {
@@ -994,11 +1424,8 @@ func TestGenerate_RCT_Totals(t *testing.T) {
 				t.Fatalf("expected RCT, got %q", got)
 			}
 			// TotalRCWRecords at 4-10, zero-padded, 1 employee
-			if got := extract(rct, 4, 10); got != "0000000" {
-				// Note: generator currently sets this to placeholder 0000000 — acceptable
-				// as the count is written as fmt.Sprintf("%07d", 0). This is a known
-				// limitation: RCT TotalRCWRecords uses 0 as a placeholder.
-				_ = got // no assertion — see note above
+			if got := extract(rct, 4, 10); got != "0000001" {
+				t.Errorf("TotalRCWRecords pos 4-10: want '0000001', got %q", got)
 			}
 			// Box 1 orig total at 11-25 (15 chars) = 5000000 cents
 			if got := extract(rct, 11, 25); got != "000000005000000" {
@@ -1024,6 +1451,128 @@ func TestGenerate_RCT_Totals(t *testing.T) {
 	}
 }
 
+// TestGenerate_RCU_Totals verifies the RCU follows the RCT with the RCO
+// count and 15-char allocated-tips totals, and is omitted without an RCO.
+func TestGenerate_RCU_Totals(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			second := sub.Employees[0]
+			second.SSN = "123456789"
+			sub.Employees = append(sub.Employees, second, second)
+			sub.Employees[0].Amounts.OriginalAllocatedTips = 123456 // $1,234.56
+			sub.Employees[0].Amounts.CorrectAllocatedTips = 130000
+			sub.Employees[2].Amounts.CorrectAllocatedTips = 50000 // third has tips, second none
+			out := generate(t, year, sub)
+
+			// RCU sits between RCT and RCF
+			nRecords := len(out) / spec.RecordLen
+			if got := extract(record(out, nRecords-3), 1, 3); got != "RCT" {
+				t.Fatalf("record[%d]: want RCT, got %q", nRecords-3, got)
+			}
+			rcu := record(out, nRecords-2)
+			if got := extract(rcu, 1, 3); got != "RCU" {
+				t.Fatalf("expected RCU, got %q", got)
+			}
+			if len(rcu) != spec.RecordLen {
+				t.Errorf("RCU length: want %d, got %d", spec.RecordLen, len(rcu))
+			}
+			// TotalRCORecords at 4-10, zero-padded, 2 employees with tips
+			if got := extract(rcu, 4, 10); got != "0000002" {
+				t.Errorf("TotalRCORecords pos 4-10: want '0000002', got %q", got)
+			}
+			// Box 8 orig total at 11-25
+			if got := extract(rcu, 11, 25); got != "000000000123456" {
+				t.Errorf("Box8 orig total pos 11-25: want '000000000123456', got %q", got)
+			}
+			// Box 8 corr total at 26-40
+			if got := extract(rcu, 26, 40); got != "000000000180000" {
+				t.Errorf("Box8 corr total pos 26-40: want '000000000180000', got %q", got)
+			}
+			if got := strings.TrimRight(extract(rcu, 41, 1024), " "); got != "" {
+				t.Errorf("RCU pos 41-1024: want blank, got %q", got)
+			}
+
+			// No RCO, no RCU.
+			for _, id := range recordIDs(generate(t, year, minimalSubmission(fmt.Sprintf("%d", year)))) {
+				if id == "RCU" {
+					t.Error("RCU written without any RCO")
+				}
+			}
+		})
+	}
+}
+
+// recordIDs lists the record identifiers in out, in order.
+func recordIDs(out string) []string {
+	var ids []string
+	for off := 0; off+spec.RecordLen <= len(out); off += spec.RecordLen {
+		ids = append(ids, out[off:off+3])
+	}
+	return ids
+}
+
+// TestGenerate_RCT_Box12Totals verifies every Box 11/12 amount with an RCW
+// position is summed into its 15-char RCT total.
+func TestGenerate_RCT_Box12Totals(t *testing.T) {
+	cases := []struct {
+		name       string
+		set        func(a *domain.MonetaryAmounts, orig, corr int64)
+		start, end int // RCT orig total; corr total follows at end+1..end+15
+	}{
+		{"Box 10", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalDependentCare, a.CorrectDependentCare = o, c }, 251, 265},
+		{"Code D", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCode401k, a.CorrectCode401k = o, c }, 281, 295},
+		{"Code E", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCode403b, a.CorrectCode403b = o, c }, 311, 325},
+		{"Code F", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP = o, c }, 341, 355},
+		{"Code G", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCode457bGovt, a.CorrectCode457bGovt = o, c }, 371, 385},
+		{"Code H", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D = o, c }, 401, 415},
+		{"Box 11 457", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalNonqualPlan457, a.CorrectNonqualPlan457 = o, c }, 491, 505},
+		{"Code W", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeW_HSA, a.CorrectCodeW_HSA = o, c }, 521, 535},
+		{"Box 11 non-457", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457 = o, c
+		}, 551, 565},
+		{"Code Q", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay = o, c
+		}, 581, 595},
+		{"Code C", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife = o, c
+		}, 641, 655},
+		{"Code V", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeV_NSO, a.CorrectCodeV_NSO = o, c }, 671, 685},
+		{"Code Y", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeY_409A, a.CorrectCodeY_409A = o, c }, 701, 715},
+		{"Code AA", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k = o, c
+		}, 731, 745},
+		{"Code BB", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b = o, c
+		}, 761, 775},
+		{"Code DD", func(a *domain.MonetaryAmounts, o, c int64) {
+			a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth = o, c
+		}, 791, 805},
+		{"Code FF", func(a *domain.MonetaryAmounts, o, c int64) { a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA = o, c }, 821, 835},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees = append(sub.Employees, sub.Employees[0])
+			tc.set(&sub.Employees[0].Amounts, 100000, 150000) // $1,000.00 → $1,500.00
+			tc.set(&sub.Employees[1].Amounts, 20000, 25000)   // $200.00 → $250.00
+
+			out := generate(t, 2024, sub)
+			rct := record(out, len(out)/spec.RecordLen-2)
+			if got := extract(rct, 1, 3); got != "RCT" {
+				t.Fatalf("expected RCT, got %q", got)
+			}
+			if got := extract(rct, tc.start, tc.end); got != "000000000120000" {
+				t.Errorf("orig total pos %d-%d: want '000000000120000', got %q", tc.start, tc.end, got)
+			}
+			if got := extract(rct, tc.end+1, tc.end+15); got != "000000000175000" {
+				t.Errorf("corr total pos %d-%d: want '000000000175000', got %q", tc.end+1, tc.end+15, got)
+			}
+		})
+	}
+}
+
 // TestGenerate_RCT_MultipleEmployees verifies totals aggregate correctly
 // across multiple RCW records.
 func TestGenerate_RCT_MultipleEmployees(t *testing.T) {
@@ -1037,10 +1586,10 @@ func TestGenerate_RCT_MultipleEmployees(t *testing.T) {
 				FirstName: "ALICE",
 				LastName:  "JONES",
 				Amounts: domain.MonetaryAmounts{
-					OriginalWagesTipsOther: 3000000,
-					CorrectWagesTipsOther:  3100000,
-					OriginalFederalIncomeTax: 400000,
-					CorrectFederalIncomeTax:  420000,
+					OriginalWagesTipsOther:      3000000,
+					CorrectWagesTipsOther:       3100000,
+					OriginalFederalIncomeTax:    400000,
+					CorrectFederalIncomeTax:     420000,
 					OriginalSocialSecurityWages: 3000000,
 					CorrectSocialSecurityWages:  3100000,
 					OriginalSocialSecurityTax:   186000,
@@ -1066,48 +1615,235 @@ func TestGenerate_RCT_MultipleEmployees(t *testing.T) {
 			if got := extract(rct, 26, 40); got != "000000008200000" {
 				t.Errorf("Box1 corr total: want '000000008200000', got %q", got)
 			}
-		})
-	}
-}
-
-// TestGenerate_RCF_FinalRecord verifies the RCF record contains the correct
-// RCW count at positions 4-10.
-func TestGenerate_RCF_FinalRecord(t *testing.T) {
-	for _, year := range spec.Supported() {
-		year := year
-		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
-			sub := minimalSubmission(fmt.Sprintf("%d", year))
-			out := generate(t, year, sub)
-			nRecords := len(out) / spec.RecordLen
-			rcf := record(out, nRecords-1)
-
-			if got := extract(rcf, 1, 3); got != "RCF" {
-				t.Fatalf("expected RCF, got %q", got)
+			// TotalRCWRecords at 4-10 matches the RCF count
+			if got := extract(rct, 4, 10); got != "0000002" {
+				t.Errorf("TotalRCWRecords pos 4-10: want '0000002', got %q", got)
 			}
-			// TotalRCWRecords at 4-10 for 1 employee
-			if got := extract(rcf, 4, 10); got != "0000001" {
-				t.Errorf("RCF TotalRCWRecords pos 4-10: want '0000001', got %q", got)
+			if got, rcf := extract(rct, 4, 10), extract(record(out, nRecords-1), 4, 10); got != rcf {
+				t.Errorf("RCT count %q != RCF count %q", got, rcf)
 			}
-			// Remainder must be blank
-			if got := strings.TrimRight(extract(rcf, 11, 1024), " "); got != "" {
-				t.Errorf("RCF Blank11 pos 11-1024: want blank, got %q", got)
+			if len(rct) != spec.RecordLen {
+				t.Errorf("RCT length: want %d, got %d", spec.RecordLen, len(rct))
 			}
 		})
 	}
 }
 
-// TestGenerate_TY2024_vs_TY2021_RCODiff verifies that the only difference
-// between TY2024 and TY2021-2023 is the presence of Code II in RCO.
-func TestGenerate_TY2024_vs_TY2021_RCODiff(t *testing.T) {
-	makeSubWithBoxAllocatedTips := func(year string) *domain.Submission {
-		sub := minimalSubmission(year)
-		sub.Employees[0].Amounts.OriginalAllocatedTips = 100000
-		sub.Employees[0].Amounts.CorrectAllocatedTips = 110000
-		return sub
-	}
+// TestGenerateMulti_PerEmployerRCT verifies each RCE gets its own RCT
+// totalling only its employees, while the single RCF counts them all.
+func TestGenerateMulti_PerEmployerRCT(t *testing.T) {
+	first := minimalSubmission("2024")
+	second := minimalSubmission("2024")
+	second.Employer.EIN = "223456789"
+	second.Employees = append(second.Employees, second.Employees[0])
+	second.Employees[1].SSN = "123456789"
+	second.Employees[1].Amounts.OriginalWagesTipsOther = 1000000
+	second.Employees[1].Amounts.CorrectWagesTipsOther = 1200000
 
-	out2021 := generate(t, 2021, makeSubWithBoxAllocatedTips("2021"))
-	out2024 := generate(t, 2024, makeSubWithBoxAllocatedTips("2024"))
+	var buf bytes.Buffer
+	if err := efw2c.MustNew(2024).GenerateMulti(context.Background(), first.Submitter, []*domain.Submission{first, second}, &buf); err != nil {
+		t.Fatalf("GenerateMulti: %v", err)
+	}
+	out := buf.String()
+	want := []string{"RCA", "RCE", "RCW", "RCT", "RCE", "RCW", "RCW", "RCT", "RCF"}
+	if got := recordIDs(out); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("records: want %v, got %v", want, got)
+	}
+
+	for _, tc := range []struct {
+		n                int
+		count, orig, cor string
+	}{
+		{3, "0000001", "000000005000000", "000000005100000"},
+		{7, "0000002", "000000006000000", "000000006300000"},
+	} {
+		rct := record(out, tc.n)
+		if got := extract(rct, 4, 10); got != tc.count {
+			t.Errorf("RCT[%d] TotalRCWRecords: want %s, got %s", tc.n, tc.count, got)
+		}
+		if got := extract(rct, 11, 25); got != tc.orig {
+			t.Errorf("RCT[%d] Box1 orig total: want %s, got %s", tc.n, tc.orig, got)
+		}
+		if got := extract(rct, 26, 40); got != tc.cor {
+			t.Errorf("RCT[%d] Box1 corr total: want %s, got %s", tc.n, tc.cor, got)
+		}
+	}
+	if got := extract(record(out, 8), 4, 10); got != "0000003" {
+		t.Errorf("RCF TotalRCWRecords: want 0000003, got %s", got)
+	}
+	if got := extract(record(out, 4), 17, 25); got != "223456789" {
+		t.Errorf("second RCE EIN: want 223456789, got %s", got)
+	}
+
+	second.Employer.TaxYear = "2023"
+	if err := efw2c.MustNew(2024).GenerateMulti(context.Background(), first.Submitter, []*domain.Submission{first, second}, &buf); err == nil {
+		t.Error("GenerateMulti: want error for mixed tax years, got nil")
+	}
+}
+
+// TestGenerateMulti_SharedSubmitter verifies two separately stored
+// employers become one file with exactly one RCA, written from the shared
+// submitter rather than either submission's, and one RCF.
+func TestGenerateMulti_SharedSubmitter(t *testing.T) {
+	first := minimalSubmission("2024")
+	second := minimalSubmission("2024")
+	second.Employer.EIN = "223456789"
+	second.Submitter.BSOUID = "OTHERUID"
+	bureau := first.Submitter
+	bureau.BSOUID = "BUREAU01"
+
+	var buf bytes.Buffer
+	err := efw2c.MustNew(2024).GenerateMulti(context.Background(), bureau, []*domain.Submission{first, second}, &buf)
+	if err != nil {
+		t.Fatalf("GenerateMulti: %v", err)
+	}
+	out := buf.String()
+	ids := recordIDs(out)
+	count := map[string]int{}
+	for _, id := range ids {
+		count[id]++
+	}
+	if count["RCA"] != 1 || count["RCF"] != 1 || count["RCE"] != 2 || count["RCT"] != 2 {
+		t.Fatalf("want 1 RCA, 2 RCE, 2 RCT, 1 RCF; got %v", ids)
+	}
+	if ids[0] != "RCA" || ids[len(ids)-1] != "RCF" {
+		t.Errorf("want RCA first and RCF last, got %v", ids)
+	}
+	if got := extract(record(out, 0), 13, 20); got != "BUREAU01" {
+		t.Errorf("RCA BSOUID: want BUREAU01, got %q", got)
+	}
+	if got := extract(record(out, len(ids)-1), 4, 10); got != "0000002" {
+		t.Errorf("RCF TotalRCWRecords: want 0000002, got %s", got)
+	}
+	if first.Submitter.BSOUID != "TESTUSER" {
+		t.Error("GenerateMulti must not modify the caller's submission")
+	}
+
+	if err := efw2c.MustNew(2024).GenerateMulti(context.Background(), bureau, nil, &buf); err == nil {
+		t.Error("no submissions: want error, got nil")
+	}
+}
+
+// TestGenerate_AdditionalEmployers verifies a submission with further
+// employer blocks writes one RCA, an RCE … RCT block per employer with its
+// own totals, and one RCF counting every RCW — and parses back to the same
+// blocks.
+func TestGenerate_AdditionalEmployers(t *testing.T) {
+	sub := minimalSubmission("2024")
+	second := minimalSubmission("2024")
+	second.Employees[0].Amounts.OriginalWagesTipsOther = 1000000
+	second.Employees[0].Amounts.CorrectWagesTipsOther = 1200000
+	sub.AdditionalEmployers = []domain.EmployerBlock{{
+		Employer:  domain.EmployerRecord{EIN: "223456789", Name: "SECOND CO", EmploymentCode: "R", KindOfEmployer: "N"},
+		Employees: append(second.Employees, second.Employees[0]),
+	}}
+
+	out := generate(t, 2024, sub)
+	want := "RCA RCE RCW RCT RCE RCW RCW RCT RCF"
+	if got := strings.Join(recordIDs(out), " "); got != want {
+		t.Fatalf("records: want %s, got %s", want, got)
+	}
+	if got := extract(record(out, 4), 17, 25); got != "223456789" {
+		t.Errorf("second RCE EIN: want 223456789, got %s", got)
+	}
+	if got := extract(record(out, 4), 4, 7); got != "2024" {
+		t.Errorf("second RCE TaxYear: want 2024 (inherited), got %s", got)
+	}
+	rct := record(out, 7)
+	if got := extract(rct, 4, 10); got != "0000002" {
+		t.Errorf("second RCT TotalRCWRecords: want 0000002, got %s", got)
+	}
+	if got := extract(rct, 11, 25); got != "000000002000000" {
+		t.Errorf("second RCT Box 1 orig total: want 000000002000000, got %s", got)
+	}
+	if got := extract(record(out, 8), 4, 10); got != "0000003" {
+		t.Errorf("RCF TotalRCWRecords: want 0000003, got %s", got)
+	}
+	if got := sub.Summary().TotalRecords; got != len(recordIDs(out)) {
+		t.Errorf("Summary TotalRecords: want %d, got %d", len(recordIDs(out)), got)
+	}
+
+	parsed, err := efw2c.Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(parsed.Employees) != 1 || len(parsed.AdditionalEmployers) != 1 ||
+		len(parsed.AdditionalEmployers[0].Employees) != 2 || parsed.AdditionalEmployers[0].Employer.EIN != "223456789" {
+		t.Fatalf("Parse: want 1 + [2] employees under EIN 223456789, got %d + %+v", len(parsed.Employees), parsed.AdditionalEmployers)
+	}
+	if got := generate(t, 2024, parsed); got != out {
+		t.Error("round trip: regenerated file differs")
+	}
+}
+
+// TestValidate_AdditionalEmployers verifies Validate, CheckNegative and
+// Check look past the first employer block, indexing employees within
+// their own block.
+func TestValidate_AdditionalEmployers(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	second := minimalSubmission("2024")
+	second.Employees[0].SSN = "000123456"
+	second.Employees[0].Amounts.CorrectStateWages = -100
+	sub.AdditionalEmployers = []domain.EmployerBlock{{
+		Employer:  domain.EmployerRecord{EIN: "12345", Name: "SECOND CO"},
+		Employees: append([]domain.EmployeeRecord{sub.Employees[0]}, second.Employees[0]),
+	}}
+
+	g := efw2c.MustNew(2024)
+	var got []string
+	for _, e := range g.Validate(sub) {
+		got = append(got, fmt.Sprintf("%s/%d", e.Code, e.Employee))
+	}
+	if !slices.Contains(got, efw2c.CodeInvalidEIN+"/-1") || !slices.Contains(got, efw2c.CodeInvalidSSN+"/1") {
+		t.Errorf("Validate: want invalid_ein/-1 and invalid_ssn/1, got %v", got)
+	}
+	if errs := efw2c.CheckNegative(sub); len(errs) != 1 || errs[0].Employee != 1 {
+		t.Errorf("CheckNegative: want one error on employee 1, got %v", errs)
+	}
+	if !slices.ContainsFunc(g.Check(sub), func(w efw2c.Warning) bool { return w.Code == efw2c.CodeOneSidedCorrection && w.Employee == 1 }) {
+		t.Error("Check: want a one-sided correction warning on employee 1")
+	}
+}
+
+// TestGenerate_RCF_FinalRecord verifies the RCF record contains the correct
+// RCW count at positions 4-10.
+func TestGenerate_RCF_FinalRecord(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			sub := minimalSubmission(fmt.Sprintf("%d", year))
+			out := generate(t, year, sub)
+			nRecords := len(out) / spec.RecordLen
+			rcf := record(out, nRecords-1)
+
+			if got := extract(rcf, 1, 3); got != "RCF" {
+				t.Fatalf("expected RCF, got %q", got)
+			}
+			// TotalRCWRecords at 4-10 for 1 employee
+			if got := extract(rcf, 4, 10); got != "0000001" {
+				t.Errorf("RCF TotalRCWRecords pos 4-10: want '0000001', got %q", got)
+			}
+			// Remainder must be blank
+			if got := strings.TrimRight(extract(rcf, 11, 1024), " "); got != "" {
+				t.Errorf("RCF Blank11 pos 11-1024: want blank, got %q", got)
+			}
+		})
+	}
+}
+
+// TestGenerate_TY2024_vs_TY2021_RCODiff verifies that the only difference
+// between TY2024 and TY2021-2023 is the presence of Code II in RCO.
+func TestGenerate_TY2024_vs_TY2021_RCODiff(t *testing.T) {
+	makeSubWithBoxAllocatedTips := func(year string) *domain.Submission {
+		sub := minimalSubmission(year)
+		sub.Employees[0].Amounts.OriginalAllocatedTips = 100000
+		sub.Employees[0].Amounts.CorrectAllocatedTips = 110000
+		return sub
+	}
+
+	out2021 := generate(t, 2021, makeSubWithBoxAllocatedTips("2021"))
+	out2024 := generate(t, 2024, makeSubWithBoxAllocatedTips("2024"))
 
 	rco2021 := ""
 	rco2024 := ""
@@ -1214,3 +1950,1843 @@ func TestGenerate_AgentIndicatorCode_Position(t *testing.T) {
 		})
 	}
 }
+
+// maximalSubmission populates every domain field so field-coverage
+// diagnostics can reveal spec fields the generator never writes.
+func maximalSubmission(taxYear string) *domain.Submission {
+	sub := minimalSubmission(taxYear)
+	sub.Submitter.PreparerCode = "A"
+	sub.Submitter.ResubIndicator = "1"
+	sub.Submitter.ResubWFID = "ABC123"
+	sub.Submitter.SoftwareCode, sub.Submitter.SoftwareVendorCode = "99", "1234"
+	sub.Submitter.PhoneExtension, sub.Submitter.ContactFax = "12345", "8005559876"
+	sub.Employer.OriginalEIN = "111222333"
+	sub.Employer.AgentIndicator = "1"
+	sub.Employer.AgentEIN = "555444333"
+	sub.Employer.TerminatingBusiness = true
+	sub.Employer.ContactName = "BOB BOSS"
+	sub.Employer.ContactPhone = "8005559999"
+	sub.Employer.ContactPhoneExtension = "54321"
+	sub.Employer.ContactEmail = "bob@example.com"
+	sub.Employer.OriginalThirdPartySick, sub.Employer.CorrectThirdPartySick = "0", "1"
+
+	e := &sub.Employees[0]
+	e.OriginalSSN = "123121234"
+	e.MiddleName, e.Suffix = "Q", "JR"
+	e.OriginalFirstName, e.OriginalMiddleName = "JON", "P"
+	e.OriginalLastName, e.OriginalSuffix = "SMYTHE", "SR"
+	e.AddressLine1, e.AddressLine2 = "1 ELM ST", "APT 2"
+	e.City, e.State, e.ZIP, e.ZIPExtension = "PEORIA", "IL", "61602", "4321"
+	e.OriginalStateCode, e.CorrectStateCode = "IL", "IN"
+	e.OriginalStateIDNumber, e.CorrectStateIDNumber = "IL-1", "IN-1"
+	e.OriginalLocalityName, e.CorrectLocalityName = "PEORIA", "GARY"
+	e.Box13 = domain.Box13Flags{
+		OrigStatutoryEmployee: boolPtr(false), CorrectStatutoryEmployee: boolPtr(true),
+		OrigRetirementPlan: boolPtr(false), CorrectRetirementPlan: boolPtr(true),
+		OrigThirdPartySickPay: boolPtr(false), CorrectThirdPartySickPay: boolPtr(true),
+	}
+	a := &e.Amounts
+	a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips = 1000, 2000
+	a.OriginalAllocatedTips, a.CorrectAllocatedTips = 1000, 2000
+	a.OriginalUncollectedEETax, a.CorrectUncollectedEETax = 1000, 2000
+	a.OriginalCodeR_MSA, a.CorrectCodeR_MSA = 1000, 2000
+	a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE = 1000, 2000
+	a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption = 1000, 2000
+	a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS = 1000, 2000
+	a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed = 1000, 2000
+	a.OriginalCodeZ_409A, a.CorrectCodeZ_409A = 1000, 2000
+	a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver = 1000, 2000
+	a.OriginalDependentCare, a.CorrectDependentCare = 1000, 2000
+	a.OriginalNonqualPlan457, a.CorrectNonqualPlan457 = 1000, 2000
+	a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457 = 1000, 2000
+	a.OriginalCode401k, a.CorrectCode401k = 1000, 2000
+	a.OriginalCode403b, a.CorrectCode403b = 1000, 2000
+	a.OriginalCode457bGovt, a.CorrectCode457bGovt = 1000, 2000
+	a.OriginalCodeW_HSA, a.CorrectCodeW_HSA = 1000, 2000
+	a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k = 1000, 2000
+	a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b = 1000, 2000
+	a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth = 1000, 2000
+	a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife = 1000, 2000
+	a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP = 1000, 2000
+	a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D = 1000, 2000
+	a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay = 1000, 2000
+	a.OriginalCodeV_NSO, a.CorrectCodeV_NSO = 1000, 2000
+	a.OriginalCodeY_409A, a.CorrectCodeY_409A = 1000, 2000
+	a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA = 1000, 2000
+	a.OriginalStateWages, a.CorrectStateWages = 1000, 2000
+	a.OriginalStateIncomeTax, a.CorrectStateIncomeTax = 1000, 2000
+	a.OriginalLocalWages, a.CorrectLocalWages = 1000, 2000
+	a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax = 1000, 2000
+	return sub
+}
+
+// TestCoverage_MaximalSubmission runs the field-coverage diagnostic and
+// checks the exact set of spec fields the generator leaves blank per record
+// type, so a field that stops being written fails here. Foreign-address
+// fields stay blank because the submission is domestic; the rest have no
+// domain value yet.
+func TestCoverage_MaximalSubmission(t *testing.T) {
+	foreign := []string{"ForeignStateProvince", "ForeignPostalCode", "CountryCode"}
+	want := map[string][]string{
+		"RCA": foreign,
+		"RCE": {"OrigEstablishmentNum", "CorrectEstablishmentNum", "ForeignStateProvince", "ForeignPostalCode", "CountryCode",
+			"OrigEmploymentCode", "ContactFax"},
+		"RCW": append(slices.Clone(foreign), "OrigTIBDeferredComp", "CorrectTIBDeferredComp"),
+		"RCO": {"OrigCodeEE_Roth457b", "CorrectCodeEE_Roth457b", "OrigCodeGG_83i", "CorrectCodeGG_83i",
+			"OrigCodeHH_83iDeferral", "CorrectCodeHH_83iDeferral"},
+		"RCS": {"OrigTaxingEntityCode", "CorrectTaxingEntityCode", "OrigSSN", "OrigFirstName", "OrigMiddleName", "OrigLastName",
+			"LocationAddress", "DeliveryAddress", "City", "StateAbbrev", "ZIPCode", "ZIPExtension",
+			"OtherStateData", "TaxTypeCode", "StateControlNumber", "SupplementalData1", "SupplementalData2"},
+		"RCT": {"OrigTotalTIBDeferredComp", "CorrectTotalTIBDeferredComp"},
+		"RCU": {"OrigTotalCodeEE_Roth457b", "CorrectTotalCodeEE_Roth457b", "OrigTotalCodeGG_83i", "CorrectTotalCodeGG_83i",
+			"OrigTotalCodeHH_83iDeferral", "CorrectTotalCodeHH_83iDeferral"},
+		"RCF": {},
+	}
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			g, err := efw2c.New(year)
+			if err != nil {
+				t.Fatalf("New(%d): %v", year, err)
+			}
+			report, err := g.Coverage(context.Background(), maximalSubmission(fmt.Sprintf("%d", year)))
+			if err != nil {
+				t.Fatalf("Coverage: %v", err)
+			}
+			for _, id := range spec.RecordOrder {
+				missing, ok := report[id]
+				if !ok || missing == nil {
+					t.Errorf("%s: missing from coverage report", id)
+					continue
+				}
+				if !slices.Equal(missing, want[id]) {
+					t.Errorf("%s unwritten fields:\n want %s\n got  %s", id, strings.Join(want[id], ", "), strings.Join(missing, ", "))
+				}
+			}
+		})
+	}
+}
+
+// TestCheckPairing_StrictBox4 verifies a one-sided Box 4 correction is an
+// error under strict pairing and only a warning otherwise.
+func TestCheckPairing_StrictBox4(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].Amounts.OriginalSocialSecurityTax = 0
+	sub.Employees[0].Amounts.CorrectSocialSecurityTax = 316200
+
+	t.Run("default", func(t *testing.T) {
+		g := efw2c.MustNew(2024)
+		errs, warns := g.CheckPairing(sub)
+		if len(errs) != 0 {
+			t.Errorf("want no errors, got %v", errs)
+		}
+		if len(warns) != 1 || warns[0].Field != "SocialSecurityTax" || warns[0].Code != efw2c.CodeOneSidedCorrection {
+			t.Errorf("want one SocialSecurityTax warning, got %+v", warns)
+		}
+		var buf bytes.Buffer
+		if err := g.Generate(context.Background(), sub, &buf); err != nil {
+			t.Errorf("Generate: want success, got %v", err)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		g := efw2c.MustNew(2024, efw2c.WithStrictPairing())
+		errs, warns := g.CheckPairing(sub)
+		if len(errs) != 1 || errs[0].Field != "SocialSecurityTax" || errs[0].Employee != 0 {
+			t.Errorf("want one SocialSecurityTax error for employee 0, got %+v", errs)
+		}
+		if len(warns) != 0 {
+			t.Errorf("want no warnings, got %+v", warns)
+		}
+		var buf bytes.Buffer
+		if err := g.Generate(context.Background(), sub, &buf); err == nil {
+			t.Error("Generate: want error under strict pairing, got nil")
+		}
+	})
+}
+
+// TestValidate_Identifiers covers the EIN/SSN rules enforced before a file is
+// generated, including the field and employee index on each error.
+func TestValidate_Identifiers(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	cases := []struct {
+		name      string
+		ein, ssn  string
+		wantField string
+		wantCode  string
+	}{
+		{"valid", "123456789", "123-45-6789", "", ""},
+		{"EIN short", "12345678", "123456789", "EIN", efw2c.CodeInvalidEIN},
+		{"EIN all zeros", "000000000", "123456789", "EIN", efw2c.CodeInvalidEIN},
+		{"EIN 00 prefix", "001234567", "123456789", "EIN", efw2c.CodeInvalidEIN},
+		{"SSN all zeros", "123456789", "000000000", "SSN", efw2c.CodeInvalidSSN},
+		{"SSN 9xx area", "123456789", "912345678", "SSN", efw2c.CodeInvalidSSN},
+		{"SSN 666 area", "123456789", "666123456", "SSN", efw2c.CodeInvalidSSN},
+		{"SSN 00 group", "123456789", "123004567", "SSN", efw2c.CodeInvalidSSN},
+		{"SSN sample card", "123456789", "078-05-1120", "SSN", efw2c.CodeInvalidSSN},
+		{"SSN letters", "123456789", "12345678X", "SSN", efw2c.CodeInvalidSSN},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employer.EIN = tc.ein
+			sub.Employees[0].SSN = tc.ssn
+			errs := g.Validate(sub)
+			if tc.wantCode == "" {
+				if len(errs) != 0 {
+					t.Errorf("want no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("want one error, got %v", errs)
+			}
+			e := errs[0]
+			wantEmp := 0
+			if tc.wantField == "EIN" {
+				wantEmp = -1
+			}
+			if e.Code != tc.wantCode || e.Field != tc.wantField || e.Employee != wantEmp {
+				t.Errorf("want %s on %s (employee %d), got %+v", tc.wantCode, tc.wantField, wantEmp, e)
+			}
+		})
+	}
+}
+
+// TestValidate_BSOUID verifies the BSO User ID is required and must be
+// exactly eight upper-case letters or digits unless lower case is allowed.
+func TestValidate_BSOUID(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	for _, id := range []string{"", "TESTUS1", "TEST-USR", "TESTUSER9", "testus01", "TestUs01"} {
+		sub := minimalSubmission("2024")
+		sub.Employees[0].SSN = "123456789"
+		sub.Submitter.BSOUID = id
+		errs := g.Validate(sub)
+		if len(errs) != 1 || errs[0].Code != efw2c.CodeInvalidBSOUID || errs[0].Field != "BSOUID" {
+			t.Errorf("BSOUID %q: want one %s error, got %v", id, efw2c.CodeInvalidBSOUID, errs)
+		}
+	}
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	sub.Submitter.BSOUID = "TESTUS01"
+	if errs := g.Validate(sub); len(errs) != 0 {
+		t.Errorf("BSOUID %q: want no errors, got %v", sub.Submitter.BSOUID, errs)
+	}
+	sub.Submitter.BSOUID = "testus01"
+	if errs := efw2c.MustNew(2024, efw2c.WithLowercaseBSOUID()).Validate(sub); len(errs) != 0 {
+		t.Errorf("BSOUID %q with WithLowercaseBSOUID: want no errors, got %v", sub.Submitter.BSOUID, errs)
+	}
+}
+
+// TestValidate_EmployerCodes verifies EmploymentCode and KindOfEmployer are
+// limited to the RCE code sets; blank falls back to the defaults.
+func TestValidate_EmployerCodes(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	cases := []struct {
+		name       string
+		employment string
+		kind       string
+		wantField  string
+		wantCode   string
+	}{
+		{"valid", "H", "T", "", ""},
+		{"blank defaults", "", "", "", ""},
+		{"employment lower case", "r", "N", "EmploymentCode", efw2c.CodeInvalidEmployment},
+		{"employment unknown", "Z", "N", "EmploymentCode", efw2c.CodeInvalidEmployment},
+		{"employment two letters", "RR", "N", "EmploymentCode", efw2c.CodeInvalidEmployment},
+		{"kind unknown", "R", "X", "KindOfEmployer", efw2c.CodeInvalidKind},
+		{"kind employment letter", "R", "R", "KindOfEmployer", efw2c.CodeInvalidKind},
+		{"kind word", "R", "NONE", "KindOfEmployer", efw2c.CodeInvalidKind},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees[0].SSN = "123456789"
+			sub.Employer.EmploymentCode, sub.Employer.KindOfEmployer = tc.employment, tc.kind
+			errs := g.Validate(sub)
+			if tc.wantCode == "" {
+				if len(errs) != 0 {
+					t.Errorf("want no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("want one error, got %v", errs)
+			}
+			if e := errs[0]; e.Code != tc.wantCode || e.Field != tc.wantField || e.Employee != -1 {
+				t.Errorf("want %s on %s, got %+v", tc.wantCode, tc.wantField, e)
+			}
+		})
+	}
+}
+
+// TestValidate_ZIP verifies a populated domestic ZIP must be exactly 5
+// digits and its extension 4, while foreign addresses skip the check.
+func TestValidate_ZIP(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	cases := []struct {
+		name      string
+		edit      func(*domain.Submission)
+		wantField string
+		employee  int
+	}{
+		{"valid", func(*domain.Submission) {}, "", 0},
+		{"blank extension", func(s *domain.Submission) { s.Employer.ZIPExtension = "" }, "", 0},
+		{"employer 4-digit ZIP", func(s *domain.Submission) { s.Employer.ZIP = "6270" }, "ZIP", -1},
+		{"employer ZIP+4 in ZIP", func(s *domain.Submission) { s.Employer.ZIP = "62701-1234" }, "ZIP", -1},
+		{"employer short extension", func(s *domain.Submission) { s.Employer.ZIPExtension = "123" }, "ZIPExtension", -1},
+		{"submitter letters", func(s *domain.Submission) { s.Submitter.ZIP = "6O601" }, "SubmitterZIP", -1},
+		{"employee 4-digit ZIP", func(s *domain.Submission) { s.Employees[0].ZIP = "2134" }, "ZIP", 0},
+		{"employee foreign", func(s *domain.Submission) {
+			s.Employees[0].ZIP, s.Employees[0].CountryCode = "K1A", "CA"
+		}, "", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees[0].SSN = "123456789"
+			tc.edit(sub)
+			errs := g.Validate(sub)
+			if tc.wantField == "" {
+				if len(errs) != 0 {
+					t.Errorf("want no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("want one error, got %v", errs)
+			}
+			if e := errs[0]; e.Code != efw2c.CodeInvalidZIP || e.Field != tc.wantField || e.Employee != tc.employee {
+				t.Errorf("want %s on %s (employee %d), got %+v", efw2c.CodeInvalidZIP, tc.wantField, tc.employee, e)
+			}
+		})
+	}
+}
+
+// TestValidate_ContactEmail verifies the RCA contact email is required,
+// fits the 40-character field and looks like local@domain.tld.
+func TestValidate_ContactEmail(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	cases := []struct {
+		name, email string
+		wantErr     bool
+	}{
+		{"valid", "jane@example.com", false},
+		{"exactly 40", strings.Repeat("a", 28) + "@example.com", false},
+		{"missing", "", true},
+		{"blank", "   ", true},
+		{"over 40", strings.Repeat("a", 29) + "@example.com", true},
+		{"no at", "jane.example.com", true},
+		{"no local part", "@example.com", true},
+		{"no dot in domain", "jane@localhost", true},
+		{"two ats", "jane@doe@example.com", true},
+		{"trailing dot", "jane@example.", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees[0].SSN = "123456789"
+			sub.Submitter.ContactEmail = tc.email
+			errs := g.Validate(sub)
+			if !tc.wantErr {
+				if len(errs) != 0 {
+					t.Errorf("want no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("want one error, got %v", errs)
+			}
+			if e := errs[0]; e.Code != efw2c.CodeInvalidEmail || e.Field != "ContactEmail" || e.Employee != -1 {
+				t.Errorf("want %s on ContactEmail, got %+v", efw2c.CodeInvalidEmail, e)
+			}
+		})
+	}
+}
+
+// TestSoftwareCode verifies an in-house (98) submitter leaves the vendor
+// code blank, an off-the-shelf (99) one writes it, and 99 without a vendor
+// code or an unknown code is rejected.
+func TestSoftwareCode(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	for _, tc := range []struct {
+		name, code, vendor string
+		wantField          string // blank: valid
+		wantCode, wantVend string // RCA 30-31 and 21-24 when valid
+	}{
+		{"none", "", "", "", "  ", "    "},
+		{"in-house", "98", "", "", "98", "    "},
+		{"off the shelf", "99", "1234", "", "99", "1234"},
+		{"off the shelf no vendor", "99", "", "SoftwareVendorCode", "", ""},
+		{"off the shelf short vendor", "99", "123", "SoftwareVendorCode", "", ""},
+		{"unknown", "97", "", "SoftwareCode", "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees[0].SSN = "123456789"
+			sub.Submitter.SoftwareCode, sub.Submitter.SoftwareVendorCode = tc.code, tc.vendor
+			errs := g.Validate(sub)
+			if tc.wantField != "" {
+				if len(errs) != 1 || errs[0].Code != efw2c.CodeInvalidSoftware || errs[0].Field != tc.wantField {
+					t.Fatalf("want one %s on %s, got %v", efw2c.CodeInvalidSoftware, tc.wantField, errs)
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("want no errors, got %v", errs)
+			}
+			rca := record(generate(t, 2024, sub), 0)
+			if got := extract(rca, 30, 31); got != tc.wantCode {
+				t.Errorf("SoftwareCode pos 30-31: want %q, got %q", tc.wantCode, got)
+			}
+			if got := extract(rca, 21, 24); got != tc.wantVend {
+				t.Errorf("SoftwareVendorCode pos 21-24: want %q, got %q", tc.wantVend, got)
+			}
+		})
+	}
+}
+
+// TestGenerate_PhoneExtensionFax verifies the RCA contact extension is
+// written zero-filled at 254-258 and the fax number at 305-314, and that
+// both stay blank when unset.
+func TestGenerate_PhoneExtensionFax(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Submitter.PhoneExtension, sub.Submitter.ContactFax = "123", "8005559876"
+	rca := record(generate(t, 2024, sub), 0)
+	if got := extract(rca, 254, 258); got != "00123" {
+		t.Errorf("PhoneExtension pos 254-258: want '00123', got %q", got)
+	}
+	if got := extract(rca, 305, 314); got != "8005559876" {
+		t.Errorf("ContactFax pos 305-314: want '8005559876', got %q", got)
+	}
+
+	rca = record(generate(t, 2024, minimalSubmission("2024")), 0)
+	if got := extract(rca, 254, 258) + extract(rca, 305, 314); strings.TrimSpace(got) != "" {
+		t.Errorf("unset extension and fax: want blanks, got %q", got)
+	}
+}
+
+// TestGenerate_EmployerPhoneExtension verifies the RCE contact extension is
+// written at 270-274 and left blank when unset.
+func TestGenerate_EmployerPhoneExtension(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employer.ContactPhone, sub.Employer.ContactPhoneExtension = "8005559999", "42"
+	rce := record(generate(t, 2024, sub), 1)
+	if got := extract(rce, 270, 274); got != "00042" {
+		t.Errorf("PhoneExtension pos 270-274: want '00042', got %q", got)
+	}
+
+	rce = record(generate(t, 2024, minimalSubmission("2024")), 1)
+	if got := extract(rce, 270, 274); got != "     " {
+		t.Errorf("unset extension: want blanks, got %q", got)
+	}
+}
+
+// TestValidate_NoCorrection verifies an employee whose every original value
+// equals its correct value is rejected, since SSA rejects an all-zero-delta
+// RCW, and that changing a single box clears the error.
+func TestValidate_NoCorrection(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	a := &sub.Employees[0].Amounts
+	a.CorrectWagesTipsOther, a.CorrectFederalIncomeTax = a.OriginalWagesTipsOther, a.OriginalFederalIncomeTax
+	a.CorrectSocialSecurityWages, a.CorrectSocialSecurityTax = a.OriginalSocialSecurityWages, a.OriginalSocialSecurityTax
+	a.CorrectMedicareWages, a.CorrectMedicareTax = a.OriginalMedicareWages, a.OriginalMedicareTax
+
+	errs := g.Validate(sub)
+	if len(errs) != 1 {
+		t.Fatalf("want one error, got %v", errs)
+	}
+	if e := errs[0]; e.Code != efw2c.CodeNoCorrection || e.Employee != 0 {
+		t.Errorf("want %s on employee 0, got %+v", efw2c.CodeNoCorrection, e)
+	}
+
+	a.CorrectMedicareTax++
+	if errs := g.Validate(sub); len(errs) != 0 {
+		t.Errorf("one changed box: want no errors, got %v", errs)
+	}
+}
+
+// TestValidate_IncompleteNameCorrection verifies a name correction without
+// the original last name is rejected, and passes once it is given.
+func TestValidate_IncompleteNameCorrection(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	sub.Employees[0].OriginalFirstName = "JON"
+
+	errs := g.Validate(sub)
+	if len(errs) != 1 || errs[0].Code != efw2c.CodeIncompleteName || errs[0].Field != "OriginalLastName" {
+		t.Fatalf("want one %s on OriginalLastName, got %v", efw2c.CodeIncompleteName, errs)
+	}
+
+	sub.Employees[0].OriginalLastName = sub.Employees[0].LastName
+	if errs := g.Validate(sub); len(errs) != 0 {
+		t.Errorf("original last name given: want no errors, got %v", errs)
+	}
+}
+
+// TestValidate_MedicaidWaiverBeforeTY2024 verifies Code II amounts are
+// rejected for a year whose RCO cannot carry them, and accepted for TY2024.
+func TestValidate_MedicaidWaiverBeforeTY2024(t *testing.T) {
+	for _, tc := range []struct {
+		year    string
+		wantErr bool
+	}{
+		{"2023", true},
+		{"2024", false},
+	} {
+		sub := minimalSubmission(tc.year)
+		sub.Employees[0].SSN = "123456789"
+		sub.Employees[0].Amounts = domain.MonetaryAmounts{OriginalMedicaidWaiver: 100000, CorrectMedicaidWaiver: 120000}
+		var got []string
+		for _, e := range efw2c.MustNew(2024).Validate(sub) {
+			got = append(got, e.Code)
+		}
+		if has := slices.Contains(got, efw2c.CodeAmountNotInYear); has != tc.wantErr {
+			t.Errorf("TY%s: want %s %v, got %v", tc.year, efw2c.CodeAmountNotInYear, tc.wantErr, got)
+		}
+	}
+}
+
+// TestValidate_CountryCode verifies a foreign address needs an SSA
+// Appendix I country code, and that a domestic one is not checked.
+func TestValidate_CountryCode(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	for _, tc := range []struct {
+		code     string
+		employee int
+		wantErr  bool
+	}{
+		{"CA", 0, false},
+		{"gb", 0, false}, // Gabon, not the United Kingdom
+		{"ZZ", 0, true},
+		{"DE", -1, true},
+		{"US", -1, false},
+	} {
+		sub := minimalSubmission("2024")
+		sub.Employees[0].SSN = "123456789"
+		if tc.employee < 0 {
+			sub.Employer.CountryCode = tc.code
+		} else {
+			sub.Employees[0].CountryCode = tc.code
+		}
+		errs := g.Validate(sub)
+		if !tc.wantErr {
+			if len(errs) != 0 {
+				t.Errorf("%q: want no errors, got %v", tc.code, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || errs[0].Code != efw2c.CodeInvalidCountry || errs[0].Employee != tc.employee {
+			t.Errorf("%q: want one %s for employee %d, got %v", tc.code, efw2c.CodeInvalidCountry, tc.employee, errs)
+		}
+	}
+}
+
+// TestValidate_RCSStateCode verifies an employee with state amounts is
+// rejected when the state the RCS would carry has no SSA numeric code,
+// whether it is their own or the employer's.
+func TestValidate_RCSStateCode(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	for _, tc := range []struct {
+		name, employee, employer, wantField string
+		wantEmployee                        int
+	}{
+		{"employee IL", "IL", "", "", 0},
+		{"employer IL", "", "IL", "", 0},
+		{"employee typo", "XX", "IL", "CorrectStateCode", 0},
+		{"employer typo", "", "XX", "State", -1}, // the address check
+		{"employer military", "", "AE", "State", 0},
+		{"none", "", "", "State", 0},
+	} {
+		sub := minimalSubmission("2024")
+		sub.Employees[0].SSN = "123456789"
+		sub.Employees[0].Amounts.CorrectStateWages = 100000
+		sub.Employees[0].CorrectStateCode = tc.employee
+		sub.Employer.State = tc.employer
+		errs := g.Validate(sub)
+		if tc.wantField == "" {
+			if len(errs) != 0 {
+				t.Errorf("%s: want no errors, got %v", tc.name, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || errs[0].Code != efw2c.CodeInvalidState || errs[0].Field != tc.wantField ||
+			errs[0].Employee != tc.wantEmployee {
+			t.Errorf("%s: want one %s on %s of %d, got %v", tc.name, efw2c.CodeInvalidState, tc.wantField, tc.wantEmployee, errs)
+		}
+	}
+}
+
+// TestValidate_StateAbbrev verifies address states accept territories and
+// military codes, reject unknown ones, and skip blank and foreign addresses.
+func TestValidate_StateAbbrev(t *testing.T) {
+	g := efw2c.MustNew(2024)
+	for _, tc := range []struct {
+		name      string
+		edit      func(*domain.Submission)
+		wantField string
+		employee  int
+	}{
+		{"employee military", func(s *domain.Submission) { s.Employees[0].State = "AE" }, "", 0},
+		{"employer territory", func(s *domain.Submission) { s.Employer.State = "gu" }, "", 0},
+		{"employee blank", func(s *domain.Submission) { s.Employees[0].State = "" }, "", 0},
+		{"employee unknown", func(s *domain.Submission) { s.Employees[0].State = "ZZ" }, "State", 0},
+		{"employer unknown", func(s *domain.Submission) { s.Employer.State = "ZZ" }, "State", -1},
+		{"submitter unknown", func(s *domain.Submission) {
+			s.Submitter.AddressLine1, s.Submitter.State = "1 MAIN ST", "ZZ"
+		}, "SubmitterState", -1},
+		{"employee foreign", func(s *domain.Submission) {
+			s.Employees[0].State, s.Employees[0].ZIP, s.Employees[0].CountryCode = "ZZ", "", "CA"
+		}, "", 0},
+	} {
+		sub := minimalSubmission("2024")
+		sub.Employees[0].SSN = "123456789"
+		tc.edit(sub)
+		errs := g.Validate(sub)
+		if tc.wantField == "" {
+			if len(errs) != 0 {
+				t.Errorf("%s: want no errors, got %v", tc.name, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || errs[0].Code != efw2c.CodeInvalidState || errs[0].Field != tc.wantField || errs[0].Employee != tc.employee {
+			t.Errorf("%s: want one %s on %s of %d, got %v", tc.name, efw2c.CodeInvalidState, tc.wantField, tc.employee, errs)
+		}
+	}
+}
+
+// TestGenerate_ZIPZeroFill verifies ZIP fields are written as numeric
+// fields: a leading zero is kept, and a ZIP that lost its leading zero in
+// a spreadsheet is right-justified and zero-filled rather than space-padded.
+func TestGenerate_ZIPZeroFill(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employer.ZIP, sub.Employer.ZIPExtension = "02134", "0042"
+	sub.Employees[0].State, sub.Employees[0].ZIP = "MA", "2134"
+	out := generate(t, 2024, sub)
+	for _, c := range []struct {
+		rec        int
+		name       string
+		start, end int
+		want       string
+	}{
+		{0, "RCA ZIPCode", 157, 161, "02134"},
+		{0, "RCA ZIPExtension", 162, 165, "0042"},
+		{1, "RCE ZIPCode", 169, 173, "02134"},
+		{1, "RCE ZIPExtension", 174, 177, "0042"},
+		{2, "RCW ZIPCode", 190, 194, "02134"},
+		{2, "RCW ZIPExtension", 195, 198, "    "},
+	} {
+		if got := extract(record(out, c.rec), c.start, c.end); got != c.want {
+			t.Errorf("%s pos %d-%d: want %q, got %q", c.name, c.start, c.end, c.want, got)
+		}
+	}
+}
+
+// TestCheckWidths_OverflowingWage verifies an amount too wide for an 11-digit
+// money field blocks generation with the field's limit and the offending value.
+func TestCheckWidths_OverflowingWage(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].Amounts.OriginalWagesTipsOther = 120000000000 // $1,200,000,000.00
+
+	errs := efw2c.CheckWidths(sub)
+	if len(errs) != 1 {
+		t.Fatalf("want one overflow error, got %+v", errs)
+	}
+	e := errs[0]
+	if e.Code != efw2c.CodeFieldOverflow || e.Field != "OriginalWagesTipsOther" || e.Employee != 0 {
+		t.Errorf("want field_overflow on OriginalWagesTipsOther for employee 0, got %+v", e)
+	}
+	if e.Limit != "$999,999,999.99" {
+		t.Errorf("Limit: want '$999,999,999.99', got %q", e.Limit)
+	}
+	if e.Got != "$1,200,000,000.00" {
+		t.Errorf("Got: want '$1,200,000,000.00', got %q", e.Got)
+	}
+
+	var buf bytes.Buffer
+	err := efw2c.MustNew(2024).Generate(context.Background(), sub, &buf)
+	var verrs efw2c.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Generate: want ValidationErrors, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Generate wrote %d bytes despite validation failure", buf.Len())
+	}
+}
+
+// TestGenerateResult_Counts verifies the result describes exactly what was
+// written for a multi-employee submission.
+func TestGenerateResult_Counts(t *testing.T) {
+	sub := minimalSubmission("2024")
+	second := sub.Employees[0]
+	second.SSN, second.FirstName = "123456789", "MARY"
+	second.Amounts.OriginalAllocatedTips, second.Amounts.CorrectAllocatedTips = 10000, 20000 // adds an RCO and RCU
+	third := sub.Employees[0]
+	third.SSN, third.FirstName = "234567890", "LEE"
+	sub.Employees = append(sub.Employees, second, third)
+
+	g := efw2c.MustNew(2024, efw2c.WithLineEnding(efw2c.CRLF))
+	var buf bytes.Buffer
+	res, err := g.GenerateResult(context.Background(), sub, &buf)
+	if err != nil {
+		t.Fatalf("GenerateResult: %v", err)
+	}
+	if res.ByteCount != buf.Len() {
+		t.Errorf("ByteCount: want %d, got %d", buf.Len(), res.ByteCount)
+	}
+	if want := buf.Len() / (spec.RecordLen + 2); res.RecordCount != want || res.RecordCount != 9 { // RCA RCE RCW RCW RCO RCW RCT RCU RCF
+		t.Errorf("RecordCount: want %d (9), got %d", want, res.RecordCount)
+	}
+	if res.RCWCount != 3 {
+		t.Errorf("RCWCount: want 3, got %d", res.RCWCount)
+	}
+	if len(res.Truncations) != 0 {
+		t.Errorf("Truncations: want none, got %+v", res.Truncations)
+	}
+	if got, want := len(res.Warnings), len(g.Check(sub)); got != want {
+		t.Errorf("Warnings: want Check's %d, got %d", want, got)
+	}
+}
+
+// TestTrimPolicy_Reject verifies an over-length employer name blocks
+// generation under the default policy.
+func TestTrimPolicy_Reject(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employer.Name = strings.Repeat("ACME WIDGETS ", 5) + "INTERNATIONAL" // 78 chars
+
+	g := efw2c.MustNew(2024)
+	errs := g.Validate(sub)
+	var found bool
+	for _, e := range errs {
+		if e.Code == efw2c.CodeFieldOverflow && e.Field == "EmployerName" && e.Employee == -1 {
+			found = true
+			if e.Limit != "57 characters" {
+				t.Errorf("Limit: want '57 characters', got %q", e.Limit)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("want field_overflow on EmployerName, got %+v", errs)
+	}
+
+	var buf bytes.Buffer
+	err := g.Generate(context.Background(), sub, &buf)
+	var verrs efw2c.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Generate: want ValidationErrors, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Generate wrote %d bytes despite an over-length name", buf.Len())
+	}
+}
+
+// TestTrimPolicy_NumericOverflow verifies an over-long numeric field is
+// rejected like an alpha one rather than cut to its trailing digits.
+func TestTrimPolicy_NumericOverflow(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Submitter.PhoneExtension = "1234567"
+
+	var found bool
+	for _, e := range efw2c.MustNew(2024).Validate(sub) {
+		if e.Code == efw2c.CodeFieldOverflow && e.Field == "PhoneExtension" && e.Employee == -1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("want field_overflow on PhoneExtension")
+	}
+
+	var buf bytes.Buffer
+	g := efw2c.MustNew(2024, efw2c.WithTrimPolicy(efw2c.TrimTruncate))
+	res, err := g.GenerateResult(context.Background(), sub, &buf)
+	if err != nil {
+		t.Fatalf("GenerateResult: %v", err)
+	}
+	if len(res.Truncations) != 1 || res.Truncations[0].Field != "PhoneExtension" {
+		t.Errorf("Truncations: want PhoneExtension, got %+v", res.Truncations)
+	}
+}
+
+// TestTrimPolicy_Truncate verifies an over-length employer name is cut to
+// the field width and reported as a warning.
+func TestTrimPolicy_Truncate(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	sub.Employer.Name = strings.Repeat("ACME WIDGETS ", 5) + "INTERNATIONAL"
+
+	g := efw2c.MustNew(2024, efw2c.WithTrimPolicy(efw2c.TrimTruncate))
+	if errs := g.Validate(sub); len(errs) != 0 {
+		t.Fatalf("Validate: want no errors under TrimTruncate, got %+v", errs)
+	}
+	var found bool
+	for _, w := range g.Check(sub) {
+		if w.Code == efw2c.CodeFieldTruncated && w.Field == "EmployerName" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("want field_truncated warning on EmployerName")
+	}
+
+	var buf bytes.Buffer
+	res, err := g.GenerateResult(context.Background(), sub, &buf)
+	if err != nil {
+		t.Fatalf("GenerateResult: %v", err)
+	}
+	if len(res.Truncations) == 0 {
+		t.Error("GenerateResult: want the truncated name in Truncations")
+	}
+	rce := record(buf.String(), 1)
+	if got, want := extract(rce, 44, 100), sub.Employer.Name[:57]; got != want {
+		t.Errorf("EmployerName: want %q, got %q", want, got)
+	}
+}
+
+// TestGenerate_NegativeAmountRejected verifies a negative amount blocks
+// generation instead of being written as zeros.
+func TestGenerate_NegativeAmountRejected(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].Amounts.CorrectWagesTipsOther = -100000 // -$1,000.00
+
+	var buf bytes.Buffer
+	err := efw2c.MustNew(2024).Generate(context.Background(), sub, &buf)
+	var verrs efw2c.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Generate: want ValidationErrors, got %v", err)
+	}
+	if len(verrs) != 1 || verrs[0].Code != efw2c.CodeNegativeAmount || verrs[0].Field != "CorrectWagesTipsOther" || verrs[0].Employee != 0 {
+		t.Errorf("want one negative_amount error on CorrectWagesTipsOther, got %+v", verrs)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Generate wrote %d bytes (want none, not 00000000000)", buf.Len())
+	}
+}
+
+// TestCheck_CityOnlyAddress verifies a partial employee address is flagged
+// as a warning and does not block generation.
+func TestCheck_CityOnlyAddress(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].City = "SPRINGFIELD"
+
+	g := efw2c.MustNew(2024)
+	var found bool
+	for _, w := range g.Check(sub) {
+		if w.Code == efw2c.CodeIncompleteAddress {
+			found = true
+			if w.Employee != 0 || !strings.Contains(w.Message, "state, ZIP") {
+				t.Errorf("want employee 0 missing state, ZIP; got %+v", w)
+			}
+		}
+	}
+	if !found {
+		t.Error("want incomplete_address warning for city-only address")
+	}
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), sub, &buf); err != nil {
+		t.Errorf("Generate: want success, got %v", err)
+	}
+}
+
+// TestCheckSocialSecurity covers the wage base and 6.2% rate advisories.
+func TestCheckSocialSecurity(t *testing.T) {
+	ys, _ := spec.ForYear(2024) // wage base $168,600.00
+	codes := func(a domain.MonetaryAmounts) []string {
+		var out []string
+		for _, w := range efw2c.CheckSocialSecurity(ys, a) {
+			out = append(out, w.Code)
+		}
+		return out
+	}
+	cases := []struct {
+		name       string
+		wages, tax int64
+		want       []string
+	}{
+		{"on rate", 5000000, 310000, nil},
+		{"within tolerance", 5000000, 310099, nil},
+		{"off rate", 5000000, 100000, []string{efw2c.CodeSSTaxRate}},
+		{"at wage base", 16860000, 1045320, nil},
+		{"over wage base, tax capped", 17000000, 1045320, []string{efw2c.CodeSSWageBase}},
+		{"over wage base, tax uncapped", 17000000, 1054000, []string{efw2c.CodeSSWageBase, efw2c.CodeSSTaxRate}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := codes(domain.MonetaryAmounts{CorrectSocialSecurityWages: tc.wages, CorrectSocialSecurityTax: tc.tax})
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestCheckSSWageCap verifies Box 3 plus Box 7 over the TY2024 wage base
+// is flagged with the overage, and that Check reports it once.
+func TestCheckSSWageCap(t *testing.T) {
+	ys, _ := spec.ForYear(2024) // wage base $168,600.00
+	cases := []struct {
+		name        string
+		wages, tips int64
+		wantOver    int64
+	}{
+		{"under", 16000000, 0, 0},
+		{"at base with tips", 16000000, 860000, 0},
+		{"wages over", 17000000, 0, 140000},
+		{"tips push over", 16000000, 900000, 40000},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warns := efw2c.CheckSSWageCap(ys, domain.MonetaryAmounts{CorrectSocialSecurityWages: tc.wages, CorrectSocialSecurityTips: tc.tips})
+			if tc.wantOver == 0 {
+				if len(warns) != 0 {
+					t.Errorf("want no warning, got %+v", warns)
+				}
+				return
+			}
+			if len(warns) != 1 || warns[0].Code != efw2c.CodeSSWageCap || warns[0].Delta != tc.wantOver || warns[0].Expected != 16860000 {
+				t.Errorf("want one %s warning over by %d, got %+v", efw2c.CodeSSWageCap, tc.wantOver, warns)
+			}
+		})
+	}
+
+	sub := minimalSubmission("2024")
+	a := &sub.Employees[0].Amounts
+	a.CorrectSocialSecurityWages, a.CorrectSocialSecurityTips = 16000000, 900000
+	a.CorrectSocialSecurityTax = 1045320
+	var found int
+	for _, w := range efw2c.MustNew(2024).Check(sub) {
+		if w.Code == efw2c.CodeSSWageCap {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Errorf("Check: want one %s warning, got %d", efw2c.CodeSSWageCap, found)
+	}
+	found = 0
+	for _, f := range efw2c.Audit(sub).Findings {
+		if f.Code == efw2c.CodeSSWageCap && f.Severity == domain.SeverityWarning {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Errorf("Audit: want one %s warning, got %d", efw2c.CodeSSWageCap, found)
+	}
+}
+
+// TestCheckMedicare covers the 1.45% rate and the 0.9% Additional Medicare
+// Tax above $200,000, with expected and delta reported in cents.
+func TestCheckMedicare(t *testing.T) {
+	cases := []struct {
+		name                  string
+		wages, tax            int64
+		wantWarn              bool
+		wantExpected, wantDel int64
+	}{
+		{"base rate", 5000000, 72500, false, 0, 0},
+		{"within tolerance", 5000000, 72400, false, 0, 0},
+		{"base rate off", 5000000, 50000, true, 72500, -22500},
+		{"above threshold", 25000000, 407500, false, 0, 0}, // 362,500 + 45,000
+		{"above threshold, additional missing", 25000000, 362500, true, 407500, -45000},
+		{"at threshold", 20000000, 290000, false, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := efw2c.CheckMedicare(domain.MonetaryAmounts{CorrectMedicareWages: tc.wages, CorrectMedicareTax: tc.tax})
+			if !tc.wantWarn {
+				if len(ws) != 0 {
+					t.Errorf("want no warning, got %+v", ws)
+				}
+				return
+			}
+			if len(ws) != 1 || ws[0].Code != efw2c.CodeMedicareTaxRate {
+				t.Fatalf("want one medicare_tax_rate warning, got %+v", ws)
+			}
+			if ws[0].Expected != tc.wantExpected || ws[0].Delta != tc.wantDel {
+				t.Errorf("want expected %d delta %d, got expected %d delta %d",
+					tc.wantExpected, tc.wantDel, ws[0].Expected, ws[0].Delta)
+			}
+			if !strings.Contains(ws[0].Message, "expected $4,075.00, got $3,625.00") && tc.wages == 25000000 {
+				t.Errorf("message: got %q", ws[0].Message)
+			}
+		})
+	}
+}
+
+// TestCheck_SSTaxExceedsWages verifies a corrected Box 4 larger than the
+// corrected Box 3 is flagged as a warning and does not block generation.
+func TestCheck_SSTaxExceedsWages(t *testing.T) {
+	sub := minimalSubmission("2024")
+	a := &sub.Employees[0].Amounts
+	a.CorrectSocialSecurityWages, a.CorrectSocialSecurityTax = 310000, 5000000 // swapped
+
+	g := efw2c.MustNew(2024)
+	var found bool
+	for _, w := range g.Check(sub) {
+		if w.Code == efw2c.CodeTaxExceedsWages {
+			if w.Field != "SocialSecurityTax" || w.Employee != 0 {
+				t.Errorf("want SocialSecurityTax on employee 0, got %+v", w)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Error("want tax_exceeds_wages warning when SS tax exceeds SS wages")
+	}
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), sub, &buf); err != nil {
+		t.Errorf("Generate: want success, got %v", err)
+	}
+}
+
+// TestWithSandboxMarker verifies the sandbox marker lands in the RCA
+// resubmission fields and leaves every other record byte-identical.
+func TestWithSandboxMarker(t *testing.T) {
+	sub := minimalSubmission("2024")
+	var prod, sandbox bytes.Buffer
+	if err := efw2c.MustNew(2024).Generate(context.Background(), sub, &prod); err != nil {
+		t.Fatalf("Generate (production): %v", err)
+	}
+	if err := efw2c.MustNew(2024, efw2c.WithSandboxMarker("")).Generate(context.Background(), sub, &sandbox); err != nil {
+		t.Fatalf("Generate (sandbox): %v", err)
+	}
+
+	rca := record(sandbox.String(), 0)
+	if got := extract(rca, 1, 3); got != "RCA" {
+		t.Errorf("RecordIdentifier pos 1-3: want 'RCA', got %q", got)
+	}
+	if got := extract(rca, 317, 317); got != "1" {
+		t.Errorf("ResubIndicator pos 317: want '1', got %q", got)
+	}
+	if got := extract(rca, 318, 323); got != efw2c.DefaultSandboxMarker {
+		t.Errorf("ResubWFID pos 318-323: want %q, got %q", efw2c.DefaultSandboxMarker, got)
+	}
+	if got := extract(record(prod.String(), 0), 317, 317); got != "0" {
+		t.Errorf("production ResubIndicator pos 317: want '0', got %q", got)
+	}
+
+	if prod.Len() != sandbox.Len() {
+		t.Fatalf("length: production %d, sandbox %d", prod.Len(), sandbox.Len())
+	}
+	for n := 1; n < prod.Len()/spec.RecordLen; n++ {
+		if p, s := record(prod.String(), n), record(sandbox.String(), n); p != s {
+			t.Errorf("record[%d] (%s) differs from production", n, p[:3])
+		}
+	}
+}
+
+// TestWithLineEnding verifies every record is followed by the chosen
+// terminator and stays 1024 content bytes.
+func TestWithLineEnding(t *testing.T) {
+	// minimalSubmission: RCA, RCE, RCW, RCT, RCF.
+	const n = 5
+	for _, tc := range []struct {
+		name string
+		le   efw2c.LineEnding
+		term string
+	}{
+		{"None", efw2c.None, ""},
+		{"LF", efw2c.LF, "\n"},
+		{"CRLF", efw2c.CRLF, "\r\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := efw2c.MustNew(2024, efw2c.WithLineEnding(tc.le))
+			var buf bytes.Buffer
+			if err := g.Generate(context.Background(), minimalSubmission("2024"), &buf); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			out := buf.String()
+			if want := n * (spec.RecordLen + len(tc.term)); len(out) != want {
+				t.Fatalf("length: want %d, got %d", want, len(out))
+			}
+			stride := spec.RecordLen + len(tc.term)
+			for i := 0; i < n; i++ {
+				rec := out[i*stride : (i+1)*stride]
+				if got := rec[spec.RecordLen:]; got != tc.term {
+					t.Errorf("record %d (%s): want terminator %q, got %q", i+1, rec[:3], tc.term, got)
+				}
+				if strings.ContainsAny(rec[:spec.RecordLen], "\r\n") {
+					t.Errorf("record %d (%s): terminator inside the 1024 content bytes", i+1, rec[:3])
+				}
+			}
+		})
+	}
+}
+
+// TestWithoutRCT verifies the RCT is left out, the RCF still counts every
+// RCW, and an SSA-style file (no line ending) is refused.
+func TestWithoutRCT(t *testing.T) {
+	sub := minimalSubmission("2024")
+	second := sub.Employees[0]
+	second.SSN = "234567890"
+	sub.Employees = append(sub.Employees, second)
+
+	g := efw2c.MustNew(2024, efw2c.WithoutRCT(), efw2c.WithLineEnding(efw2c.LF))
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), sub, &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	var ids []string
+	for _, rec := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		ids = append(ids, rec[:3])
+	}
+	if got, want := strings.Join(ids, " "), "RCA RCE RCW RCW RCF"; got != want {
+		t.Fatalf("records: want %s, got %s", want, got)
+	}
+	rcf := strings.Split(buf.String(), "\n")[4]
+	if got := extract(rcf, 4, 10); got != "0000002" {
+		t.Errorf("RCF TotalRCWRecords: want 0000002, got %q", got)
+	}
+
+	err := efw2c.MustNew(2024, efw2c.WithoutRCT()).Generate(context.Background(), sub, new(bytes.Buffer))
+	if err == nil || !strings.Contains(err.Error(), "SSA requires the RCT") {
+		t.Errorf("without a line ending: want an SSA error, got %v", err)
+	}
+}
+
+// TestGenerateWithManifest verifies the manifest of a two-employee file
+// counts each record type and digests exactly the bytes written.
+func TestGenerateWithManifest(t *testing.T) {
+	sub := minimalSubmission("2024")
+	second := sub.Employees[0]
+	second.SSN = "234567890"
+	sub.Employees = append(sub.Employees, second)
+
+	var buf bytes.Buffer
+	m, err := efw2c.MustNew(2024).GenerateWithManifest(context.Background(), sub, &buf)
+	if err != nil {
+		t.Fatalf("GenerateWithManifest: %v", err)
+	}
+	want := map[string]int{"RCA": 1, "RCE": 1, "RCW": 2, "RCT": 1, "RCF": 1}
+	if fmt.Sprint(m.Records) != fmt.Sprint(want) {
+		t.Errorf("Records: want %v, got %v", want, m.Records)
+	}
+	if sum := sha256.Sum256(buf.Bytes()); m.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("SHA256 %s does not match the file", m.SHA256)
+	}
+	if m.ByteCount != buf.Len() || m.ByteCount != 6*spec.RecordLen {
+		t.Errorf("ByteCount: want %d, got %d", buf.Len(), m.ByteCount)
+	}
+	if m.EIN != "123456789" || m.TaxYear != "2024" || m.GeneratedAt.IsZero() {
+		t.Errorf("got EIN %q, TaxYear %q, GeneratedAt %v", m.EIN, m.TaxYear, m.GeneratedAt)
+	}
+
+	// Line endings and block padding change the bytes, not the counts.
+	g := efw2c.MustNew(2024, efw2c.WithLineEnding(efw2c.CRLF), efw2c.WithBlockPadding(8192))
+	m, err = g.GenerateWithManifest(context.Background(), sub, new(bytes.Buffer))
+	if err != nil {
+		t.Fatalf("GenerateWithManifest (CRLF, padded): %v", err)
+	}
+	if fmt.Sprint(m.Records) != fmt.Sprint(want) || m.ByteCount != 8192 {
+		t.Errorf("CRLF, padded: got %v in %d bytes", m.Records, m.ByteCount)
+	}
+}
+
+// TestWithBlockPadding verifies a 5-record file is padded with spaces to the
+// next multiple of the block size and still parses to the same records.
+func TestWithBlockPadding(t *testing.T) {
+	g := efw2c.MustNew(2024, efw2c.WithBlockPadding(4096))
+	var buf bytes.Buffer
+	res, err := g.GenerateResult(context.Background(), minimalSubmission("2024"), &buf)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	out := buf.String()
+	if len(out) != 8192 || res.ByteCount != 8192 {
+		t.Fatalf("length: want 8192 (5 records padded to 2 blocks), got %d (ByteCount %d)", len(out), res.ByteCount)
+	}
+	if res.RecordCount != 5 {
+		t.Errorf("RecordCount: want 5, got %d", res.RecordCount)
+	}
+	if pad := out[5*spec.RecordLen:]; strings.Trim(pad, " ") != "" {
+		t.Error("padding is not all spaces")
+	}
+	if plain := generate(t, 2024, minimalSubmission("2024")); out[:len(plain)] != plain {
+		t.Error("records before the padding differ from the unpadded file")
+	}
+	if _, err := efw2c.Parse(strings.NewReader(out)); err != nil {
+		t.Errorf("Parse padded file: %v", err)
+	}
+}
+
+// TestParseDefaultYear verifies a supported year is kept, and that a blank,
+// unsupported or malformed value falls back to spec.DefaultYear, logging a
+// warning for the bad ones.
+func TestParseDefaultYear(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	tests := []struct {
+		in   string
+		want int
+		warn bool
+	}{
+		{"", spec.DefaultYear, false},
+		{"2022", 2022, false},
+		{"1999", spec.DefaultYear, true},
+		{"twenty", spec.DefaultYear, true},
+	}
+	for _, tc := range tests {
+		logs.Reset()
+		if got := efw2c.ParseDefaultYear(tc.in); got != tc.want {
+			t.Errorf("ParseDefaultYear(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+		if warned := strings.Contains(logs.String(), "level=WARN"); warned != tc.warn {
+			t.Errorf("ParseDefaultYear(%q) warned = %v, want %v (log %q)", tc.in, warned, tc.warn, logs.String())
+		}
+	}
+	if got := efw2c.MustNew(0).Year(); got != spec.DefaultYear {
+		t.Errorf("MustNew(0).Year() = %d, want %d", got, spec.DefaultYear)
+	}
+}
+
+// TestNewWithSpec_HonorsMovedField swaps the Box 1 orig/correct positions in a
+// cloned TY2024 layout and verifies generation follows the injected spec.
+func TestNewWithSpec_HonorsMovedField(t *testing.T) {
+	base, _ := spec.ForYear(2024)
+	ys := base.Clone()
+	for i := range ys.RCW {
+		switch ys.RCW[i].Name {
+		case "OrigWagesTipsOther":
+			ys.RCW[i].Start, ys.RCW[i].End = 255, 265
+		case "CorrectWagesTipsOther":
+			ys.RCW[i].Start, ys.RCW[i].End = 244, 254
+		}
+	}
+	// Keep the slice ordered by position so the layout stays gapless.
+	for i := range ys.RCW {
+		if ys.RCW[i].Name == "CorrectWagesTipsOther" {
+			ys.RCW[i-1], ys.RCW[i] = ys.RCW[i], ys.RCW[i-1]
+			break
+		}
+	}
+
+	g, err := efw2c.NewWithSpec(2024, ys)
+	if err != nil {
+		t.Fatalf("NewWithSpec: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), minimalSubmission("2024"), &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	rcw := record(buf.String(), 2)
+	if got := extract(rcw, 244, 254); got != "00005100000" {
+		t.Errorf("moved CorrectWagesTipsOther pos 244-254: want '00005100000', got %q", got)
+	}
+	if got := extract(rcw, 255, 265); got != "00005000000" {
+		t.Errorf("moved OrigWagesTipsOther pos 255-265: want '00005000000', got %q", got)
+	}
+
+	// The built-in layout must be untouched.
+	builtin := record(generate(t, 2024, minimalSubmission("2024")), 2)
+	if got := extract(builtin, 244, 254); got != "00005000000" {
+		t.Errorf("built-in TY2024 layout mutated: OrigWagesTipsOther pos 244-254 = %q", got)
+	}
+}
+
+// TestNewWithSpec_FieldWidths resizes RCE fields in a cloned TY2024 layout
+// and verifies Alpha values are space-padded and Numeric values zero-padded
+// to the injected widths.
+func TestNewWithSpec_FieldWidths(t *testing.T) {
+	base, _ := spec.ForYear(2024)
+	ys := base.Clone()
+	for i := range ys.RCE {
+		f := &ys.RCE[i]
+		switch f.Name {
+		case "EmployerName":
+			f.End = 90
+		case "LocationAddress":
+			f.Start = 91
+		case "ZIPExtension":
+			f.End = 179
+		case "Blank178":
+			f.Start = 180
+		}
+	}
+
+	g, err := efw2c.NewWithSpec(2024, ys)
+	if err != nil {
+		t.Fatalf("NewWithSpec: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), minimalSubmission("2024"), &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	rce := record(buf.String(), 1)
+	for _, tc := range []struct {
+		name       string
+		start, end int
+		want       string
+	}{
+		{"EmployerName", 44, 90, "ACME CORP" + strings.Repeat(" ", 38)},
+		{"LocationAddress", 91, 122, "100 MAIN ST" + strings.Repeat(" ", 21)},
+		{"ZIPExtension", 174, 179, "001234"},
+	} {
+		if got := extract(rce, tc.start, tc.end); got != tc.want {
+			t.Errorf("%s pos %d-%d: want %q, got %q", tc.name, tc.start, tc.end, tc.want, got)
+		}
+	}
+}
+
+// TestNewWithSpec_ContactWidths narrows the RCA contact e-mail in a cloned
+// TY2024 layout and verifies an address that fits the built-in 40
+// characters overflows the injected width instead.
+func TestNewWithSpec_ContactWidths(t *testing.T) {
+	base, _ := spec.ForYear(2024)
+	ys := base.Clone()
+	for i := range ys.RCA {
+		f := &ys.RCA[i]
+		switch f.Name {
+		case "ContactEmail":
+			f.End = 291
+		case "Blank302":
+			f.Start = 292
+		}
+	}
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	sub.Submitter.ContactEmail = "payroll.corrections@example.com" // 31 chars
+
+	if errs := efw2c.MustNew(2024).Validate(sub); len(errs) != 0 {
+		t.Fatalf("built-in layout: want no errors, got %v", errs)
+	}
+	g, err := efw2c.NewWithSpec(2024, ys)
+	if err != nil {
+		t.Fatalf("NewWithSpec: %v", err)
+	}
+	var found bool
+	for _, e := range g.Validate(sub) {
+		if e.Code == efw2c.CodeFieldOverflow && e.Field == "ContactEmail" && e.Limit == "30 characters" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("want field_overflow on ContactEmail at the injected 30 characters")
+	}
+
+	sub.Submitter.ContactEmail = "jane@example.com"
+	var buf bytes.Buffer
+	if err := g.Generate(context.Background(), sub, &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if got, want := extract(record(buf.String(), 0), 262, 291), "jane@example.com"+strings.Repeat(" ", 14); got != want {
+		t.Errorf("ContactEmail: want %q, got %q", want, got)
+	}
+}
+
+// TestNewWithSpec_RejectsGap verifies a layout with a gap is refused.
+func TestNewWithSpec_RejectsGap(t *testing.T) {
+	base, _ := spec.ForYear(2024)
+	ys := base.Clone()
+	ys.RCW[1].Start++ // opens a one-byte gap after RecordIdentifier
+	if _, err := efw2c.NewWithSpec(2024, ys); err == nil {
+		t.Error("NewWithSpec: want error for gapped layout, got nil")
+	}
+}
+
+// TestParse_RoundTrip verifies that re-generating a parsed file reproduces
+// it byte for byte, for every supported year.
+func TestParse_RoundTrip(t *testing.T) {
+	for _, year := range spec.Supported() {
+		year := year
+		t.Run(fmt.Sprintf("TY%d", year), func(t *testing.T) {
+			for name, sub := range map[string]*domain.Submission{
+				"minimal": minimalSubmission(fmt.Sprintf("%d", year)),
+				"maximal": maximalSubmission(fmt.Sprintf("%d", year)),
+			} {
+				want := generate(t, year, sub)
+				parsed, err := efw2c.Parse(strings.NewReader(want))
+				if err != nil {
+					t.Fatalf("%s: Parse: %v", name, err)
+				}
+				if got := generate(t, year, parsed); got != want {
+					for n := 1; n <= len(want)/spec.RecordLen; n++ {
+						if w, g := record(want, n), record(got, n); w != g {
+							t.Errorf("%s: record %d (%s) differs after round trip:\nwant %q\ngot  %q", name, n, w[:3], trimR(w), trimR(g))
+						}
+					}
+					t.Fatalf("%s: round trip: want %d bytes, got %d", name, len(want), len(got))
+				}
+			}
+		})
+	}
+}
+
+// TestParse_Fields spot-checks parsed values, including the SSN swap and
+// the CRLF terminators some tools add between records.
+func TestParse_Fields(t *testing.T) {
+	out := generate(t, 2024, maximalSubmission("2024"))
+	var crlf strings.Builder
+	for off := 0; off < len(out); off += spec.RecordLen {
+		crlf.WriteString(out[off:off+spec.RecordLen] + "\r\n")
+	}
+	s, err := efw2c.Parse(strings.NewReader(crlf.String()))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.Employer.EIN != "123456789" || s.Employer.OriginalEIN != "111222333" || s.Employer.TaxYear != "2024" {
+		t.Errorf("Employer: got EIN=%q OriginalEIN=%q TaxYear=%q", s.Employer.EIN, s.Employer.OriginalEIN, s.Employer.TaxYear)
+	}
+	if s.Submitter.CompanyName != "" {
+		t.Errorf("Submitter.CompanyName: want blank (same as employer), got %q", s.Submitter.CompanyName)
+	}
+	if len(s.Employees) != 1 {
+		t.Fatalf("Employees: want 1, got %d", len(s.Employees))
+	}
+	e := s.Employees[0]
+	if e.SSN != "987654321" || e.OriginalSSN != "123121234" {
+		t.Errorf("SSN: want SSN=987654321 OriginalSSN=123121234, got %q %q", e.SSN, e.OriginalSSN)
+	}
+	if e.Amounts.CorrectWagesTipsOther != 5100000 || e.Amounts.CorrectAllocatedTips != 2000 || e.Amounts.CorrectStateWages != 2000 {
+		t.Errorf("Amounts: got wages=%d allocated tips=%d state wages=%d",
+			e.Amounts.CorrectWagesTipsOther, e.Amounts.CorrectAllocatedTips, e.Amounts.CorrectStateWages)
+	}
+	if e.CorrectStateCode != "IN" {
+		t.Errorf("CorrectStateCode: want IN, got %q", e.CorrectStateCode)
+	}
+	if e.Box13.CorrectRetirementPlan == nil || !*e.Box13.CorrectRetirementPlan {
+		t.Error("Box13.CorrectRetirementPlan: want true")
+	}
+}
+
+// TestParse_Errors verifies truncated and unrecognised records are refused.
+func TestParse_Errors(t *testing.T) {
+	out := generate(t, 2024, minimalSubmission("2024"))
+	cases := map[string]string{
+		"short record":   out[:len(out)-10],
+		"unknown id":     out[:spec.RecordLen] + "RCX" + out[spec.RecordLen+3:],
+		"RCO before RCW": out[:spec.RecordLen*2] + "RCO" + strings.Repeat(" ", spec.RecordLen-3) + out[spec.RecordLen*2:],
+	}
+	for name, in := range cases {
+		if _, err := efw2c.Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: want error, got nil", name)
+		}
+	}
+}
+
+// TestGenerate_FieldOutOfBounds corrupts a spec after the generator has
+// accepted it, so a totals field runs past byte 1024, and expects a clear
+// bounds error with nothing written.
+func TestGenerate_FieldOutOfBounds(t *testing.T) {
+	base, _ := spec.ForYear(2024)
+	ys := base.Clone()
+	g, err := efw2c.NewWithSpec(2024, ys)
+	if err != nil {
+		t.Fatalf("NewWithSpec: %v", err)
+	}
+	for i := range ys.RCT {
+		if ys.RCT[i].Name == "OrigTotalWagesTips" {
+			ys.RCT[i].Start, ys.RCT[i].End = 1020, 1034
+		}
+	}
+
+	var buf bytes.Buffer
+	err = g.Generate(context.Background(), minimalSubmission("2024"), &buf)
+	if err == nil || !strings.Contains(err.Error(), `field "OrigTotalWagesTips" at 1020-1034 is outside the 1024-byte record`) {
+		t.Fatalf("want bounds error for OrigTotalWagesTips, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("want nothing written, got %d bytes", buf.Len())
+	}
+}
+
+// TestGenerate_ForeignAddress verifies a Canadian employer and employee
+// write the province, postal code and country to the foreign-address
+// fields and leave the domestic state and ZIP blank.
+func TestGenerate_ForeignAddress(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employer.State, sub.Employer.ZIP, sub.Employer.ZIPExtension = "IL", "62701", "1234"
+	sub.Employer.City = "OTTAWA"
+	sub.Employer.ForeignStateProvince = "ONTARIO"
+	sub.Employer.ForeignPostalCode = "K1A 0B1"
+	sub.Employer.CountryCode = "CA"
+	e := &sub.Employees[0]
+	e.AddressLine1, e.City = "1 RUE PRINCIPALE", "MONTREAL"
+	e.State, e.ZIP = "IL", "62701"
+	e.ForeignStateProvince = "QUEBEC"
+	e.ForeignPostalCode = "H2X 1Y4"
+	e.CountryCode = "CA"
+
+	out := generate(t, 2024, sub)
+	cases := []struct {
+		rec        int
+		name       string
+		start, end int
+		want       string
+	}{
+		// RCA: the submitter has no address, so the employer's is used.
+		{0, "RCA StateAbbrev", 155, 156, "  "},
+		{0, "RCA ZIPCode", 157, 161, "     "},
+		{0, "RCA ForeignStateProvince", 172, 194, fmt.Sprintf("%-23s", "ONTARIO")},
+		{0, "RCA ForeignPostalCode", 195, 209, fmt.Sprintf("%-15s", "K1A 0B1")},
+		{0, "RCA CountryCode", 210, 211, "CA"},
+		{1, "RCE StateAbbrev", 167, 168, "  "},
+		{1, "RCE ZIPCode", 169, 173, "     "},
+		{1, "RCE ForeignStateProvince", 182, 204, fmt.Sprintf("%-23s", "ONTARIO")},
+		{1, "RCE ForeignPostalCode", 205, 219, fmt.Sprintf("%-15s", "K1A 0B1")},
+		{1, "RCE CountryCode", 220, 221, "CA"},
+		{2, "RCW StateAbbrev", 188, 189, "  "},
+		{2, "RCW ZIPCode", 190, 194, "     "},
+		{2, "RCW ForeignStateProvince", 204, 226, fmt.Sprintf("%-23s", "QUEBEC")},
+		{2, "RCW ForeignPostalCode", 227, 241, fmt.Sprintf("%-15s", "H2X 1Y4")},
+		{2, "RCW CountryCode", 242, 243, "CA"},
+	}
+	for _, c := range cases {
+		if got := extract(record(out, c.rec), c.start, c.end); got != c.want {
+			t.Errorf("%s (%d-%d) = %q, want %q", c.name, c.start, c.end, got, c.want)
+		}
+	}
+
+	// A US country code is a domestic address.
+	sub.Employer.CountryCode = "US"
+	out = generate(t, 2024, sub)
+	if got := extract(record(out, 1), 167, 168); got != "IL" {
+		t.Errorf("RCE StateAbbrev with CountryCode US = %q, want IL", got)
+	}
+	if got := extract(record(out, 1), 220, 221); got != "  " {
+		t.Errorf("RCE CountryCode with CountryCode US = %q, want blank", got)
+	}
+}
+
+// TestGenerate_NameSuffix verifies suffixes are appended to the correct and
+// original last-name fields, and that a name too long to take its suffix
+// is still cut to exactly 20 characters under TrimTruncate.
+func TestGenerate_NameSuffix(t *testing.T) {
+	sub := minimalSubmission("2024")
+	e := &sub.Employees[0]
+	e.Suffix = "JR"
+	e.OriginalFirstName, e.OriginalLastName, e.OriginalSuffix = "JON", "SMYTHE", "jr"
+
+	rcw := record(generate(t, 2024, sub), 2)
+	if got, want := extract(rcw, 52, 71), fmt.Sprintf("%-20s", "SMYTHE JR"); got != want {
+		t.Errorf("OrigLastName = %q, want %q", got, want)
+	}
+	if got, want := extract(rcw, 102, 121), fmt.Sprintf("%-20s", "SMITH JR"); got != want {
+		t.Errorf("CorrectLastName = %q, want %q", got, want)
+	}
+
+	e.LastName = "WOLFESCHLEGELSTEINH" // 19 chars; with " JR" it is 22
+	var buf bytes.Buffer
+	g := efw2c.MustNew(2024, efw2c.WithTrimPolicy(efw2c.TrimTruncate))
+	if err := g.Generate(context.Background(), sub, &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	out := buf.String()
+	if len(out)%spec.RecordLen != 0 {
+		t.Fatalf("output length %d is not a multiple of %d", len(out), spec.RecordLen)
+	}
+	if got, want := extract(record(out, 2), 102, 121), "WOLFESCHLEGELSTEINH "; got != want {
+		t.Errorf("truncated CorrectLastName = %q, want %q", got, want)
+	}
+	if got := extract(record(out, 2), 122, 122); got != " " {
+		t.Errorf("LocationAddress start = %q, want blank: truncation spilled over", got)
+	}
+}
+
+// TestGenerate_Resubmission verifies the RCA resubmission fields: the
+// indicator defaults to 0 with a blank WFID, a resubmission writes 1 at 317
+// and the WFID at 318-323, and a WFID over 6 characters is cut to 6 under
+// TrimTruncate.
+func TestGenerate_Resubmission(t *testing.T) {
+	sub := minimalSubmission("2024")
+	rca := record(generate(t, 2024, sub), 0)
+	if got := extract(rca, 317, 323); got != "0      " {
+		t.Errorf("original file: ResubIndicator+WFID = %q, want \"0\" and blank", got)
+	}
+
+	sub.Submitter.ResubIndicator, sub.Submitter.ResubWFID = "1", "abc123"
+	rca = record(generate(t, 2024, sub), 0)
+	if got := extract(rca, 317, 317); got != "1" {
+		t.Errorf("ResubIndicator pos 317: want '1', got %q", got)
+	}
+	if got := extract(rca, 318, 323); got != "ABC123" {
+		t.Errorf("ResubWFID pos 318-323: want 'ABC123', got %q", got)
+	}
+
+	sub.Submitter.ResubWFID = "ABC1234"
+	var buf bytes.Buffer
+	if err := efw2c.MustNew(2024, efw2c.WithTrimPolicy(efw2c.TrimTruncate)).Generate(context.Background(), sub, &buf); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	rca = record(buf.String(), 0)
+	if got := extract(rca, 318, 323); got != "ABC123" {
+		t.Errorf("truncated ResubWFID = %q, want 'ABC123'", got)
+	}
+	if got := extract(rca, 324, 324); got != " " {
+		t.Errorf("pos 324 = %q, want blank: WFID spilled over", got)
+	}
+}
+
+// TestAnnotate verifies the dump of a minimal submission has one entry per
+// record in file order, each field's value is the record text at its
+// positions, and RecordIdentifier holds the record's 3-character id.
+func TestAnnotate(t *testing.T) {
+	sub := minimalSubmission("2024")
+	out := generate(t, 2024, sub)
+	dumps, err := efw2c.Annotate(sub)
+	if err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+	want := []string{"RCA", "RCE", "RCW", "RCT", "RCF"}
+	if len(dumps) != len(want) {
+		t.Fatalf("got %d records, want %d", len(dumps), len(want))
+	}
+	for i, d := range dumps {
+		if d.ID != want[i] || d.Index != i {
+			t.Errorf("record %d: got %s (index %d), want %s", i, d.ID, d.Index, want[i])
+		}
+		if f := d.Fields[0]; f.Name != "RecordIdentifier" || f.Start != 1 || f.End != 3 || f.Value != want[i] {
+			t.Errorf("%s: first field = %+v, want RecordIdentifier 1-3 %q", want[i], f, want[i])
+		}
+		rec, n := record(out, i), 0
+		for _, f := range d.Fields {
+			if got := extract(rec, f.Start, f.End); f.Value != got {
+				t.Errorf("%s %s: value %q, record has %q", d.ID, f.Name, f.Value, got)
+			}
+			n += len(f.Value)
+		}
+		if n != spec.RecordLen {
+			t.Errorf("%s: fields cover %d bytes, want %d", d.ID, n, spec.RecordLen)
+		}
+	}
+}
+
+// TestGenerate_RCEThirdPartySick verifies the RCE third-party sick pay
+// indicators land at 224 (orig) and 225 (correct), blank when unset.
+func TestGenerate_RCEThirdPartySick(t *testing.T) {
+	sub := minimalSubmission("2024")
+	if got := extract(record(generate(t, 2024, sub), 1), 224, 225); got != "  " {
+		t.Errorf("unset indicators = %q, want blank", got)
+	}
+
+	sub.Employer.OriginalThirdPartySick, sub.Employer.CorrectThirdPartySick = "0", "1"
+	rce := record(generate(t, 2024, sub), 1)
+	if got := extract(rce, 224, 224); got != "0" {
+		t.Errorf("OrigThirdPartySick (224) = %q, want 0", got)
+	}
+	if got := extract(rce, 225, 225); got != "1" {
+		t.Errorf("CorrectThirdPartySick (225) = %q, want 1", got)
+	}
+}
+
+// TestGenerate_RCETerminatingBusiness verifies TerminatingBusiness changes
+// nothing in the file: the EFW2C RCE has no terminating-business position,
+// and SSA rejects a non-blank reserved position.
+func TestGenerate_RCETerminatingBusiness(t *testing.T) {
+	sub := minimalSubmission("2024")
+	want := record(generate(t, 2024, sub), 1)
+	sub.Employer.TerminatingBusiness = true
+	rce := record(generate(t, 2024, sub), 1)
+	if len(rce) != spec.RecordLen {
+		t.Fatalf("RCE is %d bytes, want %d", len(rce), spec.RecordLen)
+	}
+	if rce != want {
+		t.Errorf("TerminatingBusiness changed the RCE:\n got %q\nwant %q", strings.TrimRight(rce, " "), strings.TrimRight(want, " "))
+	}
+}
+
+// TestAudit_Clean verifies a valid submission audits with no errors or
+// warnings, only the informational record count.
+func TestAudit_Clean(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Employees[0].SSN = "123456789"
+	rep := efw2c.Audit(sub)
+	if !rep.Clean() {
+		t.Fatalf("want a clean audit, got %+v", rep.Findings)
+	}
+	if n := rep.Count(domain.SeverityWarning); n != 0 {
+		t.Errorf("want no warnings, got %d: %+v", n, rep.Findings)
+	}
+	if len(rep.Findings) != 1 || rep.Findings[0].Code != efw2c.CodeRecordCounts {
+		t.Errorf("want only the record_counts note, got %+v", rep.Findings)
+	}
+}
+
+// TestAudit_Broken verifies a submission with several problems reports each
+// one with its severity and record, errors before warnings before info.
+func TestAudit_Broken(t *testing.T) {
+	sub := minimalSubmission("2024")
+	sub.Submitter.BSOUID = ""
+	sub.Employer.EmploymentCode = "Q"
+	sub.Employer.KindOfEmployer = ""
+	sub.Employees[0].SSN = "000000000"
+	sub.Employees[0].Amounts.CorrectSocialSecurityTax = 400000 // not 6.2% of wages
+	sub.Employees = append(sub.Employees, domain.EmployeeRecord{SSN: "123456789", FirstName: "JANE", LastName: "ROE"})
+
+	rep := efw2c.Audit(sub)
+	if rep.Clean() {
+		t.Fatal("want errors, got a clean audit")
+	}
+	type key struct {
+		sev      domain.Severity
+		code     string
+		record   string
+		employee int
+	}
+	got := make(map[key]bool)
+	for _, f := range rep.Findings {
+		got[key{f.Severity, f.Code, f.Record, f.Employee}] = true
+	}
+	for _, want := range []key{
+		{domain.SeverityError, efw2c.CodeInvalidBSOUID, "RCA", -1},
+		{domain.SeverityError, efw2c.CodeInvalidSSN, "RCW", 0},
+		{domain.SeverityError, efw2c.CodeEmploymentMismatch, "RCW", 0},
+		{domain.SeverityWarning, efw2c.CodeSSTaxRate, "RCW", 0},
+		{domain.SeverityError, efw2c.CodeNoCorrection, "RCW", 1},
+		{domain.SeverityInfo, efw2c.CodeDefaultApplied, "RCE", -1},
+	} {
+		if !got[want] {
+			t.Errorf("missing finding %+v in %+v", want, rep.Findings)
+		}
+	}
+	rank := map[domain.Severity]int{domain.SeverityError: 0, domain.SeverityWarning: 1, domain.SeverityInfo: 2}
+	for i := 1; i < len(rep.Findings); i++ {
+		if rank[rep.Findings[i-1].Severity] > rank[rep.Findings[i].Severity] {
+			t.Fatalf("findings out of severity order at %d: %+v", i, rep.Findings)
+		}
+	}
+}
+
+// TestAudit_ThirdPartySickMismatch verifies the RCE third-party sick pay
+// indicator is checked against the employees' Box 13 corrections.
+func TestAudit_ThirdPartySickMismatch(t *testing.T) {
+	checked := true
+	tests := []struct {
+		name      string
+		employee  *bool
+		indicator string
+		want      string // message prefix; "" for no finding
+	}{
+		{"employee checked, RCE blank", &checked, "", "RCE indicator missing"},
+		{"employee checked, RCE 0", &checked, "0", "1 employee(s)"},
+		{"RCE 1, no employee", nil, "1", "RCE third-party sick pay indicator is corrected to 1"},
+		{"both set", &checked, "1", ""},
+		{"neither set", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := minimalSubmission("2024")
+			sub.Employees[0].SSN = "123456789"
+			sub.Employees[0].Box13.CorrectThirdPartySickPay = tt.employee
+			sub.Employer.CorrectThirdPartySick = tt.indicator
+			rep := efw2c.Audit(sub)
+			var got *efw2c.AuditFinding
+			for i := range rep.Findings {
+				if rep.Findings[i].Code == efw2c.CodeThirdPartySick {
+					got = &rep.Findings[i]
+				}
+			}
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("want no finding, got %+v", *got)
+			case tt.want != "" && got == nil:
+				t.Errorf("want a %s finding, got none", efw2c.CodeThirdPartySick)
+			case got != nil && (!strings.HasPrefix(got.Message, tt.want) || got.Record != "RCE" || got.Severity != domain.SeverityWarning):
+				t.Errorf("got %+v, want an RCE warning starting %q", *got, tt.want)
+			}
+		})
+	}
+}
+
+// heapSampler is an io.Writer that discards output, collecting garbage and
+// noting the live heap every 1000 writes so a benchmark can report the
+// peak while streaming.
+type heapSampler struct {
+	writes int
+	peak   uint64
+}
+
+func (h *heapSampler) Write(p []byte) (int, error) {
+	if h.writes++; h.writes%1000 == 0 {
+		runtime.GC()
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		h.peak = max(h.peak, m.HeapAlloc)
+	}
+	return len(p), nil
+}
+
+// BenchmarkGenerate_Streaming generates files for 1k and 10k employees.
+// Records are streamed, so heap-over-input-KB (the live heap above the
+// submission itself while writing) should stay flat as employees grow,
+// while the file grows tenfold:
+//
+//	go test ./internal/adapters/efw2c -run '^$' -bench Streaming
+func BenchmarkGenerate_Streaming(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("employees=%d", n), func(b *testing.B) {
+			sub := minimalSubmission("2024")
+			e := sub.Employees[0]
+			sub.Employees = make([]domain.EmployeeRecord, n)
+			for i := range sub.Employees {
+				sub.Employees[i] = e
+				sub.Employees[i].SSN = fmt.Sprintf("123%06d", i+1)
+			}
+			g := efw2c.MustNew(2024)
+			b.ReportAllocs()
+			b.ResetTimer()
+			var over uint64
+			for range b.N {
+				runtime.GC()
+				var m runtime.MemStats
+				runtime.ReadMemStats(&m)
+				w := &heapSampler{}
+				if err := g.Generate(context.Background(), sub, w); err != nil {
+					b.Fatal(err)
+				}
+				if w.peak > m.HeapAlloc {
+					over = max(over, w.peak-m.HeapAlloc)
+				}
+			}
+			b.ReportMetric(float64(over)/1024, "heap-over-input-KB")
+		})
+	}
+}
+
+// cancelAfter is an io.Writer that cancels its context once n records
+// have been written to it.
+type cancelAfter struct {
+	buf    bytes.Buffer
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Write(p []byte) (int, error) {
+	n, err := c.buf.Write(p)
+	if c.buf.Len() >= c.n*spec.RecordLen {
+		c.cancel()
+	}
+	return n, err
+}
+
+// TestGenerate_Cancelled verifies Generate stops writing and returns
+// context.Canceled when its context is cancelled after the first employee.
+func TestGenerate_Cancelled(t *testing.T) {
+	sub := minimalSubmission("2024")
+	for i := 2; i <= 5; i++ {
+		e := sub.Employees[0]
+		e.SSN = fmt.Sprintf("12345678%d", i)
+		sub.Employees = append(sub.Employees, e)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelAfter{n: 3, cancel: cancel} // RCA, RCE, first RCW
+
+	err := efw2c.MustNew(2024).Generate(ctx, sub, w)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Generate: want context.Canceled, got %v", err)
+	}
+	if got := recordIDs(w.buf.String()); strings.Join(got, ",") != "RCA,RCE,RCW" {
+		t.Errorf("records written = %v, want RCA,RCE,RCW", got)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := efw2c.MustNew(2024).Generate(ctx, sub, &buf); !errors.Is(err, context.Canceled) || buf.Len() != 0 {
+		t.Errorf("already cancelled: want context.Canceled and nothing written, got %v with %d bytes", err, buf.Len())
+	}
+}
@@ -0,0 +1,66 @@
+package efw2c
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// Manifest describes a generated file; see domain.Manifest.
+type Manifest = domain.Manifest
+
+// GenerateWithManifest is Generate, also returning a Manifest of the bytes
+// written to w: their SHA-256, length and record counts by type. On error
+// no manifest is returned, since w may hold a partial file. Satisfies
+// ports.EFW2CGenerator.
+func (g *Generator) GenerateWithManifest(ctx context.Context, s *domain.Submission, w io.Writer) (*Manifest, error) {
+	sum := sha256.New()
+	rc := &recordCounter{
+		stride: spec.RecordLen + len(g.lineEnding.terminator()),
+		counts: make(map[string]int),
+	}
+	res, err := g.GenerateResult(ctx, s, io.MultiWriter(w, sum, rc))
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{
+		SHA256:      hex.EncodeToString(sum.Sum(nil)),
+		ByteCount:   res.ByteCount,
+		Records:     rc.counts,
+		EIN:         s.Employer.EIN,
+		TaxYear:     s.Employer.TaxYear,
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// recordCounter is an io.Writer that counts records by identifier: the
+// first three bytes of every stride-byte record. WithBlockPadding's blank
+// padding has no identifier and is not counted.
+type recordCounter struct {
+	stride int
+	off    int
+	id     []byte
+	counts map[string]int
+}
+
+func (c *recordCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if c.off%c.stride < 3 {
+			c.id = append(c.id, b)
+			if len(c.id) == 3 {
+				if id := string(c.id); strings.TrimSpace(id) != "" {
+					c.counts[id]++
+				}
+				c.id = c.id[:0]
+			}
+		}
+		c.off++
+	}
+	return len(p), nil
+}
@@ -0,0 +1,437 @@
+package efw2c
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// Parse reads an EFW2C file with the package-level Parse; the spec comes
+// from the file's RCE tax year, not g. Satisfies ports.EFW2CGenerator.
+func (g *Generator) Parse(r io.Reader) (*domain.Submission, error) {
+	return Parse(r)
+}
+
+// ParseEmployees reads RCW (RCO, RCS) records with the package-level
+// ParseEmployees. Satisfies ports.EFW2CGenerator.
+func (g *Generator) ParseEmployees(r io.Reader, year int) ([]domain.EmployeeRecord, error) {
+	return ParseEmployees(r, year)
+}
+
+// Parse reads an EFW2C stream (as written by Generate) back into a
+// Submission. The layout is chosen from the RCE tax year. Submitter
+// company name and address are left blank when they match the employer's,
+// mirroring the fallback Generate applies, so re-generating a parsed file
+// reproduces it byte for byte.
+//
+// Each RCE after the first starts a block in AdditionalEmployers, so
+// multi-employer files round-trip too.
+//
+// Line terminators between records are skipped. Parse fails if a record is
+// not spec.RecordLen bytes, has an unknown identifier, or an RCO/RCS
+// appears before any RCW of its employer.
+func Parse(r io.Reader) (*domain.Submission, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The layout depends on the tax year, which lives in the RCE; its
+	// position is the same in every supported year.
+	ys, _ := spec.ForYear(spec.DefaultYear)
+	for _, rec := range records {
+		if rec[:3] == "RCE" {
+			if year, err := strconv.Atoi(field(rec, ys.RCE, "TaxYear")); err == nil {
+				ys, _ = spec.ForYear(year)
+			}
+			break
+		}
+	}
+
+	s := &domain.Submission{}
+	var rca string
+	seenRCE := false
+	// employees returns the employee list of the current RCE.
+	employees := func() *[]domain.EmployeeRecord {
+		if n := len(s.AdditionalEmployers); n > 0 {
+			return &s.AdditionalEmployers[n-1].Employees
+		}
+		return &s.Employees
+	}
+	for n, rec := range records {
+		switch id := rec[:3]; id {
+		case "RCA":
+			rca = rec
+		case "RCE":
+			if !seenRCE {
+				parseRCE(rec, ys, &s.Employer)
+				seenRCE = true
+				break
+			}
+			var b domain.EmployerBlock
+			parseRCE(rec, ys, &b.Employer)
+			s.AdditionalEmployers = append(s.AdditionalEmployers, b)
+		case "RCW", "RCO", "RCS":
+			if err := parseEmployeeRecord(employees(), n, rec, ys); err != nil {
+				return nil, err
+			}
+		case "RCT", "RCU", "RCF":
+			// Totals are derived from the RCW and RCO records.
+		default:
+			return nil, fmt.Errorf("efw2c: record %d: unknown record identifier %q", n+1, id)
+		}
+	}
+	if rca != "" {
+		parseRCA(rca, ys, s)
+	}
+	return s, nil
+}
+
+// ParseEmployees reads the employees from a block of RCW records, each
+// optionally followed by its RCO and RCS, using the layout for year — for
+// example records cut from a rejected file. Any RCA, RCE, RCT, RCU or RCF
+// records in the block are skipped.
+//
+// Pasted text is accepted one record per line with trailing blanks
+// trimmed; a line longer than spec.RecordLen is an error.
+func ParseEmployees(r io.Reader, year int) ([]domain.EmployeeRecord, error) {
+	ys, ok := spec.ForYear(year)
+	if !ok {
+		return nil, fmt.Errorf("efw2c: no spec for TY%d", year)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var records []string
+	if strings.ContainsRune(string(data), '\n') {
+		records, err = padLines(string(data))
+	} else {
+		records, err = readRecords(strings.NewReader(string(data)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var emps []domain.EmployeeRecord
+	for n, rec := range records {
+		switch id := rec[:3]; id {
+		case "RCW", "RCO", "RCS":
+			if err := parseEmployeeRecord(&emps, n, rec, ys); err != nil {
+				return nil, err
+			}
+		case "RCA", "RCE", "RCT", "RCU", "RCF":
+		default:
+			return nil, fmt.Errorf("efw2c: record %d: unknown record identifier %q", n+1, id)
+		}
+	}
+	if len(emps) == 0 {
+		return nil, fmt.Errorf("efw2c: no RCW records found")
+	}
+	return emps, nil
+}
+
+// parseEmployeeRecord decodes record n (0-based) — an RCW, or an RCO/RCS
+// belonging to the last RCW in emps.
+func parseEmployeeRecord(emps *[]domain.EmployeeRecord, n int, rec string, ys *spec.YearSpec) error {
+	id := rec[:3]
+	if id == "RCW" {
+		e, err := parseRCW(rec, ys)
+		if err != nil {
+			return fmt.Errorf("efw2c: record %d (RCW): %w", n+1, err)
+		}
+		*emps = append(*emps, e)
+		return nil
+	}
+	if len(*emps) == 0 {
+		return fmt.Errorf("efw2c: record %d: %s before any RCW", n+1, id)
+	}
+	e := &(*emps)[len(*emps)-1]
+	var err error
+	if id == "RCO" {
+		err = parseRCO(rec, ys, e)
+	} else {
+		err = parseRCS(rec, ys, e)
+	}
+	if err != nil {
+		return fmt.Errorf("efw2c: record %d (%s): %w", n+1, id, err)
+	}
+	return nil
+}
+
+// padLines splits newline-delimited records, restoring trailing blanks an
+// editor or text box may have trimmed. Blank lines are skipped.
+func padLines(text string) ([]string, error) {
+	var records []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) > spec.RecordLen {
+			return nil, fmt.Errorf("efw2c: record %d is %d bytes (want at most %d)", len(records)+1, len(line), spec.RecordLen)
+		}
+		records = append(records, line+strings.Repeat(" ", spec.RecordLen-len(line)))
+	}
+	return records, nil
+}
+
+// readRecords splits r into spec.RecordLen-byte records, skipping CR/LF
+// terminators between them and all-blank block padding.
+func readRecords(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	var records []string
+	buf := make([]byte, spec.RecordLen)
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c == '\r' || c == '\n' {
+			continue
+		}
+		buf[0] = c
+		n, err := io.ReadFull(br, buf[1:])
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			if isBlank(buf[:n+1]) {
+				return records, nil // WithBlockPadding filler
+			}
+			return nil, fmt.Errorf("efw2c: record %d is %d bytes (want %d)", len(records)+1, n+1, spec.RecordLen)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec := string(buf)
+		if i := strings.IndexAny(rec, "\r\n"); i >= 0 {
+			return nil, fmt.Errorf("efw2c: record %d is %d bytes (want %d)", len(records)+1, i, spec.RecordLen)
+		}
+		if isBlank(buf) {
+			continue // WithBlockPadding filler
+		}
+		records = append(records, rec)
+	}
+}
+
+// isBlank reports whether b is all spaces.
+func isBlank(b []byte) bool {
+	return strings.TrimLeft(string(b), " ") == ""
+}
+
+func parseRCA(rec string, ys *spec.YearSpec, s *domain.Submission) {
+	f := func(name string) string { return field(rec, ys.RCA, name) }
+	sub := &s.Submitter
+	sub.BSOUID = f("BSOUID")
+	emp := &s.Employer
+	if name := f("CompanyName"); name != emp.Name {
+		sub.CompanyName = name
+	}
+	addr := [6]string{f("LocationAddress"), f("DeliveryAddress"), f("City"), f("StateAbbrev"), f("ZIPCode"), f("ZIPExtension")}
+	if addr != [6]string{emp.AddressLine1, emp.AddressLine2, emp.City, emp.State, emp.ZIP, emp.ZIPExtension} {
+		sub.AddressLine1, sub.AddressLine2, sub.City = addr[0], addr[1], addr[2]
+		sub.State, sub.ZIP, sub.ZIPExtension = addr[3], addr[4], addr[5]
+	}
+	sub.ContactName = f("ContactName")
+	sub.ContactPhone = f("ContactPhone")
+	sub.PhoneExtension = f("PhoneExtension")
+	sub.ContactFax = f("ContactFax")
+	sub.ContactEmail = f("ContactEmail")
+	sub.PreparerCode = f("PreparerCode")
+	sub.ResubIndicator = f("ResubIndicator")
+	sub.ResubWFID = f("ResubWFID")
+	sub.SoftwareCode = f("SoftwareCode")
+	sub.SoftwareVendorCode = f("SoftwareVendorCode")
+}
+
+func parseRCE(rec string, ys *spec.YearSpec, emp *domain.EmployerRecord) {
+	f := func(name string) string { return field(rec, ys.RCE, name) }
+	emp.TaxYear = f("TaxYear")
+	emp.OriginalEIN = f("OrigReportedEIN")
+	emp.EIN = f("EmployerEIN")
+	emp.AgentIndicator = f("AgentIndicatorCode")
+	emp.AgentEIN = f("AgentForEIN")
+	emp.Name = f("EmployerName")
+	emp.AddressLine1 = f("LocationAddress")
+	emp.AddressLine2 = f("DeliveryAddress")
+	emp.City = f("City")
+	emp.State = f("StateAbbrev")
+	emp.ZIP = f("ZIPCode")
+	emp.ZIPExtension = f("ZIPExtension")
+	emp.ForeignStateProvince = f("ForeignStateProvince")
+	emp.ForeignPostalCode = f("ForeignPostalCode")
+	emp.CountryCode = f("CountryCode")
+	emp.EmploymentCode = f("CorrectEmploymentCode")
+	emp.KindOfEmployer = f("KindOfEmployer")
+	emp.ContactName = f("ContactName")
+	emp.ContactPhone = f("ContactPhone")
+	emp.ContactPhoneExtension = f("PhoneExtension")
+	emp.ContactEmail = f("ContactEmail")
+	emp.OriginalThirdPartySick = f("OrigThirdPartySick")
+	emp.CorrectThirdPartySick = f("CorrectThirdPartySick")
+}
+
+func parseRCW(rec string, ys *spec.YearSpec) (domain.EmployeeRecord, error) {
+	f := func(name string) string { return field(rec, ys.RCW, name) }
+	var e domain.EmployeeRecord
+	e.SSN = f("OrigSSN")
+	if corr := f("CorrectSSN"); corr != "" {
+		e.OriginalSSN, e.SSN = e.SSN, corr
+	}
+	e.OriginalFirstName = f("OrigFirstName")
+	e.OriginalMiddleName = f("OrigMiddleName")
+	e.OriginalLastName, e.OriginalSuffix = splitSuffix(f("OrigLastName"))
+	e.FirstName = f("CorrectFirstName")
+	e.MiddleName = f("CorrectMiddleName")
+	e.LastName, e.Suffix = splitSuffix(f("CorrectLastName"))
+	e.AddressLine1 = f("LocationAddress")
+	e.AddressLine2 = f("DeliveryAddress")
+	e.City = f("City")
+	e.State = f("StateAbbrev")
+	e.ZIP = f("ZIPCode")
+	e.ZIPExtension = f("ZIPExtension")
+	e.ForeignStateProvince = f("ForeignStateProvince")
+	e.ForeignPostalCode = f("ForeignPostalCode")
+	e.CountryCode = f("CountryCode")
+
+	a := &e.Amounts
+	m := moneyReader{rec: rec, fields: ys.RCW}
+	m.pair("OrigWagesTipsOther", "CorrectWagesTipsOther", &a.OriginalWagesTipsOther, &a.CorrectWagesTipsOther)
+	m.pair("OrigFedIncomeTax", "CorrectFedIncomeTax", &a.OriginalFederalIncomeTax, &a.CorrectFederalIncomeTax)
+	m.pair("OrigSSWages", "CorrectSSWages", &a.OriginalSocialSecurityWages, &a.CorrectSocialSecurityWages)
+	m.pair("OrigSSTax", "CorrectSSTax", &a.OriginalSocialSecurityTax, &a.CorrectSocialSecurityTax)
+	m.pair("OrigMedicareWages", "CorrectMedicareWages", &a.OriginalMedicareWages, &a.CorrectMedicareWages)
+	m.pair("OrigMedicareTax", "CorrectMedicareTax", &a.OriginalMedicareTax, &a.CorrectMedicareTax)
+	m.pair("OrigSSTips", "CorrectSSTips", &a.OriginalSocialSecurityTips, &a.CorrectSocialSecurityTips)
+	m.pair("OrigDependentCare", "CorrectDependentCare", &a.OriginalDependentCare, &a.CorrectDependentCare)
+	m.pair("OrigCode401k", "CorrectCode401k", &a.OriginalCode401k, &a.CorrectCode401k)
+	m.pair("OrigCode403b", "CorrectCode403b", &a.OriginalCode403b, &a.CorrectCode403b)
+	m.pair("OrigCode457bGovt", "CorrectCode457bGovt", &a.OriginalCode457bGovt, &a.CorrectCode457bGovt)
+	m.pair("OrigCodeW_HSA", "CorrectCodeW_HSA", &a.OriginalCodeW_HSA, &a.CorrectCodeW_HSA)
+	m.pair("OrigCodeAA_Roth401k", "CorrectCodeAA_Roth401k", &a.OriginalCodeAA_Roth401k, &a.CorrectCodeAA_Roth401k)
+	m.pair("OrigCodeBB_Roth403b", "CorrectCodeBB_Roth403b", &a.OriginalCodeBB_Roth403b, &a.CorrectCodeBB_Roth403b)
+	m.pair("OrigCodeDD_EmpHealth", "CorrectCodeDD_EmpHealth", &a.OriginalCodeDD_EmpHealth, &a.CorrectCodeDD_EmpHealth)
+	m.pair("OrigCodeC", "CorrectCodeC", &a.OriginalCodeC_GroupTermLife, &a.CorrectCodeC_GroupTermLife)
+	m.pair("OrigCodeF", "CorrectCodeF", &a.OriginalCodeF_SARSEP, &a.CorrectCodeF_SARSEP)
+	m.pair("OrigCodeH", "CorrectCodeH", &a.OriginalCodeH_501c18D, &a.CorrectCodeH_501c18D)
+	m.pair("OrigCodeQ", "CorrectCodeQ", &a.OriginalCodeQ_CombatPay, &a.CorrectCodeQ_CombatPay)
+	m.pair("OrigCodeV", "CorrectCodeV", &a.OriginalCodeV_NSO, &a.CorrectCodeV_NSO)
+	m.pair("OrigCodeY", "CorrectCodeY", &a.OriginalCodeY_409A, &a.CorrectCodeY_409A)
+	m.pair("OrigCodeFF_QSEHRA", "CorrectCodeFF_QSEHRA", &a.OriginalCodeFF_QSEHRA, &a.CorrectCodeFF_QSEHRA)
+	m.pair("OrigNonqualPlan457", "CorrectNonqualPlan457", &a.OriginalNonqualPlan457, &a.CorrectNonqualPlan457)
+	m.pair("OrigNonqualNotSection457", "CorrectNonqualNotSection457", &a.OriginalNonqualNotSection457, &a.CorrectNonqualNotSection457)
+	if m.err != nil {
+		return e, m.err
+	}
+
+	b := &e.Box13
+	b.OrigStatutoryEmployee = box13Flag(f("OrigStatutoryEmployee"))
+	b.CorrectStatutoryEmployee = box13Flag(f("CorrectStatutoryEmployee"))
+	b.OrigRetirementPlan = box13Flag(f("OrigRetirementPlan"))
+	b.CorrectRetirementPlan = box13Flag(f("CorrectRetirementPlan"))
+	b.OrigThirdPartySickPay = box13Flag(f("OrigThirdPartySickPay"))
+	b.CorrectThirdPartySickPay = box13Flag(f("CorrectThirdPartySickPay"))
+	return e, nil
+}
+
+func parseRCO(rec string, ys *spec.YearSpec, e *domain.EmployeeRecord) error {
+	m := moneyReader{rec: rec, fields: ys.RCO}
+	m.pair("OrigAllocatedTips", "CorrectAllocatedTips", &e.Amounts.OriginalAllocatedTips, &e.Amounts.CorrectAllocatedTips)
+	m.pair("OrigUncollectedEETax", "CorrectUncollectedEETax", &e.Amounts.OriginalUncollectedEETax, &e.Amounts.CorrectUncollectedEETax)
+	m.pair("OrigCodeR_MSA", "CorrectCodeR_MSA", &e.Amounts.OriginalCodeR_MSA, &e.Amounts.CorrectCodeR_MSA)
+	m.pair("OrigCodeS_SIMPLE", "CorrectCodeS_SIMPLE", &e.Amounts.OriginalCodeS_SIMPLE, &e.Amounts.CorrectCodeS_SIMPLE)
+	m.pair("OrigCodeT_Adoption", "CorrectCodeT_Adoption", &e.Amounts.OriginalCodeT_Adoption, &e.Amounts.CorrectCodeT_Adoption)
+	m.pair("OrigCodeM_UncollSS", "CorrectCodeM_UncollSS", &e.Amounts.OriginalCodeM_UncollSS, &e.Amounts.CorrectCodeM_UncollSS)
+	m.pair("OrigCodeN_UncollMed", "CorrectCodeN_UncollMed", &e.Amounts.OriginalCodeN_UncollMed, &e.Amounts.CorrectCodeN_UncollMed)
+	m.pair("OrigCodeZ_409A", "CorrectCodeZ_409A", &e.Amounts.OriginalCodeZ_409A, &e.Amounts.CorrectCodeZ_409A)
+	if hasField(ys.RCO, "OrigMedicaidWaiver") {
+		m.pair("OrigMedicaidWaiver", "CorrectMedicaidWaiver", &e.Amounts.OriginalMedicaidWaiver, &e.Amounts.CorrectMedicaidWaiver)
+	}
+	return m.err
+}
+
+// parseRCS records the state on CorrectStateCode; Generate writes a single
+// code whichever side it came from.
+func parseRCS(rec string, ys *spec.YearSpec, e *domain.EmployeeRecord) error {
+	if postal, ok := spec.StatePostalCode(field(rec, ys.RCS, "StateCode")); ok {
+		e.CorrectStateCode = postal
+	}
+	a := &e.Amounts
+	m := moneyReader{rec: rec, fields: ys.RCS}
+	m.pair("OrigStateWages", "CorrectStateWages", &a.OriginalStateWages, &a.CorrectStateWages)
+	m.pair("OrigStateIncomeTax", "CorrectStateIncomeTax", &a.OriginalStateIncomeTax, &a.CorrectStateIncomeTax)
+	m.pair("OrigLocalWages", "CorrectLocalWages", &a.OriginalLocalWages, &a.CorrectLocalWages)
+	m.pair("OrigLocalIncomeTax", "CorrectLocalIncomeTax", &a.OriginalLocalIncomeTax, &a.CorrectLocalIncomeTax)
+	// Where WithRCSLocality put it; blank in files written without it.
+	e.CorrectLocalityName = field(rec, ys.RCS, "SupplementalData1")
+	return m.err
+}
+
+// field returns the named field's value in rec with padding trimmed.
+func field(rec string, fields []spec.Field, name string) string {
+	for _, f := range fields {
+		if f.Name == name {
+			return strings.TrimSpace(rec[f.Start-1 : f.End])
+		}
+	}
+	panic(fmt.Sprintf("efw2c: field %q not found in spec — parser bug", name))
+}
+
+// nameSuffixes are the suffixes splitSuffix recognises at the end of an
+// RCW last-name field.
+var nameSuffixes = map[string]bool{
+	"JR": true, "SR": true, "II": true, "III": true, "IV": true, "V": true,
+}
+
+// splitSuffix undoes withSuffix: a trailing word that is a known suffix is
+// returned separately. A last name such as "DE LA CRUZ" is left whole.
+func splitSuffix(last string) (name, suffix string) {
+	i := strings.LastIndexByte(last, ' ')
+	if i < 0 || !nameSuffixes[last[i+1:]] {
+		return last, ""
+	}
+	return strings.TrimSpace(last[:i]), last[i+1:]
+}
+
+// moneyReader decodes money fields, keeping the first error.
+type moneyReader struct {
+	rec    string
+	fields []spec.Field
+	err    error
+}
+
+// pair reads an original/correct pair of money fields in cents; blank
+// fields read as zero.
+func (m *moneyReader) pair(origName, corrName string, orig, corr *int64) {
+	*orig = m.money(origName)
+	*corr = m.money(corrName)
+}
+
+func (m *moneyReader) money(name string) int64 {
+	v := field(m.rec, m.fields, name)
+	if v == "" || m.err != nil {
+		return 0
+	}
+	cents, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || cents < 0 {
+		m.err = fmt.Errorf("%s: invalid money value %q", name, v)
+		return 0
+	}
+	return cents
+}
+
+// box13Flag reverses putBox13: blank is "no correction".
+func box13Flag(v string) *bool {
+	if v == "" {
+		return nil
+	}
+	b := v == "1"
+	return &b
+}
@@ -0,0 +1,105 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Change is how a field differs between two years' layouts.
+type Change int
+
+const (
+	Added Change = iota
+	Removed
+	Moved // same name, different positions
+)
+
+func (c Change) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Moved:
+		return "moved"
+	}
+	return fmt.Sprintf("Change(%d)", int(c))
+}
+
+// FieldDiff is one field that differs between two years' layouts. From is
+// the field in the earlier year and To in the later; the side a field is
+// missing from is the zero Field.
+type FieldDiff struct {
+	Record string
+	Name   string
+	Change Change
+	From   Field
+	To     Field
+}
+
+// Diff returns the fields added, removed or moved going from year a's
+// layout to year b's, record by record in RecordOrder, each record's
+// removed fields first and then the rest in b's order. Blank fillers are
+// left out: they only shrink or shift around real fields. Years without a
+// layout fall back to DefaultYear as in ForYear, so callers wanting an
+// error should check Supported first.
+func Diff(a, b int) []FieldDiff {
+	from, _ := ForYear(a)
+	to, _ := ForYear(b)
+	fromRecs, toRecs := from.Records(), to.Records()
+
+	var out []FieldDiff
+	for _, id := range RecordOrder {
+		old := make(map[string]Field)
+		for _, f := range fromRecs[id] {
+			if f.Type != Blank {
+				old[f.Name] = f
+			}
+		}
+		now := make(map[string]bool)
+		for _, f := range toRecs[id] {
+			now[f.Name] = true
+		}
+		for _, f := range fromRecs[id] {
+			if f.Type != Blank && !now[f.Name] {
+				out = append(out, FieldDiff{Record: id, Name: f.Name, Change: Removed, From: f})
+			}
+		}
+		for _, f := range toRecs[id] {
+			if f.Type == Blank {
+				continue
+			}
+			prev, ok := old[f.Name]
+			switch {
+			case !ok:
+				out = append(out, FieldDiff{Record: id, Name: f.Name, Change: Added, To: f})
+			case prev.Start != f.Start || prev.End != f.End:
+				out = append(out, FieldDiff{Record: id, Name: f.Name, Change: Moved, From: prev, To: f})
+			}
+		}
+	}
+	return out
+}
+
+// WriteDiff writes diffs between TY a and TY b as aligned text, one line
+// per field, in the style of WriteRuler.
+func WriteDiff(w io.Writer, a, b int, diffs []FieldDiff) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(tw, "# EFW2C layout changes TY%d -> TY%d (SSA Pub 42-014)\n", a, b)
+	if len(diffs) == 0 {
+		fmt.Fprintln(tw, "# no field changes")
+		return tw.Flush()
+	}
+	pos := func(f Field) string {
+		if f.Name == "" {
+			return "-"
+		}
+		return fmt.Sprintf("%d-%d", f.Start, f.End)
+	}
+	fmt.Fprintln(tw, "\nRECORD\tFIELD\tCHANGE\tFROM\tTO")
+	for _, d := range diffs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Record, d.Name, d.Change, pos(d.From), pos(d.To))
+	}
+	return tw.Flush()
+}
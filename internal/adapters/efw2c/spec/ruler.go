@@ -0,0 +1,52 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+func (t FieldType) String() string {
+	switch t {
+	case Alpha:
+		return "Alpha"
+	case Numeric:
+		return "Numeric"
+	case Money11:
+		return "Money11"
+	case Money15:
+		return "Money15"
+	case Fixed:
+		return "Fixed"
+	case Blank:
+		return "Blank"
+	}
+	return fmt.Sprintf("FieldType(%d)", int(t))
+}
+
+// RecordOrder lists the record identifiers in file order.
+var RecordOrder = []string{"RCA", "RCE", "RCW", "RCO", "RCS", "RCT", "RCU", "RCF"}
+
+// WriteRuler writes a printable data dictionary for ys: one aligned line per
+// field (name, start, end, length, type, required) grouped by record. It is
+// meant to be diffed by hand against the SSA publication.
+func (ys *YearSpec) WriteRuler(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(tw, "# EFW2C record layout TY%d (SSA Pub 42-014)\n", ys.TaxYear)
+	if ys.PublicationURL != "" {
+		fmt.Fprintf(tw, "# %s\n", ys.PublicationURL)
+	}
+	records := ys.Records()
+	for _, id := range RecordOrder {
+		fmt.Fprintf(tw, "\n%s\n", id)
+		fmt.Fprintln(tw, "FIELD\tSTART\tEND\tLEN\tTYPE\tREQUIRED")
+		for _, f := range records[id] {
+			req := ""
+			if f.Required {
+				req = "Y"
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\n", f.Name, f.Start, f.End, f.Len(), f.Type, req)
+		}
+	}
+	return tw.Flush()
+}
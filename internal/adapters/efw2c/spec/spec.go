@@ -4,6 +4,8 @@
 // only RCO/RCU had new fields added for TY2024 (Box 12 Code II).
 package spec
 
+import "fmt"
+
 const RecordLen = 1024
 
 type Field struct {
@@ -40,9 +42,63 @@ type YearSpec struct {
 	RCO            []Field // Employee Optional — Box 8, selected Box 12 codes
 	RCS            []Field // State Record — optional, SSA does not process
 	RCT            []Field
+	RCU            []Field // Total Optional — RCO totals, written only for a block with an RCO
 	RCF            []Field
 }
 
+// Records returns the layouts keyed by record identifier.
+func (ys *YearSpec) Records() map[string][]Field {
+	return map[string][]Field{
+		"RCA": ys.RCA,
+		"RCE": ys.RCE,
+		"RCW": ys.RCW,
+		"RCO": ys.RCO,
+		"RCS": ys.RCS,
+		"RCT": ys.RCT,
+		"RCU": ys.RCU,
+		"RCF": ys.RCF,
+	}
+}
+
+// Clone returns a deep copy of ys so callers can tweak field positions
+// without mutating the built-in layouts.
+func (ys *YearSpec) Clone() *YearSpec {
+	c := *ys
+	cp := func(f []Field) []Field { return append([]Field(nil), f...) }
+	c.RCA, c.RCE, c.RCW = cp(ys.RCA), cp(ys.RCE), cp(ys.RCW)
+	c.RCO, c.RCS, c.RCT, c.RCF = cp(ys.RCO), cp(ys.RCS), cp(ys.RCT), cp(ys.RCF)
+	c.RCU = cp(ys.RCU)
+	return &c
+}
+
+// CheckLayout verifies every record is defined and covers positions
+// 1..RecordLen with no gaps or overlaps.
+func (ys *YearSpec) CheckLayout() error {
+	records := ys.Records()
+	for _, id := range RecordOrder {
+		fields := records[id]
+		if len(fields) == 0 {
+			return fmt.Errorf("spec TY%d %s: no fields defined", ys.TaxYear, id)
+		}
+		prev := 0
+		for _, f := range fields {
+			if f.Start != prev+1 {
+				return fmt.Errorf("spec TY%d %s: field %q starts at %d, want %d (gap or overlap)",
+					ys.TaxYear, id, f.Name, f.Start, prev+1)
+			}
+			if f.End < f.Start {
+				return fmt.Errorf("spec TY%d %s: field %q ends (%d) before it starts (%d)",
+					ys.TaxYear, id, f.Name, f.End, f.Start)
+			}
+			prev = f.End
+		}
+		if prev != RecordLen {
+			return fmt.Errorf("spec TY%d %s: record ends at %d, want %d", ys.TaxYear, id, prev, RecordLen)
+		}
+	}
+	return nil
+}
+
 const DefaultYear = 2024
 
 func Supported() []int { return []int{2021, 2022, 2023, 2024} }
@@ -62,6 +118,28 @@ var specs = map[int]*YearSpec{
 	2024: ty2024(),
 }
 
+// A broken built-in layout would shift every record after the bad field, so
+// refuse to start with one.
+func init() {
+	if err := Validate(); err != nil {
+		panic(err)
+	}
+}
+
+// Validate runs CheckLayout on the built-in layout of every supported year.
+func Validate() error {
+	for _, year := range Supported() {
+		ys, ok := specs[year]
+		if !ok {
+			return fmt.Errorf("spec TY%d: supported but not defined", year)
+		}
+		if err := ys.CheckLayout(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ty2021() *YearSpec {
 	s := baseSpec(2021)
 	s.PublicationURL = "https://www.ssa.gov/employer/efw/21efw2c.pdf"
@@ -93,6 +171,14 @@ func ty2024() *YearSpec {
 		{Name: "CorrectMedicaidWaiver", Start: 288, End: 298, Type: Money11, Description: "Box 12 Code II corr"},
 		{Name: "Blank299", Start: 299, End: 1024, Type: Blank},
 	}...)
+	// RCU totals Code II at 371-400 the same way.
+	rcu := s.RCU
+	rcu = rcu[:len(rcu)-1] // drop Blank371 (371-1024)
+	s.RCU = append(rcu, []Field{
+		{Name: "OrigTotalMedicaidWaiver", Start: 371, End: 385, Type: Money15, Description: "Box 12 Code II orig total (TY2024+)"},
+		{Name: "CorrectTotalMedicaidWaiver", Start: 386, End: 400, Type: Money15, Description: "Box 12 Code II corr total"},
+		{Name: "Blank401", Start: 401, End: 1024, Type: Blank},
+	}...)
 	return s
 }
 
@@ -307,7 +393,7 @@ func baseSpec(year int) *YearSpec {
 
 		// ── RCS (State Record) ────────────────────────────────────────────
 		// Optional. SSA and IRS do NOT process this record; for state agencies only.
-		// SSA Pub 42-014 TY2024 §5.9. Key fields only — state taxable wages / income tax.
+		// SSA Pub 42-014 TY2024 §5.9. Key fields only — state and local taxable wages / income tax.
 		RCS: []Field{
 			{Name: "RecordIdentifier", Start: 1, End: 3, Type: Fixed, Required: true, Description: "Constant 'RCS'"},
 			{Name: "StateCode", Start: 4, End: 5, Type: Numeric, Required: true, Description: "State postal numeric code (Appendix H)"},
@@ -333,7 +419,16 @@ func baseSpec(year int) *YearSpec {
 			{Name: "CorrectStateWages", Start: 409, End: 419, Type: Money11, Required: false, Description: "Box 16 corr"},
 			{Name: "OrigStateIncomeTax", Start: 420, End: 430, Type: Money11, Required: false, Description: "Box 17 orig — state income tax withheld"},
 			{Name: "CorrectStateIncomeTax", Start: 431, End: 441, Type: Money11, Required: false, Description: "Box 17 corr"},
-			{Name: "Blank442", Start: 442, End: 1024, Type: Blank, Required: false},
+			{Name: "OtherStateData", Start: 442, End: 451, Type: Alpha, Required: false, Description: "State-defined"},
+			{Name: "TaxTypeCode", Start: 452, End: 452, Type: Alpha, Required: false, Description: "C=City D=County E=School district F=Other; blank if no local tax"},
+			{Name: "OrigLocalWages", Start: 453, End: 463, Type: Money11, Required: false, Description: "Box 18 orig — local taxable wages"},
+			{Name: "CorrectLocalWages", Start: 464, End: 474, Type: Money11, Required: false, Description: "Box 18 corr"},
+			{Name: "OrigLocalIncomeTax", Start: 475, End: 485, Type: Money11, Required: false, Description: "Box 19 orig — local income tax withheld"},
+			{Name: "CorrectLocalIncomeTax", Start: 486, End: 496, Type: Money11, Required: false, Description: "Box 19 corr"},
+			{Name: "StateControlNumber", Start: 497, End: 503, Type: Alpha, Required: false},
+			{Name: "SupplementalData1", Start: 504, End: 578, Type: Alpha, Required: false, Description: "State-defined; holds the Box 20 locality name only under WithRCSLocality"},
+			{Name: "SupplementalData2", Start: 579, End: 653, Type: Alpha, Required: false, Description: "State-defined"},
+			{Name: "Blank654", Start: 654, End: 1024, Type: Blank, Required: false},
 		},
 
 		// ── RCT (Total) ──────────────────────────────────────────────────
@@ -398,6 +493,40 @@ func baseSpec(year int) *YearSpec {
 			{Name: "Blank851", Start: 851, End: 1024, Type: Blank, Required: false},
 		},
 
+		// ── RCU (Total Optional) ─────────────────────────────────────────
+		// Totals the RCO money fields for the preceding RCE; follows the RCT
+		// and is only present when at least one RCO was written. 15-char money
+		// fields. SSA Pub 42-014 TY2024 §5.11.
+		// TY2021-2023 end with blank 371-1024. TY2024 adds Code II; ty2024() appends it.
+		RCU: []Field{
+			{Name: "RecordIdentifier", Start: 1, End: 3, Type: Fixed, Required: true, Description: "Constant 'RCU'"},
+			{Name: "TotalRCORecords", Start: 4, End: 10, Type: Numeric, Required: true, Description: "Total RCO count, 7 digits zero-padded"},
+			{Name: "OrigTotalAllocatedTips", Start: 11, End: 25, Type: Money15, Required: false, Description: "Box 8 orig total"},
+			{Name: "CorrectTotalAllocatedTips", Start: 26, End: 40, Type: Money15, Required: false, Description: "Box 8 corr total"},
+			{Name: "OrigTotalUncollectedEETax", Start: 41, End: 55, Type: Money15, Required: false, Description: "Box 12 Codes A&B orig total"},
+			{Name: "CorrectTotalUncollectedEETax", Start: 56, End: 70, Type: Money15, Required: false, Description: "Box 12 Codes A&B corr total"},
+			{Name: "OrigTotalCodeR_MSA", Start: 71, End: 85, Type: Money15, Required: false, Description: "Box 12 Code R orig total"},
+			{Name: "CorrectTotalCodeR_MSA", Start: 86, End: 100, Type: Money15, Required: false, Description: "Box 12 Code R corr total"},
+			{Name: "OrigTotalCodeS_SIMPLE", Start: 101, End: 115, Type: Money15, Required: false, Description: "Box 12 Code S orig total"},
+			{Name: "CorrectTotalCodeS_SIMPLE", Start: 116, End: 130, Type: Money15, Required: false, Description: "Box 12 Code S corr total"},
+			{Name: "OrigTotalCodeT_Adoption", Start: 131, End: 145, Type: Money15, Required: false, Description: "Box 12 Code T orig total"},
+			{Name: "CorrectTotalCodeT_Adoption", Start: 146, End: 160, Type: Money15, Required: false, Description: "Box 12 Code T corr total"},
+			{Name: "OrigTotalCodeM_UncollSS", Start: 161, End: 175, Type: Money15, Required: false, Description: "Box 12 Code M orig total"},
+			{Name: "CorrectTotalCodeM_UncollSS", Start: 176, End: 190, Type: Money15, Required: false, Description: "Box 12 Code M corr total"},
+			{Name: "OrigTotalCodeN_UncollMed", Start: 191, End: 205, Type: Money15, Required: false, Description: "Box 12 Code N orig total"},
+			{Name: "CorrectTotalCodeN_UncollMed", Start: 206, End: 220, Type: Money15, Required: false, Description: "Box 12 Code N corr total"},
+			{Name: "OrigTotalCodeZ_409A", Start: 221, End: 235, Type: Money15, Required: false, Description: "Box 12 Code Z orig total"},
+			{Name: "CorrectTotalCodeZ_409A", Start: 236, End: 250, Type: Money15, Required: false, Description: "Box 12 Code Z corr total"},
+			{Name: "Blank251", Start: 251, End: 280, Type: Blank, Required: false},
+			{Name: "OrigTotalCodeEE_Roth457b", Start: 281, End: 295, Type: Money15, Required: false, Description: "Box 12 Code EE orig total"},
+			{Name: "CorrectTotalCodeEE_Roth457b", Start: 296, End: 310, Type: Money15, Required: false, Description: "Box 12 Code EE corr total"},
+			{Name: "OrigTotalCodeGG_83i", Start: 311, End: 325, Type: Money15, Required: false, Description: "Box 12 Code GG orig total"},
+			{Name: "CorrectTotalCodeGG_83i", Start: 326, End: 340, Type: Money15, Required: false, Description: "Box 12 Code GG corr total"},
+			{Name: "OrigTotalCodeHH_83iDeferral", Start: 341, End: 355, Type: Money15, Required: false, Description: "Box 12 Code HH orig total"},
+			{Name: "CorrectTotalCodeHH_83iDeferral", Start: 356, End: 370, Type: Money15, Required: false, Description: "Box 12 Code HH corr total"},
+			{Name: "Blank371", Start: 371, End: 1024, Type: Blank, Required: false},
+		},
+
 		// ── RCF (Final) ──────────────────────────────────────────────────
 		RCF: []Field{
 			{Name: "RecordIdentifier", Start: 1, End: 3, Type: Fixed, Required: true},
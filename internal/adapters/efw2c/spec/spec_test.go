@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidate verifies the built-in layouts pass and a corrupted one,
+// swapped in for TY2022, is reported.
+func TestValidate(t *testing.T) {
+	if err := Validate(); err != nil {
+		t.Fatalf("built-in layouts: %v", err)
+	}
+
+	orig := specs[2022]
+	t.Cleanup(func() { specs[2022] = orig })
+	bad := orig.Clone()
+	bad.RCT[1].Start-- // overlaps RecordIdentifier
+	specs[2022] = bad
+
+	err := Validate()
+	if err == nil {
+		t.Fatal("corrupted TY2022 RCT: want error, got nil")
+	}
+	if !strings.Contains(err.Error(), "TY2022 RCT") {
+		t.Errorf("error should name the year and record, got %q", err)
+	}
+}
+
+// TestDiff verifies 2023 -> 2024 reports exactly the Code II pairs added
+// to RCO and RCU, and that a year diffed against itself is empty.
+func TestDiff(t *testing.T) {
+	got := Diff(2023, 2024)
+	want := []FieldDiff{
+		{Record: "RCO", Name: "OrigMedicaidWaiver", Change: Added, To: Field{Start: 277, End: 287}},
+		{Record: "RCO", Name: "CorrectMedicaidWaiver", Change: Added, To: Field{Start: 288, End: 298}},
+		{Record: "RCU", Name: "OrigTotalMedicaidWaiver", Change: Added, To: Field{Start: 371, End: 385}},
+		{Record: "RCU", Name: "CorrectTotalMedicaidWaiver", Change: Added, To: Field{Start: 386, End: 400}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Diff(2023, 2024): want %d changes, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Record != w.Record || g.Name != w.Name || g.Change != w.Change ||
+			g.To.Start != w.To.Start || g.To.End != w.To.End || g.From.Name != "" {
+			t.Errorf("change %d: want %s %s %s at %d-%d, got %+v",
+				i, w.Record, w.Name, w.Change, w.To.Start, w.To.End, g)
+		}
+	}
+
+	if d := Diff(2024, 2023); len(d) != 4 || d[0].Change != Removed {
+		t.Errorf("Diff(2024, 2023): want the Code II fields removed, got %+v", d)
+	}
+	if d := Diff(2022, 2022); len(d) != 0 {
+		t.Errorf("Diff(2022, 2022): want no changes, got %+v", d)
+	}
+}
+
+// TestIsValidCountryCode checks SSA codes, including ones ISO spells
+// differently, and rejects an unknown one.
+func TestIsValidCountryCode(t *testing.T) {
+	for code, want := range map[string]bool{
+		"CA": true, "GB": true, "UK": true, " gm ": true,
+		"ZZ": false, "": false, "USA": false,
+	} {
+		if got := IsValidCountryCode(code); got != want {
+			t.Errorf("IsValidCountryCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+// TestStateNumericCode maps postal abbreviations to Appendix H codes and
+// back, and reports an unknown abbreviation.
+func TestStateNumericCode(t *testing.T) {
+	if code, ok := StateNumericCode(" il "); !ok || code != "13" {
+		t.Errorf("StateNumericCode(IL) = %q, %v; want 13, true", code, ok)
+	}
+	if code, ok := StateNumericCode("XX"); ok {
+		t.Errorf("StateNumericCode(XX) = %q, true; want not ok", code)
+	}
+	if abbr, ok := StatePostalCode("13"); !ok || abbr != "IL" {
+		t.Errorf("StatePostalCode(13) = %q, %v; want IL, true", abbr, ok)
+	}
+}
+
+// TestIsValidStateAbbrev accepts states, territories and military codes.
+func TestIsValidStateAbbrev(t *testing.T) {
+	for abbr, want := range map[string]bool{
+		"IL": true, "DC": true, "PR": true, "GU": true, "ae": true, "AA": true, "AP": true,
+		"ZZ": false, "": false, "ILL": false,
+	} {
+		if got := IsValidStateAbbrev(abbr); got != want {
+			t.Errorf("IsValidStateAbbrev(%q) = %v, want %v", abbr, got, want)
+		}
+	}
+}
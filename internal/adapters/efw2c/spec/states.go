@@ -0,0 +1,66 @@
+package spec
+
+import "strings"
+
+// stateNumericCodes maps postal abbreviations to SSA 2-digit numeric state
+// codes (Appendix H), as written in the RCS StateCode fields.
+var stateNumericCodes = map[string]string{
+	"AL": "01", "AK": "02", "AZ": "03", "AR": "04", "CA": "05",
+	"CO": "06", "CT": "07", "DE": "08", "FL": "09", "GA": "10",
+	"HI": "11", "ID": "12", "IL": "13", "IN": "14", "IA": "15",
+	"KS": "16", "KY": "17", "LA": "18", "ME": "19", "MD": "20",
+	"MA": "21", "MI": "22", "MN": "23", "MS": "24", "MO": "25",
+	"MT": "26", "NE": "27", "NV": "28", "NH": "29", "NJ": "30",
+	"NM": "31", "NY": "32", "NC": "33", "ND": "34", "OH": "35",
+	"OK": "36", "OR": "37", "PA": "38", "RI": "39", "SC": "40",
+	"SD": "41", "TN": "42", "TX": "43", "UT": "44", "VT": "45",
+	"VA": "46", "WA": "47", "WV": "48", "WI": "49", "WY": "50",
+	"DC": "51", "PR": "72", "VI": "78", "GU": "66", "AS": "60",
+	"MP": "69",
+}
+
+// StateNumericCode returns the SSA 2-digit numeric code (Appendix H) for a
+// postal state abbreviation, ignoring case and surrounding spaces. ok is
+// false for an abbreviation SSA has no state code for.
+func StateNumericCode(abbr string) (code string, ok bool) {
+	code, ok = stateNumericCodes[strings.ToUpper(strings.TrimSpace(abbr))]
+	return code, ok
+}
+
+// StatePostalCode is the inverse of StateNumericCode.
+func StatePostalCode(numeric string) (abbr string, ok bool) {
+	for postal, num := range stateNumericCodes {
+		if num == numeric {
+			return postal, true
+		}
+	}
+	return "", false
+}
+
+// stateAbbrevs are the USPS abbreviations SSA accepts in the StateAbbrev
+// fields of the RCA, RCE and RCW (Appendix G): the states and DC, the
+// territories and freely associated states, and the military APO/FPO
+// "states" AA, AE and AP.
+var stateAbbrevs = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true,
+	"CO": true, "CT": true, "DE": true, "FL": true, "GA": true,
+	"HI": true, "ID": true, "IL": true, "IN": true, "IA": true,
+	"KS": true, "KY": true, "LA": true, "ME": true, "MD": true,
+	"MA": true, "MI": true, "MN": true, "MS": true, "MO": true,
+	"MT": true, "NE": true, "NV": true, "NH": true, "NJ": true,
+	"NM": true, "NY": true, "NC": true, "ND": true, "OH": true,
+	"OK": true, "OR": true, "PA": true, "RI": true, "SC": true,
+	"SD": true, "TN": true, "TX": true, "UT": true, "VT": true,
+	"VA": true, "WA": true, "WV": true, "WI": true, "WY": true,
+	"DC": true,
+	"AS": true, "FM": true, "GU": true, "MH": true, "MP": true,
+	"PR": true, "PW": true, "VI": true,
+	"AA": true, "AE": true, "AP": true,
+}
+
+// IsValidStateAbbrev reports whether abbr, ignoring case and surrounding
+// spaces, is a state, territory or military postal abbreviation SSA
+// accepts in an address.
+func IsValidStateAbbrev(abbr string) bool {
+	return stateAbbrevs[strings.ToUpper(strings.TrimSpace(abbr))]
+}
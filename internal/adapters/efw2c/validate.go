@@ -0,0 +1,848 @@
+package efw2c
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Validation result types
+// ---------------------------------------------------------------------------
+
+// Machine-readable validation codes. The HTML layer keys off these to
+// highlight the offending input.
+const (
+	CodeOneSidedCorrection = "one_sided_correction"
+	CodeSSTaxRate          = "ss_tax_rate"
+	CodeSSWageBase         = "ss_wage_base"
+	CodeSSWageCap          = "ss_wage_cap"
+	CodeMedicareTaxRate    = "medicare_tax_rate"
+	CodeTaxExceedsWages    = "tax_exceeds_wages"
+	CodeFieldOverflow      = "field_overflow"
+	CodeFieldTruncated     = "field_truncated"
+	CodeNegativeAmount     = "negative_amount"
+	CodeIncompleteAddress  = "incomplete_address"
+	CodeInvalidEIN         = "invalid_ein"
+	CodeInvalidSSN         = "invalid_ssn"
+	CodeInvalidBSOUID      = "invalid_bsouid"
+	CodeInvalidEmployment  = "invalid_employment_code"
+	CodeInvalidKind        = "invalid_kind_of_employer"
+	CodeInvalidZIP         = "invalid_zip"
+	CodeInvalidEmail       = "invalid_email"
+	CodeInvalidSoftware    = "invalid_software_code"
+	CodeNoCorrection       = "no_correction"
+	CodeIncompleteName     = "incomplete_name_correction"
+	CodeInvalidCountry     = "invalid_country_code"
+	CodeInvalidState       = "invalid_state_code"
+	CodeAmountNotInYear    = "amount_not_in_tax_year"
+)
+
+// ValidationError is a blocking problem; see domain.ValidationError.
+type ValidationError = domain.ValidationError
+
+// ValidationErrors collects every blocking problem found in a submission.
+type ValidationErrors = domain.ValidationErrors
+
+// Warning is a non-fatal advisory; see domain.Warning.
+type Warning = domain.Warning
+
+// GenerateResult describes a written file; see domain.GenerateResult.
+type GenerateResult = domain.GenerateResult
+
+// ---------------------------------------------------------------------------
+// Money pairing
+// ---------------------------------------------------------------------------
+
+// moneyPair is one original/correct amount pair with its W-2c box label.
+type moneyPair struct {
+	field string // MonetaryAmounts field name without the Original/Correct prefix
+	box   string
+	orig  int64
+	corr  int64
+	// critical pairs (Boxes 2, 4, 6) are errors rather than warnings when
+	// one-sided under strict pairing.
+	critical bool
+}
+
+func moneyPairs(a *domain.MonetaryAmounts) []moneyPair {
+	return []moneyPair{
+		{"WagesTipsOther", "Box 1", a.OriginalWagesTipsOther, a.CorrectWagesTipsOther, false},
+		{"FederalIncomeTax", "Box 2", a.OriginalFederalIncomeTax, a.CorrectFederalIncomeTax, true},
+		{"SocialSecurityWages", "Box 3", a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages, false},
+		{"SocialSecurityTax", "Box 4", a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax, true},
+		{"MedicareWages", "Box 5", a.OriginalMedicareWages, a.CorrectMedicareWages, false},
+		{"MedicareTax", "Box 6", a.OriginalMedicareTax, a.CorrectMedicareTax, true},
+		{"SocialSecurityTips", "Box 7", a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips, false},
+		{"AllocatedTips", "Box 8", a.OriginalAllocatedTips, a.CorrectAllocatedTips, false},
+		{"UncollectedEETax", "Box 12 A/B", a.OriginalUncollectedEETax, a.CorrectUncollectedEETax, false},
+		{"CodeR_MSA", "Box 12 R", a.OriginalCodeR_MSA, a.CorrectCodeR_MSA, false},
+		{"CodeS_SIMPLE", "Box 12 S", a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE, false},
+		{"CodeT_Adoption", "Box 12 T", a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption, false},
+		{"CodeM_UncollSS", "Box 12 M", a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS, false},
+		{"CodeN_UncollMed", "Box 12 N", a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed, false},
+		{"CodeZ_409A", "Box 12 Z", a.OriginalCodeZ_409A, a.CorrectCodeZ_409A, false},
+		{"MedicaidWaiver", "Box 12 II", a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver, false},
+		{"DependentCare", "Box 10", a.OriginalDependentCare, a.CorrectDependentCare, false},
+		{"NonqualPlan457", "Box 11", a.OriginalNonqualPlan457, a.CorrectNonqualPlan457, false},
+		{"NonqualNotSection457", "Box 11", a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457, false},
+		{"Code401k", "Box 12 D", a.OriginalCode401k, a.CorrectCode401k, false},
+		{"Code403b", "Box 12 E", a.OriginalCode403b, a.CorrectCode403b, false},
+		{"Code457bGovt", "Box 12 G", a.OriginalCode457bGovt, a.CorrectCode457bGovt, false},
+		{"CodeW_HSA", "Box 12 W", a.OriginalCodeW_HSA, a.CorrectCodeW_HSA, false},
+		{"CodeAA_Roth401k", "Box 12 AA", a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k, false},
+		{"CodeBB_Roth403b", "Box 12 BB", a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b, false},
+		{"CodeDD_EmpHealth", "Box 12 DD", a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth, false},
+		{"CodeC_GroupTermLife", "Box 12 C", a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife, false},
+		{"CodeF_SARSEP", "Box 12 F", a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP, false},
+		{"CodeH_501c18D", "Box 12 H", a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D, false},
+		{"CodeQ_CombatPay", "Box 12 Q", a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay, false},
+		{"CodeV_NSO", "Box 12 V", a.OriginalCodeV_NSO, a.CorrectCodeV_NSO, false},
+		{"CodeY_409A", "Box 12 Y", a.OriginalCodeY_409A, a.CorrectCodeY_409A, false},
+		{"CodeFF_QSEHRA", "Box 12 FF", a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA, false},
+		{"StateWages", "Box 16", a.OriginalStateWages, a.CorrectStateWages, false},
+		{"StateIncomeTax", "Box 17", a.OriginalStateIncomeTax, a.CorrectStateIncomeTax, false},
+		{"LocalWages", "Box 18", a.OriginalLocalWages, a.CorrectLocalWages, false},
+		{"LocalIncomeTax", "Box 19", a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax, false},
+	}
+}
+
+// CheckPairing flags one-sided corrections: a corrected amount with no
+// original amount. Normally every one-sided pair is a warning; with
+// WithStrictPairing the core tax boxes (2, 4, 6) become errors, since SSA
+// almost always rejects them, while the remaining boxes stay warnings.
+// Employees are indexed within their employer block.
+func (g *Generator) CheckPairing(s *domain.Submission) (ValidationErrors, []Warning) {
+	var (
+		errs  ValidationErrors
+		warns []Warning
+	)
+	for _, b := range s.EmployerBlocks() {
+		for i := range b.Employees {
+			for _, p := range moneyPairs(&b.Employees[i].Amounts) {
+				if p.orig != 0 || p.corr == 0 {
+					continue
+				}
+				msg := fmt.Sprintf("%s has a corrected amount but no original amount", p.box)
+				if g.strictPairing && p.critical {
+					errs = append(errs, ValidationError{Code: CodeOneSidedCorrection, Field: p.field, Employee: i, Message: msg})
+					continue
+				}
+				warns = append(warns, Warning{Code: CodeOneSidedCorrection, Field: p.field, Employee: i, Message: msg})
+			}
+		}
+	}
+	return errs, warns
+}
+
+// ---------------------------------------------------------------------------
+// Identifiers
+// ---------------------------------------------------------------------------
+
+// invalidSSNs are advertising/sample numbers that were never validly
+// issued: 078-05-1120 (the 1938 wallet insert) and 219-09-9999 (an SSA
+// pamphlet).
+var invalidSSNs = map[string]bool{
+	"078051120": true,
+	"219099999": true,
+}
+
+// Validate reports every identifier and code problem in s that would get
+// the file rejected, before one is produced: the BSO User ID (see
+// WithLowercaseBSOUID), submitter email and software code, every EIN and
+// SSN (optional ones only when set), the employment code and kind of
+// employer, domestic ZIP codes and states, foreign country codes, the RCS
+// state of employees with state amounts, name corrections, Code II
+// amounts before TY2024, and employees that correct nothing. Every
+// employer block is checked, its employees indexed within the block as
+// Generate reports them. The *Problem helpers below hold each rule. Under
+// TrimReject (the default) every text value too long for its field is
+// also reported. Satisfies ports.EFW2CGenerator.
+func (g *Generator) Validate(s *domain.Submission) ValidationErrors {
+	local := g.forSubmission(s)
+	var errs ValidationErrors
+	if msg := bsouidProblem(s.Submitter.BSOUID, g.lowercaseBSOUID); msg != "" {
+		errs = append(errs, ValidationError{Code: CodeInvalidBSOUID, Field: "BSOUID", Employee: -1, Message: msg})
+	}
+	if msg := emailProblem(s.Submitter.ContactEmail); msg != "" {
+		errs = append(errs, ValidationError{Code: CodeInvalidEmail, Field: "ContactEmail", Employee: -1, Message: msg})
+	}
+	if field, msg := softwareProblem(s.Submitter.SoftwareCode, s.Submitter.SoftwareVendorCode); msg != "" {
+		errs = append(errs, ValidationError{Code: CodeInvalidSoftware, Field: field, Employee: -1, Message: msg})
+	}
+	zips := func(employee int, prefix, zip, ext string) {
+		if msg := zipProblem(zip, 5); msg != "" {
+			errs = append(errs, ValidationError{Code: CodeInvalidZIP, Field: prefix + "ZIP", Employee: employee, Message: "ZIP code " + msg})
+		}
+		if msg := zipProblem(ext, 4); msg != "" {
+			errs = append(errs, ValidationError{Code: CodeInvalidZIP, Field: prefix + "ZIPExtension", Employee: employee, Message: "ZIP+4 extension " + msg})
+		}
+	}
+	states := func(employee int, field, state string) {
+		if state != "" && !spec.IsValidStateAbbrev(state) {
+			errs = append(errs, ValidationError{Code: CodeInvalidState, Field: field, Employee: employee,
+				Message: fmt.Sprintf("State %q is not a USPS state, territory or military (AA/AE/AP) code", state)})
+		}
+	}
+	country := func(employee int, code string) {
+		if msg := countryProblem(code); msg != "" {
+			errs = append(errs, ValidationError{Code: CodeInvalidCountry, Field: "CountryCode", Employee: employee, Message: msg})
+		}
+	}
+	zips(-1, "Submitter", s.Submitter.ZIP, s.Submitter.ZIPExtension)
+	states(-1, "SubmitterState", s.Submitter.State)
+
+	for _, b := range s.EmployerBlocks() {
+		emp := &b.Employer
+		eins := []struct {
+			field, value string
+			required     bool
+		}{
+			{"EIN", emp.EIN, true},
+			{"OriginalEIN", emp.OriginalEIN, false},
+			{"AgentEIN", emp.AgentEIN, false},
+		}
+		for _, ein := range eins {
+			if ein.value == "" && !ein.required {
+				continue
+			}
+			if msg := einProblem(ein.value); msg != "" {
+				errs = append(errs, ValidationError{Code: CodeInvalidEIN, Field: ein.field, Employee: -1, Message: msg})
+			}
+		}
+		if msg := codeProblem("Employment code", employmentCodes, emp.EmploymentCode); msg != "" {
+			errs = append(errs, ValidationError{Code: CodeInvalidEmployment, Field: "EmploymentCode", Employee: -1, Message: msg})
+		}
+		if msg := codeProblem("Kind of employer", kindsOfEmployer, emp.KindOfEmployer); msg != "" {
+			errs = append(errs, ValidationError{Code: CodeInvalidKind, Field: "KindOfEmployer", Employee: -1, Message: msg})
+		}
+		if emp.HasForeignAddress() {
+			country(-1, emp.CountryCode)
+		} else {
+			zips(-1, "", emp.ZIP, emp.ZIPExtension)
+			states(-1, "State", emp.State)
+		}
+		for i := range b.Employees {
+			e := &b.Employees[i]
+			if e.HasForeignAddress() {
+				country(i, e.CountryCode)
+			} else {
+				zips(i, "", e.ZIP, e.ZIPExtension)
+				states(i, "State", e.State)
+			}
+			if msg := ssnProblem(e.SSN); msg != "" {
+				errs = append(errs, ValidationError{Code: CodeInvalidSSN, Field: "SSN", Employee: i, Message: msg})
+			}
+			if e.HasRCSData() {
+				field, code := rcsState(e, emp.State)
+				// An employer state already reported above needs no second error.
+				reported := field == "State" && code != "" && !emp.HasForeignAddress() && !spec.IsValidStateAbbrev(code)
+				if _, ok := spec.StateNumericCode(code); !ok && !reported {
+					errs = append(errs, ValidationError{Code: CodeInvalidState, Field: field, Employee: i,
+						Message: rcsStateMessage(code)})
+				}
+			}
+			if field, msg := nameCorrectionProblem(e); msg != "" {
+				errs = append(errs, ValidationError{Code: CodeIncompleteName, Field: field, Employee: i, Message: msg})
+			}
+			if msg := medicaidWaiverProblem(local.yspec, &e.Amounts); msg != "" {
+				errs = append(errs, ValidationError{Code: CodeAmountNotInYear, Field: "MedicaidWaiver", Employee: i, Message: msg})
+			}
+			if !e.HasCorrection() {
+				errs = append(errs, ValidationError{Code: CodeNoCorrection, Field: "Employee", Employee: i,
+					Message: "Every original value equals its correct value; SSA rejects an RCW with no correction"})
+			}
+			if e.OriginalSSN != "" {
+				if msg := ssnProblem(e.OriginalSSN); msg != "" {
+					errs = append(errs, ValidationError{Code: CodeInvalidSSN, Field: "OriginalSSN", Employee: i, Message: msg})
+				}
+			}
+		}
+	}
+	if g.trimPolicy == TrimReject {
+		// A field already rejected above (a 9-char BSOUID or a ZIP+4 in
+		// the ZIP, say) needs no second, overflow error.
+		seen := make(map[string]bool, len(errs))
+		for _, e := range errs {
+			seen[fmt.Sprint(e.Employee, e.Field)] = true
+			// Mark the record field names too.
+			switch e.Field {
+			case "EmploymentCode":
+				seen[fmt.Sprint(e.Employee, "CorrectEmploymentCode")] = true
+			case "ZIP", "SubmitterZIP":
+				seen[fmt.Sprint(e.Employee, "ZIPCode")] = true
+			case "SubmitterZIPExtension":
+				seen[fmt.Sprint(e.Employee, "ZIPExtension")] = true
+			}
+		}
+		for _, e := range g.truncations(s) {
+			if !seen[fmt.Sprint(e.Employee, e.Field)] {
+				errs = append(errs, e)
+			}
+		}
+	}
+	return errs
+}
+
+// medicaidWaiverProblem reports Box 12 Code II amounts in a year whose
+// RCO has no Code II positions (before TY2024), or returns "". Generate
+// would drop them, and HasCorrection and Summary would still count them.
+func medicaidWaiverProblem(ys *spec.YearSpec, a *domain.MonetaryAmounts) string {
+	if a.OriginalMedicaidWaiver == 0 && a.CorrectMedicaidWaiver == 0 || hasField(ys.RCO, "OrigMedicaidWaiver") {
+		return ""
+	}
+	return fmt.Sprintf("Box 12 Code II (Medicaid waiver) is not in the TY%d layout; SSA added it for TY2024", ys.TaxYear)
+}
+
+// employmentCodes and kindsOfEmployer are the single-letter RCE codes SSA
+// accepts for CorrectEmploymentCode and KindOfEmployer.
+var (
+	employmentCodes = []string{"A", "H", "M", "Q", "R", "X", "F"}
+	kindsOfEmployer = []string{"F", "S", "T", "Y", "N"}
+)
+
+// codeProblem describes why c is not one of allowed, or returns "". Blank
+// is allowed: buildRCE writes the defaults R and N.
+func codeProblem(label string, allowed []string, c string) string {
+	if c == "" || slices.Contains(allowed, c) {
+		return ""
+	}
+	return fmt.Sprintf("%s must be one of %s, got %q", label, strings.Join(allowed, "/"), c)
+}
+
+// softwareProblem describes why the RCA software fields are inconsistent,
+// naming the field at fault, or returns "". The code is blank, 98 or 99;
+// 99 (off-the-shelf) needs the 4-digit NACTP vendor code.
+func softwareProblem(code, vendor string) (field, msg string) {
+	switch code {
+	case "", "98":
+		return "", ""
+	case "99":
+		if len(vendor) != 4 || strings.Trim(vendor, "0123456789") != "" {
+			return "SoftwareVendorCode", fmt.Sprintf("Software code 99 (off-the-shelf) needs a 4-digit NACTP vendor code, got %q", vendor)
+		}
+		return "", ""
+	}
+	return "SoftwareCode", fmt.Sprintf("Software code must be 98 (in-house) or 99 (off-the-shelf), got %q", code)
+}
+
+// nameCorrectionProblem describes why an employee's name correction is
+// incomplete, naming the field at fault, or returns "". Any original name
+// field marks a name correction, and SSA rejects an RCW whose original or
+// correct last name is blank.
+func nameCorrectionProblem(e *domain.EmployeeRecord) (field, msg string) {
+	if e.OriginalFirstName == "" && e.OriginalMiddleName == "" && e.OriginalLastName == "" && e.OriginalSuffix == "" {
+		return "", ""
+	}
+	if strings.TrimSpace(e.OriginalLastName) == "" {
+		return "OriginalLastName", "A name correction needs the original last name, even when only the first or middle name changed"
+	}
+	if strings.TrimSpace(e.LastName) == "" {
+		return "LastName", "A name correction needs the correct last name"
+	}
+	return "", ""
+}
+
+// zipProblem describes why a populated ZIP code or extension is not exactly
+// n digits, or returns "". Blank is allowed; CheckAddress warns about
+// incomplete employee addresses.
+func zipProblem(zip string, n int) string {
+	if zip == "" {
+		return ""
+	}
+	if len(zip) != n || strings.Trim(zip, "0123456789") != "" {
+		return fmt.Sprintf("must be exactly %d digits, got %q", n, zip)
+	}
+	return ""
+}
+
+// rcsStateMessage explains why the RCS state code for code would be blank.
+func rcsStateMessage(code string) string {
+	if strings.TrimSpace(code) == "" {
+		return "State amounts need a state code, on the employee or the employer, for the RCS record"
+	}
+	return fmt.Sprintf("State code %q has no SSA numeric code; the RCS record would be rejected", code)
+}
+
+// countryProblem describes why a foreign address's country code is not an
+// SSA Appendix I code, or returns "". ISO codes SSA does not use, such as
+// DE for Germany, are the usual culprits.
+func countryProblem(code string) string {
+	if spec.IsValidCountryCode(code) {
+		return ""
+	}
+	return fmt.Sprintf("Country code %q is not an SSA Appendix I code (the United Kingdom is UK, Germany GM)", code)
+}
+
+// maxEmailLen is the width of the RCA ContactEmail field.
+const maxEmailLen = 40
+
+// emailProblem describes what is wrong with the submitter contact email,
+// or returns "". BSO rejects a file whose RCA email is blank or malformed,
+// and padEmail would silently cut a longer one.
+func emailProblem(email string) string {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "Submitter contact email is required"
+	}
+	if len(email) > maxEmailLen {
+		return fmt.Sprintf("Submitter contact email must be at most %d characters, got %d", maxEmailLen, len(email))
+	}
+	local, host, ok := strings.Cut(email, "@")
+	if !ok || local == "" || strings.Contains(host, "@") || strings.ContainsAny(email, " \t") ||
+		!strings.Contains(host, ".") || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+		return fmt.Sprintf("Submitter contact email must look like name@example.com, got %q", email)
+	}
+	return ""
+}
+
+// bsouidProblem describes what is wrong with id, or returns "" if it is a
+// well-formed BSO User ID: eight upper-case letters or digits, as SSA
+// assigns it. With lower, lower-case letters are accepted too; padAlpha
+// upper-cases them when the RCA is written.
+func bsouidProblem(id string, lower bool) string {
+	if id == "" {
+		return "BSO User ID is required"
+	}
+	if len(id) != 8 {
+		return fmt.Sprintf("BSO User ID must be exactly 8 characters, got %d (%q)", len(id), id)
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'z' && lower:
+		case r >= 'a' && r <= 'z':
+			return fmt.Sprintf("BSO User ID must be upper case as SSA issued it, got %q", id)
+		default:
+			return fmt.Sprintf("BSO User ID must contain only letters and digits, got %q", id)
+		}
+	}
+	return ""
+}
+
+// einProblem describes what is wrong with ein, or returns "" if it is
+// valid: nine digits, not all zeros, and not starting with 00.
+func einProblem(ein string) string {
+	d, ok := nineDigits(ein)
+	switch {
+	case !ok:
+		return fmt.Sprintf("EIN must be exactly 9 digits, got %q", ein)
+	case d == "000000000":
+		return "EIN cannot be all zeros"
+	case d[:2] == "00":
+		return fmt.Sprintf("EIN prefix %s is not assigned by the IRS", d[:2])
+	}
+	return ""
+}
+
+// ssnProblem describes what is wrong with ssn, or returns "" if it is
+// valid: nine digits with a valid area (not 000, 666 or 9xx), group (not
+// 00) and serial (not 0000), and not a known-invalid number.
+func ssnProblem(ssn string) string {
+	d, ok := nineDigits(ssn)
+	switch {
+	case !ok:
+		return fmt.Sprintf("SSN must be exactly 9 digits, got %q", ssn)
+	case d == "000000000":
+		return "SSN cannot be all zeros"
+	case d[:3] == "000" || d[:3] == "666" || d[0] == '9':
+		return fmt.Sprintf("SSN area number %s is never issued", d[:3])
+	case d[3:5] == "00":
+		return "SSN group number cannot be 00"
+	case d[5:] == "0000":
+		return "SSN serial number cannot be 0000"
+	case invalidSSNs[d]:
+		return fmt.Sprintf("SSN %s-%s-%s is a known invalid (advertising/sample) number", d[:3], d[3:5], d[5:])
+	}
+	return ""
+}
+
+// nineDigits strips dashes and spaces from v and reports whether exactly
+// nine digits remain and nothing else.
+func nineDigits(v string) (string, bool) {
+	d := strings.NewReplacer("-", "", " ", "").Replace(v)
+	if len(d) != 9 {
+		return d, false
+	}
+	for _, r := range d {
+		if r < '0' || r > '9' {
+			return d, false
+		}
+	}
+	return d, true
+}
+
+// ---------------------------------------------------------------------------
+// Field widths
+// ---------------------------------------------------------------------------
+
+// maxMoney11 is the largest amount, in cents, an 11-digit money field holds.
+const maxMoney11 = 99_999_999_999
+
+// CheckWidths reports every value too wide for its fixed-width field:
+// amounts over 11 digits and EINs/SSNs over 9 digits. Left alone, these
+// would be silently truncated when the record is written. Every employer
+// block is checked, its employees indexed within the block.
+func CheckWidths(s *domain.Submission) ValidationErrors {
+	var errs ValidationErrors
+	for _, b := range s.EmployerBlocks() {
+		errs = append(errs, checkBlockWidths(&b)...)
+	}
+	return errs
+}
+
+// checkBlockWidths is CheckWidths for one employer block.
+func checkBlockWidths(b *domain.EmployerBlock) ValidationErrors {
+	var errs ValidationErrors
+	ids := []struct{ field, value string }{
+		{"EIN", b.Employer.EIN},
+		{"OriginalEIN", b.Employer.OriginalEIN},
+		{"AgentEIN", b.Employer.AgentEIN},
+	}
+	for _, id := range ids {
+		if e, ok := checkDigits(id.field, id.value, 9); ok {
+			e.Employee = -1
+			errs = append(errs, e)
+		}
+	}
+	for i := range b.Employees {
+		emp := &b.Employees[i]
+		for _, id := range []struct{ field, value string }{{"SSN", emp.SSN}, {"OriginalSSN", emp.OriginalSSN}} {
+			if e, ok := checkDigits(id.field, id.value, 9); ok {
+				e.Employee = i
+				errs = append(errs, e)
+			}
+		}
+		for _, p := range moneyPairs(&emp.Amounts) {
+			for _, side := range []struct {
+				prefix, label string
+				cents         int64
+			}{{"Original", "original", p.orig}, {"Correct", "correct", p.corr}} {
+				if side.cents <= maxMoney11 {
+					continue
+				}
+				limit, got := "$"+dollars(maxMoney11), "$"+dollars(side.cents)
+				errs = append(errs, ValidationError{
+					Code:     CodeFieldOverflow,
+					Field:    side.prefix + p.field,
+					Employee: i,
+					Message:  fmt.Sprintf("%s %s max %s, got %s", p.box, side.label, limit, got),
+					Limit:    limit,
+					Got:      got,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// CheckNegative reports every negative amount. EFW2C money fields are
+// unsigned magnitudes — a reduction is expressed by a smaller corrected
+// amount, not a negative one — so a negative stored amount is a data-entry
+// error and blocks generation rather than being written as zero. Employees
+// are indexed within their employer block.
+func CheckNegative(s *domain.Submission) ValidationErrors {
+	var errs ValidationErrors
+	for _, b := range s.EmployerBlocks() {
+		for i := range b.Employees {
+			for _, p := range moneyPairs(&b.Employees[i].Amounts) {
+				for _, side := range []struct {
+					prefix, label string
+					cents         int64
+				}{{"Original", "original", p.orig}, {"Correct", "correct", p.corr}} {
+					if side.cents >= 0 {
+						continue
+					}
+					errs = append(errs, ValidationError{
+						Code:     CodeNegativeAmount,
+						Field:    side.prefix + p.field,
+						Employee: i,
+						Message:  fmt.Sprintf("%s %s is negative (-$%s); EFW2C amounts are unsigned, so enter the amount itself, not a change", p.box, side.label, dollars(-side.cents)),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// checkDigits reports whether value has more than n digits, returning the
+// overflow error (Employee unset) if so.
+func checkDigits(field, value string, n int) (ValidationError, bool) {
+	digits := 0
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits <= n {
+		return ValidationError{}, false
+	}
+	limit, got := fmt.Sprintf("%d digits", n), fmt.Sprintf("%d digits (%s)", digits, value)
+	return ValidationError{
+		Code:    CodeFieldOverflow,
+		Field:   field,
+		Message: fmt.Sprintf("%s max %s, got %s", field, limit, got),
+		Limit:   limit,
+		Got:     got,
+	}, true
+}
+
+// ---------------------------------------------------------------------------
+// Reconciliation checks
+// ---------------------------------------------------------------------------
+
+// Check returns every non-fatal reconciliation warning for s, each tagged
+// with its employee index within its employer block. Satisfies
+// ports.EFW2CGenerator.
+func (g *Generator) Check(s *domain.Submission) []Warning {
+	local := g.forSubmission(s)
+	_, warns := local.CheckPairing(s)
+	for _, b := range s.EmployerBlocks() {
+		for i := range b.Employees {
+			e := &b.Employees[i]
+			ss := CheckSocialSecurity(local.yspec, e.Amounts)
+			for _, w := range ss {
+				w.Employee = i
+				warns = append(warns, w)
+			}
+			// Box 3 alone over the base is already an ss_wage_base warning.
+			if !slices.ContainsFunc(ss, func(w Warning) bool { return w.Code == CodeSSWageBase }) {
+				for _, w := range CheckSSWageCap(local.yspec, e.Amounts) {
+					w.Employee = i
+					warns = append(warns, w)
+				}
+			}
+			for _, w := range CheckMedicare(e.Amounts) {
+				w.Employee = i
+				warns = append(warns, w)
+			}
+			for _, w := range CheckTaxExceedsWages(e.Amounts) {
+				w.Employee = i
+				warns = append(warns, w)
+			}
+			for _, w := range CheckAddress(e) {
+				w.Employee = i
+				warns = append(warns, w)
+			}
+		}
+	}
+	if g.trimPolicy == TrimTruncate {
+		for _, t := range g.truncations(s) {
+			warns = append(warns, truncationWarning(t))
+		}
+	}
+	return warns
+}
+
+// truncationWarning restates a truncated value's overflow error as the
+// field_truncated warning TrimTruncate reports instead.
+func truncationWarning(t ValidationError) Warning {
+	return Warning{Code: CodeFieldTruncated, Field: t.Field, Employee: t.Employee, Message: t.Message}
+}
+
+// truncations builds s's RCA and the records of every employer block and
+// returns every text value that had to be cut to fit its field. A spec too broken to build
+// with yields nothing here; Generate reports it.
+func (g *Generator) truncations(s *domain.Submission) (errs ValidationErrors) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*boundsError); !ok {
+				panic(r)
+			}
+			errs = nil
+		}
+	}()
+	local := g.forSubmission(s)
+	local.truncs = &truncLog{employee: -1}
+	local.build(context.Background(), blockSubmissions(s), func(string) error { return nil })
+	return local.truncs.items
+}
+
+// ssTaxRatePermille is the employee Social Security tax rate (6.2%).
+const ssTaxRatePermille = 62
+
+// ssTaxToleranceCents absorbs per-paycheck rounding in Box 4.
+const ssTaxToleranceCents = 100
+
+// CheckSocialSecurity warns when the corrected Box 3 exceeds the year's
+// SS wage base, and when the corrected Box 4 differs from 6.2% of the
+// corrected Box 3 (capped at the wage base) by more than a rounding
+// tolerance. Employee is left at 0; callers set it.
+func CheckSocialSecurity(ys *spec.YearSpec, a domain.MonetaryAmounts) []Warning {
+	if a.CorrectSocialSecurityWages == 0 && a.CorrectSocialSecurityTax == 0 {
+		return nil
+	}
+	var warns []Warning
+	wages := a.CorrectSocialSecurityWages
+	if ys.SSWageBase > 0 && wages > ys.SSWageBase {
+		warns = append(warns, Warning{
+			Code:  CodeSSWageBase,
+			Field: "SocialSecurityWages",
+			Message: fmt.Sprintf("Box 3 SS wages $%s exceed the TY%d wage base of $%s",
+				dollars(wages), ys.TaxYear, dollars(ys.SSWageBase)),
+		})
+		wages = ys.SSWageBase
+	}
+	expected := (wages*ssTaxRatePermille + 500) / 1000
+	delta := a.CorrectSocialSecurityTax - expected
+	if abs(delta) > ssTaxToleranceCents {
+		warns = append(warns, Warning{
+			Code:  CodeSSTaxRate,
+			Field: "SocialSecurityTax",
+			Message: fmt.Sprintf("Box 4 SS tax $%s is not 6.2%% of Box 3 SS wages (expected about $%s)",
+				dollars(a.CorrectSocialSecurityTax), dollars(expected)),
+			Expected: expected,
+			Delta:    delta,
+		})
+	}
+	return warns
+}
+
+// CheckSSWageCap warns when the corrected Box 3 SS wages plus Box 7 SS
+// tips exceed the year's SS wage base, which SSA's edits reject. Expected
+// is the wage base and Delta the overage, in cents. Employee is left at 0;
+// callers set it.
+func CheckSSWageCap(ys *spec.YearSpec, a domain.MonetaryAmounts) []Warning {
+	total := a.CorrectSocialSecurityWages + a.CorrectSocialSecurityTips
+	if ys.SSWageBase <= 0 || total <= ys.SSWageBase {
+		return nil
+	}
+	over := total - ys.SSWageBase
+	return []Warning{{
+		Code:  CodeSSWageCap,
+		Field: "SocialSecurityWages",
+		Message: fmt.Sprintf("Box 3 SS wages plus Box 7 SS tips ($%s) exceed the TY%d wage base of $%s by $%s",
+			dollars(total), ys.TaxYear, dollars(ys.SSWageBase), dollars(over)),
+		Expected: ys.SSWageBase,
+		Delta:    over,
+	}}
+}
+
+// Medicare withholding: 1.45% of all Box 5 wages plus 0.9% Additional
+// Medicare Tax on wages above $200,000, in basis points so the arithmetic
+// stays in integer cents.
+const (
+	medicareRateBP              = 145
+	additionalMedicareRateBP    = 90
+	additionalMedicareThreshold = 20000000 // cents
+)
+
+// medicareToleranceCents absorbs per-paycheck rounding in Box 6.
+const medicareToleranceCents = 100
+
+// CheckMedicare warns when the corrected Box 6 differs from the expected
+// withholding on the corrected Box 5 by more than a rounding tolerance.
+// The expected figure includes Additional Medicare Tax above the $200,000
+// threshold. Expected and Delta are set on the warning; Employee is left
+// at 0 and callers set it.
+func CheckMedicare(a domain.MonetaryAmounts) []Warning {
+	if a.CorrectMedicareWages == 0 && a.CorrectMedicareTax == 0 {
+		return nil
+	}
+	expected := bp(a.CorrectMedicareWages, medicareRateBP)
+	if over := a.CorrectMedicareWages - additionalMedicareThreshold; over > 0 {
+		expected += bp(over, additionalMedicareRateBP)
+	}
+	delta := a.CorrectMedicareTax - expected
+	if abs(delta) <= medicareToleranceCents {
+		return nil
+	}
+	return []Warning{{
+		Code:  CodeMedicareTaxRate,
+		Field: "MedicareTax",
+		Message: fmt.Sprintf("Box 6 Medicare tax: expected $%s, got $%s (1.45%% of Box 5 plus 0.9%% over $200,000)",
+			dollars(expected), dollars(a.CorrectMedicareTax)),
+		Expected: expected,
+		Delta:    delta,
+	}}
+}
+
+// bp returns cents × basisPoints / 10000, rounded half up.
+func bp(cents, basisPoints int64) int64 {
+	return (cents*basisPoints + 5000) / 10000
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// CheckTaxExceedsWages warns when the corrected Box 4 SS tax exceeds the
+// corrected Box 3 SS wages, or the corrected Box 6 Medicare tax exceeds the
+// corrected Box 5 Medicare wages — almost always a swapped entry. It is a
+// warning rather than an error so unusual adjustments can still be filed.
+// Employee is left at 0; callers set it.
+func CheckTaxExceedsWages(a domain.MonetaryAmounts) []Warning {
+	var warns []Warning
+	if a.CorrectSocialSecurityTax > a.CorrectSocialSecurityWages {
+		warns = append(warns, Warning{
+			Code:  CodeTaxExceedsWages,
+			Field: "SocialSecurityTax",
+			Message: fmt.Sprintf("Box 4 SS tax $%s exceeds Box 3 SS wages $%s; check for swapped entries",
+				dollars(a.CorrectSocialSecurityTax), dollars(a.CorrectSocialSecurityWages)),
+		})
+	}
+	if a.CorrectMedicareTax > a.CorrectMedicareWages {
+		warns = append(warns, Warning{
+			Code:  CodeTaxExceedsWages,
+			Field: "MedicareTax",
+			Message: fmt.Sprintf("Box 6 Medicare tax $%s exceeds Box 5 Medicare wages $%s; check for swapped entries",
+				dollars(a.CorrectMedicareTax), dollars(a.CorrectMedicareWages)),
+		})
+	}
+	return warns
+}
+
+// CheckAddress warns when an employee address is only partly filled in:
+// once any address field is set, city, state and ZIP must all be present
+// (city and postal code for a foreign address). SSA does not require
+// employee addresses, so this is never an error. Employee is left at 0;
+// callers set it.
+func CheckAddress(e *domain.EmployeeRecord) []Warning {
+	if e.AddressLine1 == "" && e.AddressLine2 == "" && e.City == "" &&
+		e.State == "" && e.ZIP == "" && e.ZIPExtension == "" && !e.HasForeignAddress() {
+		return nil
+	}
+	var missing []string
+	if e.City == "" {
+		missing = append(missing, "city")
+	}
+	if e.HasForeignAddress() {
+		if e.ForeignPostalCode == "" {
+			missing = append(missing, "postal code")
+		}
+	} else if e.State == "" {
+		missing = append(missing, "state")
+	}
+	if !e.HasForeignAddress() && e.ZIP == "" {
+		missing = append(missing, "ZIP")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []Warning{{
+		Code:    CodeIncompleteAddress,
+		Field:   "Address",
+		Message: "Employee address is incomplete: missing " + strings.Join(missing, ", "),
+	}}
+}
+
+// dollars formats cents as "1,234.56" for messages.
+func dollars(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	whole := fmt.Sprintf("%d", cents/100)
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, whole, cents%100)
+}
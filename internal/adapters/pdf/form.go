@@ -0,0 +1,367 @@
+package pdf
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// GenerateW2cFormPDF writes Copy B of Form W-2c, one page per employee, to w:
+// employer and employee boxes a–i on the left, Boxes 1–14 as "Previously
+// reported" and "Correct information" columns on the right, and the Box
+// 15–20 state and local rows underneath, following the IRS layout so the
+// page can be furnished to the employee as a substitute statement. As the
+// IRS instructions ask, only the amounts being corrected are filled in. It
+// stops and returns ctx.Err() if ctx is done before every page is drawn;
+// nothing is written to w in that case.
+func GenerateW2cFormPDF(ctx context.Context, s *domain.Submission, w io.Writer, opts ...Option) error {
+	pdf, ssn := newDocument(opts)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(false, 10)
+	for i := range s.Employees {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pdf.AddPage()
+		drawW2cForm(pdf, s, &s.Employees[i], ssn)
+	}
+	return pdf.Output(w)
+}
+
+// box12Entry is one Box 12 code and its two amounts.
+type box12Entry struct {
+	code       string
+	orig, corr int64
+}
+
+// box12Corrections lists e's Box 12 codes whose amount changes, in code
+// order as the form lists them.
+func box12Corrections(a *domain.MonetaryAmounts) []box12Entry {
+	all := []box12Entry{
+		{"A/B", a.OriginalUncollectedEETax, a.CorrectUncollectedEETax},
+		{"C", a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife},
+		{"D", a.OriginalCode401k, a.CorrectCode401k},
+		{"E", a.OriginalCode403b, a.CorrectCode403b},
+		{"F", a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP},
+		{"G", a.OriginalCode457bGovt, a.CorrectCode457bGovt},
+		{"H", a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D},
+		{"M", a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS},
+		{"N", a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed},
+		{"Q", a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay},
+		{"R", a.OriginalCodeR_MSA, a.CorrectCodeR_MSA},
+		{"S", a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE},
+		{"T", a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption},
+		{"V", a.OriginalCodeV_NSO, a.CorrectCodeV_NSO},
+		{"W", a.OriginalCodeW_HSA, a.CorrectCodeW_HSA},
+		{"Y", a.OriginalCodeY_409A, a.CorrectCodeY_409A},
+		{"Z", a.OriginalCodeZ_409A, a.CorrectCodeZ_409A},
+		{"AA", a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k},
+		{"BB", a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b},
+		{"DD", a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth},
+		{"FF", a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA},
+		{"II", a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver},
+	}
+	var out []box12Entry
+	for _, b := range all {
+		if b.orig != b.corr {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func drawW2cForm(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRecord, ssn func(string) string) {
+	pageW, _ := pdf.GetPageSize()
+	marginL, marginT, marginR, _ := pdf.GetMargins()
+	contentW := pageW - marginL - marginR
+	a := &e.Amounts
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+
+	// ── Title ───────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.SetXY(marginL, marginT)
+	pdf.CellFormat(30, 7, "Form W-2c", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(70, 7, "Corrected Wage and Tax Statement", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.CellFormat(contentW-100, 7, "Copy B - To Be Filed With Employee's FEDERAL Tax Return", "", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 6.5)
+	pdf.SetX(marginL)
+	pdf.CellFormat(contentW, 4, "This information is being furnished to the Internal Revenue Service.", "", 1, "R", false, 0, "")
+
+	// ── Boxes a–i ───────────────────────────────────────────────────────────
+	top := marginT + 13
+	leftW := 78.0
+	rightX := marginL + leftW
+	colW := (contentW - leftW) / 2
+	boxW := colW / 2
+
+	employerLines := []string{s.Employer.Name}
+	for _, l := range []string{s.Employer.AddressLine1, s.Employer.AddressLine2,
+		strings.TrimPrefix(cityLine(s.Employer.City, s.Employer.State, s.Employer.ZIP), ", ")} {
+		if l != "" {
+			employerLines = append(employerLines, l)
+		}
+	}
+	employeeCorrected := e.OriginalSSN != "" || e.OriginalFirstName != "" || e.OriginalLastName != ""
+	prevName := joinNonEmpty(e.OriginalFirstName, e.OriginalMiddleName, e.OriginalLastName, e.OriginalSuffix)
+	prevSSN := ""
+	if e.OriginalSSN != "" {
+		prevSSN = ssn(e.OriginalSSN)
+	}
+	var employeeAddr []string
+	for _, l := range []string{e.AddressLine1, e.AddressLine2,
+		strings.TrimPrefix(cityLine(e.City, e.State, e.ZIP), ", ")} {
+		if l != "" {
+			employeeAddr = append(employeeAddr, l)
+		}
+	}
+
+	y := top
+	for _, b := range []struct {
+		h     float64
+		label string
+		lines []string
+	}{
+		{24, "a  Employer's name, address, and ZIP code", employerLines},
+		{9, "b  Employer's Federal EIN", []string{formatEIN(s.Employer.EIN)}},
+		{9, "c  Tax year/Form corrected", []string{s.Employer.TaxYear + " / W-2"}},
+		{9, "d  Employee's correct SSN", []string{ssn(e.SSN)}},
+		{9, "e  Corrected SSN and/or name (see boxes f and g)", []string{checkMark(employeeCorrected)}},
+		{9, "f  Employee's previously reported SSN", []string{prevSSN}},
+		{11, "g  Employee's previously reported name", []string{prevName}},
+		{11, "h  Employee's first name and initial, last name, suffix", []string{joinNonEmpty(e.FirstName, e.MiddleName, e.LastName, e.Suffix)}},
+		{13, "i  Employee's address and ZIP code", employeeAddr},
+	} {
+		formBox(pdf, marginL, y, leftW, b.h, b.label, b.lines...)
+		y += b.h
+	}
+	gridBottom := y
+
+	// ── Boxes 1–14: previously reported | correct information ──────────────
+	pdf.SetFillColor(230, 230, 230)
+	pdf.SetFont("Helvetica", "B", 7)
+	pdf.SetXY(rightX, top)
+	pdf.CellFormat(colW, 5, "Previously reported", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colW, 5, "Correct information", "1", 0, "C", true, 0, "")
+
+	box12 := box12Corrections(a)
+	code := func(i int) formField {
+		f := formField{label: "12" + string(rune('a'+i)) + "  See instructions for box 12"}
+		if i < len(box12) {
+			b := box12[i]
+			f.orig, f.corr = b.code+"  "+centsToDisplay(b.orig), b.code+"  "+centsToDisplay(b.corr)
+		}
+		return f
+	}
+	money := func(label string, orig, corr int64) formField {
+		f := formField{label: label}
+		if orig != corr {
+			f.orig, f.corr = centsToDisplay(orig), centsToDisplay(corr)
+		}
+		return f
+	}
+	rows := []struct{ left, right formField }{
+		{money("1  Wages, tips, other compensation", a.OriginalWagesTipsOther, a.CorrectWagesTipsOther),
+			money("2  Federal income tax withheld", a.OriginalFederalIncomeTax, a.CorrectFederalIncomeTax)},
+		{money("3  Social security wages", a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages),
+			money("4  Social security tax withheld", a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax)},
+		{money("5  Medicare wages and tips", a.OriginalMedicareWages, a.CorrectMedicareWages),
+			money("6  Medicare tax withheld", a.OriginalMedicareTax, a.CorrectMedicareTax)},
+		{money("7  Social security tips", a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips),
+			money("8  Allocated tips", a.OriginalAllocatedTips, a.CorrectAllocatedTips)},
+		{formField{label: "9", shaded: true},
+			money("10  Dependent care benefits", a.OriginalDependentCare, a.CorrectDependentCare)},
+		{money("11  Nonqualified plans", a.OriginalNonqualPlan457+a.OriginalNonqualNotSection457,
+			a.CorrectNonqualPlan457+a.CorrectNonqualNotSection457), code(0)},
+		{formField{label: "13", box13: true}, code(1)},
+		{formField{label: "14  Other (see instructions)"}, code(2)},
+		{formField{label: ""}, code(3)},
+	}
+	rowH := (gridBottom - top - 5) / float64(len(rows))
+	y = top + 5
+	for _, r := range rows {
+		for side := 0; side < 2; side++ {
+			x := rightX + float64(side)*colW
+			for j, f := range []formField{r.left, r.right} {
+				bx := x + float64(j)*boxW
+				switch {
+				case f.shaded:
+					pdf.SetFillColor(210, 210, 210)
+					pdf.Rect(bx, y, boxW, rowH, "FD")
+					formBox(pdf, bx, y, boxW, rowH, f.label)
+				case f.box13:
+					drawBox13(pdf, bx, y, boxW, rowH, &e.Box13, side == 1)
+				default:
+					value := f.orig
+					if side == 1 {
+						value = f.corr
+					}
+					formBox(pdf, bx, y, boxW, rowH, f.label, value)
+				}
+			}
+		}
+		y += rowH
+	}
+
+	// More than four codes do not fit boxes 12a–d; list the rest beneath.
+	if len(box12) > 4 {
+		var extra []string
+		for _, b := range box12[4:] {
+			extra = append(extra, b.code+" "+centsToDisplay(b.orig)+" -> "+centsToDisplay(b.corr))
+		}
+		pdf.SetFont("Helvetica", "", 6.5)
+		pdf.SetXY(rightX, y+0.5)
+		pdf.CellFormat(contentW-leftW, 3.5, "Box 12 continued (previously reported -> correct): "+strings.Join(extra, "; "), "", 0, "L", false, 0, "")
+	}
+
+	// ── Boxes 15–20 ─────────────────────────────────────────────────────────
+	y = gridBottom + 5
+	tagW := 18.0
+	cols := []struct {
+		w          float64
+		label      string
+		orig, corr string
+	}{
+		{12, "15  State", e.OriginalStateCode, e.CorrectStateCode},
+		{36, "Employer's state ID number", e.OriginalStateIDNumber, e.CorrectStateIDNumber},
+		{28, "16  State wages, tips, etc.", "", ""},
+		{26, "17  State income tax", "", ""},
+		{28, "18  Local wages, tips, etc.", "", ""},
+		{26, "19  Local income tax", "", ""},
+		{0, "20  Locality name", e.OriginalLocalityName, e.CorrectLocalityName},
+	}
+	for i, m := range []struct{ orig, corr int64 }{
+		{a.OriginalStateWages, a.CorrectStateWages},
+		{a.OriginalStateIncomeTax, a.CorrectStateIncomeTax},
+		{a.OriginalLocalWages, a.CorrectLocalWages},
+		{a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax},
+	} {
+		if m.orig != m.corr {
+			cols[2+i].orig, cols[2+i].corr = centsToDisplay(m.orig), centsToDisplay(m.corr)
+		}
+	}
+	used := tagW
+	for _, c := range cols {
+		used += c.w
+	}
+	cols[len(cols)-1].w = contentW - used
+	stateH := 11.0
+	for side, tag := range []string{"Previously reported", "Correct information"} {
+		rowY := y + float64(side)*stateH
+		pdf.SetFillColor(230, 230, 230)
+		pdf.Rect(marginL, rowY, tagW, stateH, "FD")
+		pdf.SetFont("Helvetica", "B", 6.5)
+		pdf.SetXY(marginL+0.8, rowY+1)
+		pdf.MultiCell(tagW-1.6, 3.2, tag, "", "L", false)
+		x := marginL + tagW
+		for _, c := range cols {
+			value := c.orig
+			if side == 1 {
+				value = c.corr
+			}
+			formBox(pdf, x, rowY, c.w, stateH, c.label, value)
+			x += c.w
+		}
+	}
+
+	// ── Footer ──────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetXY(marginL, y+2*stateH+1)
+	pdf.CellFormat(contentW/3, 5, "Form W-2c", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.CellFormat(contentW/3, 5, "Substitute statement - W-2C Generator", "", 0, "C", false, 0, "")
+	pdf.CellFormat(contentW/3, 5, "Department of the Treasury - Internal Revenue Service", "", 0, "R", false, 0, "")
+}
+
+// formField is one box on the W-2c grid: its caption and the text shown in
+// the previously reported and correct columns.
+type formField struct {
+	label      string
+	orig, corr string
+	shaded     bool // box 9, not used on Form W-2c
+	box13      bool // the three box 13 checkboxes
+}
+
+// formBox draws a ruled box with a small caption at its top and lines of
+// typewriter text along its bottom.
+func formBox(pdf *fpdf.Fpdf, x, y, w, h float64, label string, lines ...string) {
+	pdf.Rect(x, y, w, h, "D")
+	pdf.SetFont("Helvetica", "", 5.5)
+	pdf.SetXY(x+0.8, y+0.6)
+	pdf.CellFormat(w-1.6, 2.5, fitText(pdf, label, w-1.6), "", 0, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 8.5)
+	const lineH = 3.6
+	ly := y + h - 0.8 - lineH*float64(len(lines))
+	for _, l := range lines {
+		pdf.SetXY(x+1.2, ly)
+		pdf.CellFormat(w-2.4, lineH, fitText(pdf, l, w-2.4), "", 0, "L", false, 0, "")
+		ly += lineH
+	}
+}
+
+// drawBox13 draws box 13's statutory employee, retirement plan and
+// third-party sick pay checkboxes for one column. Nothing is marked unless
+// the flag is being corrected.
+func drawBox13(pdf *fpdf.Fpdf, x, y, w, h float64, f *domain.Box13Flags, correct bool) {
+	formBox(pdf, x, y, w, h, "13")
+	flags := []struct {
+		caption    string
+		orig, corr *bool
+	}{
+		{"Statutory employee", f.OrigStatutoryEmployee, f.CorrectStatutoryEmployee},
+		{"Retirement plan", f.OrigRetirementPlan, f.CorrectRetirementPlan},
+		{"Third-party sick pay", f.OrigThirdPartySickPay, f.CorrectThirdPartySickPay},
+	}
+	cellW := (w - 1.6) / float64(len(flags))
+	for i, fl := range flags {
+		cx := x + 0.8 + float64(i)*cellW
+		pdf.SetFont("Helvetica", "", 4.5)
+		pdf.SetXY(cx, y+3)
+		pdf.MultiCell(cellW, 2, fl.caption, "", "C", false)
+		sq := 2.8
+		sx, sy := cx+(cellW-sq)/2, y+h-sq-1
+		pdf.Rect(sx, sy, sq, sq, "D")
+		v := fl.corr
+		if !correct {
+			v = fl.orig
+		}
+		if fl.orig != nil && v != nil && *v {
+			pdf.SetFont("Helvetica", "B", 7)
+			pdf.SetXY(sx, sy)
+			pdf.CellFormat(sq, sq, "X", "", 0, "C", false, 0, "")
+		}
+	}
+}
+
+// checkMark renders a form checkbox's state.
+func checkMark(checked bool) string {
+	if checked {
+		return "[X]"
+	}
+	return "[ ]"
+}
+
+// joinNonEmpty joins the non-blank parts with single spaces.
+func joinNonEmpty(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// fitText trims s until it fits w at the current font.
+func fitText(pdf *fpdf.Fpdf, s string, w float64) string {
+	for s != "" && pdf.GetStringWidth(s) > w {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -1,10 +1,12 @@
 // Package pdf generates a human-readable W-2C correction PDF report.
-// One page is produced per employee; each page shows the employer header,
+// The report opens with a submission summary page, followed by one page per
+// employee; each employee page shows the employer header,
 // employee identity information, and a table comparing original vs. corrected
 // amounts for every W-2C box.
 package pdf
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -14,22 +16,190 @@ import (
 	"github.com/csg33k/w2c-generator/internal/domain"
 )
 
-// GeneratePDF writes a multi-page PDF (one page per employee) to w.
-func GeneratePDF(s *domain.Submission, w io.Writer) error {
+// Option configures GeneratePDF and GenerateEmployeePDF.
+type Option func(*options)
+
+type options struct {
+	userPassword  string
+	ownerPassword string
+	maskSSN       bool
+}
+
+// WithPassword encrypts the PDF with the standard PDF security handler.
+// user is required to open the document; owner grants full access and
+// defaults to user when empty. Printing stays allowed for anyone holding
+// the user password. Because the report carries full SSNs and wages, this
+// protects the file at rest (on disk, in email, in backups); it does
+// nothing for the download itself, which relies on the transport. An empty
+// user password leaves the PDF unencrypted.
+func WithPassword(user, owner string) Option {
+	return func(o *options) {
+		o.userPassword = user
+		o.ownerPassword = owner
+		if o.ownerPassword == "" {
+			o.ownerPassword = user
+		}
+	}
+}
+
+// WithMaskedSSN renders every SSN as XXX-XX-1234 for distribution copies
+// handed to employees. The default internal copy shows full SSNs.
+func WithMaskedSSN() Option {
+	return func(o *options) { o.maskSSN = true }
+}
+
+// GeneratePDF writes a multi-page PDF (summary page plus one page per
+// employee) to w. It stops and returns ctx.Err() if ctx is done before
+// every page is drawn; nothing is written to w in that case.
+func GeneratePDF(ctx context.Context, s *domain.Submission, w io.Writer, opts ...Option) error {
+	pdf, ssn := newDocument(opts)
+
+	pdf.AddPage()
+	drawSummaryPage(pdf, s)
+
+	for i := range s.Employees {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pdf.AddPage()
+		drawEmployeePage(pdf, s, &s.Employees[i], ssn)
+	}
+
+	return pdf.Output(w)
+}
+
+// GenerateEmployeePDF writes a one-page PDF of e's corrections, without the
+// summary page, for handing a single corrected W-2c to that employee. s is
+// e's submission and supplies the employer header.
+func GenerateEmployeePDF(s *domain.Submission, e *domain.EmployeeRecord, w io.Writer, opts ...Option) error {
+	pdf, ssn := newDocument(opts)
+	pdf.AddPage()
+	drawEmployeePage(pdf, s, e, ssn)
+	return pdf.Output(w)
+}
+
+// newDocument returns an empty Letter document configured by opts, and the
+// SSN formatter its pages should use.
+func newDocument(opts []Option) (*fpdf.Fpdf, func(string) string) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	pdf := fpdf.New("P", "mm", "Letter", "")
+	if o.userPassword != "" {
+		pdf.SetProtection(fpdf.CnProtectPrint, o.userPassword, o.ownerPassword)
+	}
 	pdf.SetMargins(18, 18, 18)
 	pdf.SetAutoPageBreak(true, 18)
 	pdf.AliasNbPages("{nb}")
 
-	for i := range s.Employees {
-		pdf.AddPage()
-		drawEmployeePage(pdf, s, &s.Employees[i])
+	if o.maskSSN {
+		return pdf, maskSSN
 	}
+	return pdf, formatSSN
+}
 
-	return pdf.Output(w)
+// drawSummaryPage renders the submission-level roll-up from
+// domain.Submission.Summary: employee and record counts plus net box deltas.
+func drawSummaryPage(pdf *fpdf.Fpdf, s *domain.Submission) {
+	pageW, _ := pdf.GetPageSize()
+	marginL, marginT, marginR, _ := pdf.GetMargins()
+	contentW := pageW - marginL - marginR
+	sum := s.Summary()
+
+	// ── Header bar ───────────────────────────────────────────────────────────
+	pdf.SetFillColor(30, 30, 30)
+	pdf.Rect(marginL, marginT, contentW, 10, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetXY(marginL+2, marginT+1.5)
+	pdf.CellFormat(contentW-4, 7, "W-2C  CORRECTION SUMMARY", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 7, "Page "+fmt.Sprint(pdf.PageNo())+" of {nb}", "", 1, "R", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	y := marginT + 13
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetXY(marginL, y)
+	pdf.CellFormat(contentW, 6.5, s.Employer.Name, "", 1, "L", false, 0, "")
+	y += 6.5
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetXY(marginL, y)
+	pdf.CellFormat(contentW, 5.5, "EIN: "+formatEIN(s.Employer.EIN)+"   Tax Year: "+s.Employer.TaxYear, "", 1, "L", false, 0, "")
+	y += 9
+
+	// ── Counts ───────────────────────────────────────────────────────────────
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetXY(marginL, y)
+	pdf.CellFormat(contentW, 5.5, "COUNTS", "1", 1, "L", true, 0, "")
+	y += 5.5
+
+	counts := []struct {
+		label string
+		n     int
+	}{
+		{"Employees", sum.Employees},
+		{"Employees with changes", sum.EmployeesWithChanges},
+		{"RCW records", sum.RCWRecords},
+		{"RCO records", sum.RCORecords},
+		{"RCS records", sum.RCSRecords},
+		{"RCU records", sum.RCURecords},
+		{"Total records in file", sum.TotalRecords},
+	}
+	pdf.SetFont("Helvetica", "", 9)
+	for _, c := range counts {
+		pdf.SetXY(marginL, y)
+		pdf.CellFormat(contentW*0.7, 6, c.label, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(contentW*0.3, 6, fmt.Sprint(c.n), "1", 1, "R", false, 0, "")
+		y += 6
+	}
+	y += 5
+
+	// ── Net deltas ───────────────────────────────────────────────────────────
+	pdf.SetFillColor(30, 30, 30)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 8.5)
+	pdf.SetXY(marginL, y)
+	pdf.CellFormat(contentW*0.7, 7, "Box", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(contentW*0.3, 7, "Net Change", "1", 1, "C", true, 0, "")
+	y += 7
+	pdf.SetTextColor(0, 0, 0)
+
+	d := sum.Deltas
+	deltas := []struct {
+		label string
+		cents int64
+	}{
+		{"Box 1 - Wages, Tips, Other Comp.", d.WagesTipsOther},
+		{"Box 2 - Federal Income Tax Withheld", d.FederalIncomeTax},
+		{"Box 3 - Social Security Wages", d.SocialSecurityWages},
+		{"Box 4 - Social Security Tax Withheld", d.SocialSecurityTax},
+		{"Box 5 - Medicare Wages and Tips", d.MedicareWages},
+		{"Box 6 - Medicare Tax Withheld", d.MedicareTax},
+		{"Box 7 - Social Security Tips", d.SocialSecurityTips},
+		{"Box 8 - Allocated Tips", d.AllocatedTips},
+		{"Box 16 - State Wages, Tips, etc.", d.StateWages},
+		{"Box 17 - State Income Tax", d.StateIncomeTax},
+		{"Box 18 - Local Wages, Tips, etc.", d.LocalWages},
+		{"Box 19 - Local Income Tax", d.LocalIncomeTax},
+	}
+	for _, r := range deltas {
+		pdf.SetXY(marginL, y)
+		if r.cents != 0 {
+			pdf.SetFont("Helvetica", "B", 8.5)
+		} else {
+			pdf.SetFont("Helvetica", "", 8.5)
+		}
+		pdf.CellFormat(contentW*0.7, 6.5, r.label, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(contentW*0.3, 6.5, signedCents(r.cents), "1", 1, "R", false, 0, "")
+		y += 6.5
+	}
 }
 
-func drawEmployeePage(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRecord) {
+func drawEmployeePage(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRecord, ssn func(string) string) {
 	pageW, pageH := pdf.GetPageSize()
 	marginL, marginT, marginR, marginB := pdf.GetMargins()
 	contentW := pageW - marginL - marginR
@@ -92,13 +262,13 @@ func drawEmployeePage(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRe
 	pdf.SetXY(marginL, y)
 	pdf.CellFormat(colHalf, 6.5, name, "L", 0, "L", false, 0, "")
 	pdf.SetFont("Helvetica", "", 9)
-	pdf.CellFormat(colHalf, 6.5, "SSN: "+formatSSN(e.SSN), "R", 1, "R", false, 0, "")
+	pdf.CellFormat(colHalf, 6.5, "SSN: "+ssn(e.SSN), "R", 1, "R", false, 0, "")
 	y += 6.5
 
 	if e.OriginalSSN != "" {
 		pdf.SetFont("Helvetica", "I", 8.5)
 		pdf.SetXY(marginL, y)
-		pdf.CellFormat(contentW, 5.5, "Original SSN: "+formatSSN(e.OriginalSSN), "LR", 1, "L", false, 0, "")
+		pdf.CellFormat(contentW, 5.5, "Original SSN: "+ssn(e.OriginalSSN), "LR", 1, "L", false, 0, "")
 		y += 5.5
 	}
 
@@ -160,6 +330,14 @@ func drawEmployeePage(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRe
 	// Optional boxes — only included when at least one value is non-zero
 	optRows := []amtRow{
 		{"Box 8 - Allocated Tips", e.Amounts.OriginalAllocatedTips, e.Amounts.CorrectAllocatedTips},
+		{"Box 12 Codes A/B - Uncollected Tax on Tips", e.Amounts.OriginalUncollectedEETax, e.Amounts.CorrectUncollectedEETax},
+		{"Box 12 Code R - Archer MSA Contributions", e.Amounts.OriginalCodeR_MSA, e.Amounts.CorrectCodeR_MSA},
+		{"Box 12 Code S - 408(p) SIMPLE Deferrals", e.Amounts.OriginalCodeS_SIMPLE, e.Amounts.CorrectCodeS_SIMPLE},
+		{"Box 12 Code T - Adoption Benefits", e.Amounts.OriginalCodeT_Adoption, e.Amounts.CorrectCodeT_Adoption},
+		{"Box 12 Code M - Uncollected SS/RRTA Tax on GTL", e.Amounts.OriginalCodeM_UncollSS, e.Amounts.CorrectCodeM_UncollSS},
+		{"Box 12 Code N - Uncollected Medicare Tax on GTL", e.Amounts.OriginalCodeN_UncollMed, e.Amounts.CorrectCodeN_UncollMed},
+		{"Box 12 Code Z - 409A Income (failed plan)", e.Amounts.OriginalCodeZ_409A, e.Amounts.CorrectCodeZ_409A},
+		{"Box 12 Code II - Medicaid Waiver Payments", e.Amounts.OriginalMedicaidWaiver, e.Amounts.CorrectMedicaidWaiver},
 		{"Box 10 - Dependent Care Benefits", e.Amounts.OriginalDependentCare, e.Amounts.CorrectDependentCare},
 		{"Box 11 - Nonqual Plans (Sec 457)", e.Amounts.OriginalNonqualPlan457, e.Amounts.CorrectNonqualPlan457},
 		{"Box 11 - Nonqual Plans (Non-457)", e.Amounts.OriginalNonqualNotSection457, e.Amounts.CorrectNonqualNotSection457},
@@ -170,6 +348,13 @@ func drawEmployeePage(pdf *fpdf.Fpdf, s *domain.Submission, e *domain.EmployeeRe
 		{"Box 12 Code AA - Roth 401(k)", e.Amounts.OriginalCodeAA_Roth401k, e.Amounts.CorrectCodeAA_Roth401k},
 		{"Box 12 Code BB - Roth 403(b)", e.Amounts.OriginalCodeBB_Roth403b, e.Amounts.CorrectCodeBB_Roth403b},
 		{"Box 12 Code DD - Employer Health Coverage", e.Amounts.OriginalCodeDD_EmpHealth, e.Amounts.CorrectCodeDD_EmpHealth},
+		{"Box 12 Code C - Taxable Group-Term Life", e.Amounts.OriginalCodeC_GroupTermLife, e.Amounts.CorrectCodeC_GroupTermLife},
+		{"Box 12 Code F - 408(k)(6) SARSEP Deferrals", e.Amounts.OriginalCodeF_SARSEP, e.Amounts.CorrectCodeF_SARSEP},
+		{"Box 12 Code H - 501(c)(18)(D) Plan Deferrals", e.Amounts.OriginalCodeH_501c18D, e.Amounts.CorrectCodeH_501c18D},
+		{"Box 12 Code Q - Nontaxable Combat Pay", e.Amounts.OriginalCodeQ_CombatPay, e.Amounts.CorrectCodeQ_CombatPay},
+		{"Box 12 Code V - Nonstatutory Stock Options", e.Amounts.OriginalCodeV_NSO, e.Amounts.CorrectCodeV_NSO},
+		{"Box 12 Code Y - 409A NQDC Deferrals", e.Amounts.OriginalCodeY_409A, e.Amounts.CorrectCodeY_409A},
+		{"Box 12 Code FF - QSEHRA Permitted Benefits", e.Amounts.OriginalCodeFF_QSEHRA, e.Amounts.CorrectCodeFF_QSEHRA},
 		{"Box 16 - State Wages, Tips, etc.", e.Amounts.OriginalStateWages, e.Amounts.CorrectStateWages},
 		{"Box 17 - State Income Tax", e.Amounts.OriginalStateIncomeTax, e.Amounts.CorrectStateIncomeTax},
 		{"Box 18 - Local Wages, Tips, etc.", e.Amounts.OriginalLocalWages, e.Amounts.CorrectLocalWages},
@@ -340,6 +525,16 @@ func formatSSN(ssn string) string {
 	return ssn
 }
 
+// maskSSN is formatSSN showing only the last four digits. Values that are
+// not nine digits are masked entirely rather than echoed.
+func maskSSN(ssn string) string {
+	digits := strings.ReplaceAll(ssn, "-", "")
+	if len(digits) == 9 {
+		return "XXX-XX-" + digits[5:]
+	}
+	return "XXX-XX-XXXX"
+}
+
 func formatEIN(ein string) string {
 	digits := strings.ReplaceAll(ein, "-", "")
 	if len(digits) == 9 {
@@ -352,6 +547,14 @@ func centsToDisplay(cents int64) string {
 	return fmt.Sprintf("%.2f", float64(cents)/100)
 }
 
+// signedCents renders a delta as "+$1.00" / "-$1.00".
+func signedCents(cents int64) string {
+	if cents < 0 {
+		return "-$" + centsToDisplay(-cents)
+	}
+	return "+$" + centsToDisplay(cents)
+}
+
 // cityLine returns ", City, ST ZIP" ready to append to an address, or "".
 func cityLine(city, state, zip string) string {
 	if city == "" && state == "" && zip == "" {
@@ -0,0 +1,112 @@
+package pdf_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/pdf"
+	"github.com/csg33k/w2c-generator/internal/fixtures"
+)
+
+// TestGeneratePDF_WithPassword verifies a password adds the standard
+// security handler's encryption dictionary, and that its absence does not.
+func TestGeneratePDF_WithPassword(t *testing.T) {
+	s := fixtures.AllBoxes()
+
+	var plain, enc bytes.Buffer
+	if err := pdf.GeneratePDF(context.Background(), s, &plain); err != nil {
+		t.Fatalf("GeneratePDF (plain): %v", err)
+	}
+	if err := pdf.GeneratePDF(context.Background(), s, &enc, pdf.WithPassword("s3cret", "")); err != nil {
+		t.Fatalf("GeneratePDF (encrypted): %v", err)
+	}
+
+	if strings.Contains(plain.String(), "/Encrypt") {
+		t.Error("plain PDF: want no /Encrypt entry")
+	}
+	out := enc.String()
+	for _, marker := range []string{"/Filter /Standard", "/Encrypt", "/O (", "/U ("} {
+		if !strings.Contains(out, marker) {
+			t.Errorf("encrypted PDF: missing %q", marker)
+		}
+	}
+}
+
+// TestGeneratePDF_WithMaskedSSN verifies the distribution copy shows only the
+// last four SSN digits while the default copy shows the full SSN.
+func TestGeneratePDF_WithMaskedSSN(t *testing.T) {
+	s := fixtures.AllBoxes()
+	e := s.Employees[0]
+	full := e.SSN[:3] + "-" + e.SSN[3:5] + "-" + e.SSN[5:]
+
+	var plain, masked bytes.Buffer
+	if err := pdf.GeneratePDF(context.Background(), s, &plain); err != nil {
+		t.Fatalf("GeneratePDF (plain): %v", err)
+	}
+	if err := pdf.GeneratePDF(context.Background(), s, &masked, pdf.WithMaskedSSN()); err != nil {
+		t.Fatalf("GeneratePDF (masked): %v", err)
+	}
+
+	if text := pageText(t, plain.Bytes()); !strings.Contains(text, full) {
+		t.Errorf("default copy: want full SSN %s", full)
+	}
+	text := pageText(t, masked.Bytes())
+	if !strings.Contains(text, "XXX-XX-"+e.SSN[5:]) {
+		t.Errorf("masked copy: want XXX-XX-%s", e.SSN[5:])
+	}
+	if strings.Contains(text, full) || strings.Contains(text, e.SSN) {
+		t.Errorf("masked copy: full SSN %s must not appear", full)
+	}
+}
+
+// TestGenerateW2cFormPDF verifies the form layout draws one page per
+// employee with no summary page, and fills in the corrected boxes.
+func TestGenerateW2cFormPDF(t *testing.T) {
+	s := fixtures.AllBoxes()
+	s.Employees = append(s.Employees, s.Employees[0])
+
+	var buf bytes.Buffer
+	if err := pdf.GenerateW2cFormPDF(context.Background(), s, &buf); err != nil {
+		t.Fatalf("GenerateW2cFormPDF: %v", err)
+	}
+	doc := buf.Bytes()
+	if !bytes.HasPrefix(doc, []byte("%PDF-")) {
+		t.Fatalf("want a PDF header, got %q", doc[:min(len(doc), 8)])
+	}
+	if got := len(pageRE.FindAll(doc, -1)); got != len(s.Employees) {
+		t.Errorf("page count: want %d, got %d", len(s.Employees), got)
+	}
+	text := pageText(t, doc)
+	for _, want := range []string{"Form W-2c", "Copy B", "Previously reported", "Correct information", "12a"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("form: missing %q", want)
+		}
+	}
+}
+
+// pageRE matches a page object, not the /Pages tree root.
+var pageRE = regexp.MustCompile(`/Type /Page\b[^s]`)
+
+var streamRE = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// pageText returns the content streams of a PDF, inflated where fpdf
+// compressed them, so tests can look for drawn text.
+func pageText(t *testing.T, doc []byte) string {
+	t.Helper()
+	var sb strings.Builder
+	for _, m := range streamRE.FindAllSubmatch(doc, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			sb.Write(m[1])
+			continue
+		}
+		b, _ := io.ReadAll(zr)
+		sb.Write(b)
+	}
+	return sb.String()
+}
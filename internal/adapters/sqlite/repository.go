@@ -3,11 +3,15 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/ports"
 )
 
 type Repository struct {
@@ -24,6 +28,39 @@ func New(dsn string) (*Repository, error) {
 	return &Repository{db: db}, nil
 }
 
+// schemaColumns names, per table, the newest column its migrations add;
+// bump it with each migration the repository's statements rely on.
+var schemaColumns = []struct{ table, column string }{
+	{"submissions", "employer_contact_phone_ext"},
+	{"employees", "corr_code_ii"},
+}
+
+// EnsureSchema checks that the dbmate migrations have been applied, so a
+// missing table or column fails at startup with instructions rather than
+// on the first query.
+func (r *Repository) EnsureSchema(ctx context.Context) error {
+	for _, c := range schemaColumns {
+		var n int
+		err := r.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?`, c.table).Scan(&n)
+		if err != nil {
+			return fmt.Errorf("checking schema: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("database has no %s table: run `dbmate up` to apply the migrations in db/migrations", c.table)
+		}
+		err = r.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name=?`, c.table, c.column).Scan(&n)
+		if err != nil {
+			return fmt.Errorf("checking schema: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("table %s has no %s column: the database is behind; run `dbmate up` to apply the pending migrations", c.table, c.column)
+		}
+	}
+	return nil
+}
+
 // ── Submissions ───────────────────────────────────────────────────────────────
 
 func (r *Repository) CreateSubmission(ctx context.Context, s *domain.Submission) error {
@@ -31,15 +68,21 @@ func (r *Repository) CreateSubmission(ctx context.Context, s *domain.Submission)
 	res, err := r.db.ExecContext(ctx, `
 		INSERT INTO submissions (
 			ein, orig_ein, employer_name, addr1, addr2, city, state, zip, zip_ext,
+			country_code, foreign_state_province, foreign_postal_code,
 			agent_indicator, agent_ein, terminating, notes,
 			bso_uid, contact_name, contact_phone, contact_email, preparer_code,
 			employment_code, kind_of_employer,
 			employer_contact_name, employer_contact_phone, employer_contact_email,
-		    created_at, tax_year
-	    ) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		    created_at, tax_year, submitter_company_name,
+		    submitter_addr1, submitter_addr2, submitter_city, submitter_state, submitter_zip, submitter_zip_ext,
+		    orig_third_party_sick, corr_third_party_sick, resub_indicator, resub_wfid,
+		    software_code, software_vendor_code, phone_extension, contact_fax,
+		    employer_contact_phone_ext
+	    ) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
 		s.Employer.EIN, s.Employer.OriginalEIN, s.Employer.Name,
 		s.Employer.AddressLine1, s.Employer.AddressLine2,
 		s.Employer.City, s.Employer.State, s.Employer.ZIP, s.Employer.ZIPExtension,
+		s.Employer.CountryCode, s.Employer.ForeignStateProvince, s.Employer.ForeignPostalCode,
 		s.Employer.AgentIndicator, s.Employer.AgentEIN,
 		boolToInt(s.Employer.TerminatingBusiness),
 		s.Notes,
@@ -47,7 +90,14 @@ func (r *Repository) CreateSubmission(ctx context.Context, s *domain.Submission)
 		s.Submitter.ContactPhone, s.Submitter.ContactEmail, s.Submitter.PreparerCode,
 		s.Employer.EmploymentCode, s.Employer.KindOfEmployer,
 		s.Employer.ContactName, s.Employer.ContactPhone, s.Employer.ContactEmail,
-		s.CreatedAt, s.Employer.TaxYear,
+		s.CreatedAt, s.Employer.TaxYear, s.Submitter.CompanyName,
+		s.Submitter.AddressLine1, s.Submitter.AddressLine2, s.Submitter.City,
+		s.Submitter.State, s.Submitter.ZIP, s.Submitter.ZIPExtension,
+		s.Employer.OriginalThirdPartySick, s.Employer.CorrectThirdPartySick,
+		s.Submitter.ResubIndicator, s.Submitter.ResubWFID,
+		s.Submitter.SoftwareCode, s.Submitter.SoftwareVendorCode,
+		s.Submitter.PhoneExtension, s.Submitter.ContactFax,
+		s.Employer.ContactPhoneExtension,
 	)
 	if err != nil {
 		return err
@@ -63,23 +113,39 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 	var submittedAt sql.NullTime
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, ein, orig_ein, employer_name, addr1, addr2, city, state, zip, zip_ext,
+			country_code, foreign_state_province, foreign_postal_code,
 		       agent_indicator, agent_ein, terminating, notes,
 		       bso_uid, contact_name, contact_phone, contact_email, preparer_code,
 		       employment_code, kind_of_employer,
 		       employer_contact_name, employer_contact_phone, employer_contact_email,
-		       created_at, submitted_at, tax_year
+		       created_at, submitted_at, tax_year, submitter_company_name,
+		       submitter_addr1, submitter_addr2, submitter_city, submitter_state, submitter_zip, submitter_zip_ext,
+		       generate_count, orig_third_party_sick, corr_third_party_sick, resub_indicator, resub_wfid,
+		       software_code, software_vendor_code, phone_extension, contact_fax,
+		       employer_contact_phone_ext
 		FROM submissions WHERE id=?`, id).Scan(
 		&s.ID, &s.Employer.EIN, &s.Employer.OriginalEIN, &s.Employer.Name,
 		&s.Employer.AddressLine1, &s.Employer.AddressLine2,
 		&s.Employer.City, &s.Employer.State, &s.Employer.ZIP, &s.Employer.ZIPExtension,
+		&s.Employer.CountryCode, &s.Employer.ForeignStateProvince, &s.Employer.ForeignPostalCode,
 		&s.Employer.AgentIndicator, &s.Employer.AgentEIN,
 		&terminating, &s.Notes,
 		&s.Submitter.BSOUID, &s.Submitter.ContactName,
 		&s.Submitter.ContactPhone, &s.Submitter.ContactEmail, &s.Submitter.PreparerCode,
 		&s.Employer.EmploymentCode, &s.Employer.KindOfEmployer,
 		&s.Employer.ContactName, &s.Employer.ContactPhone, &s.Employer.ContactEmail,
-		&s.CreatedAt, &submittedAt, &s.Employer.TaxYear,
+		&s.CreatedAt, &submittedAt, &s.Employer.TaxYear, &s.Submitter.CompanyName,
+		&s.Submitter.AddressLine1, &s.Submitter.AddressLine2, &s.Submitter.City,
+		&s.Submitter.State, &s.Submitter.ZIP, &s.Submitter.ZIPExtension,
+		&s.GenerateCount, &s.Employer.OriginalThirdPartySick, &s.Employer.CorrectThirdPartySick,
+		&s.Submitter.ResubIndicator, &s.Submitter.ResubWFID,
+		&s.Submitter.SoftwareCode, &s.Submitter.SoftwareVendorCode,
+		&s.Submitter.PhoneExtension, &s.Submitter.ContactFax,
+		&s.Employer.ContactPhoneExtension,
 	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("submission %d: %w", id, ports.ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -91,11 +157,28 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 		s.SubmittedAt = &submittedAt.Time
 	}
 
+	employees, err := r.listEmployees(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.Employees = employees
+	return s, nil
+}
+
+// listEmployees loads every employee row for a submission, ordered by id.
+func (r *Repository) listEmployees(ctx context.Context, submissionID int64) ([]domain.EmployeeRecord, error) {
+	return r.queryEmployees(ctx, `WHERE submission_id=? ORDER BY id`, submissionID)
+}
+
+// queryEmployees loads the employee rows selected by the clauses that
+// follow FROM (WHERE, ORDER BY).
+func (r *Repository) queryEmployees(ctx context.Context, clauses string, args ...any) ([]domain.EmployeeRecord, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, submission_id, ssn, original_ssn,
 		       first_name, middle_name, last_name, suffix,
 		       orig_first_name, orig_middle_name, orig_last_name, orig_suffix,
 		       addr1, addr2, city, state, zip, zip_ext,
+		       country_code, foreign_state_province, foreign_postal_code,
 		       orig_wages, corr_wages,
 		       orig_ss_wages, corr_ss_wages,
 		       orig_med_wages, corr_med_wages,
@@ -104,6 +187,14 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 		       orig_med_tax, corr_med_tax,
 		       orig_ss_tips, corr_ss_tips,
 		       orig_alloc_tips, corr_alloc_tips,
+		       orig_uncoll_tips_tax, corr_uncoll_tips_tax,
+		       orig_code_r, corr_code_r,
+		       orig_code_s, corr_code_s,
+		       orig_code_t, corr_code_t,
+		       orig_code_m, corr_code_m,
+		       orig_code_n, corr_code_n,
+		       orig_code_z, corr_code_z,
+		       orig_code_ii, corr_code_ii,
 		       orig_dep_care, corr_dep_care,
 		       orig_nonqual_457, corr_nonqual_457,
 		       orig_nonqual_not457, corr_nonqual_not457,
@@ -114,6 +205,13 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 		       orig_code_aa, corr_code_aa,
 		       orig_code_bb, corr_code_bb,
 		       orig_code_dd, corr_code_dd,
+		       orig_code_c, corr_code_c,
+		       orig_code_f, corr_code_f,
+		       orig_code_h, corr_code_h,
+		       orig_code_q, corr_code_q,
+		       orig_code_v, corr_code_v,
+		       orig_code_y, corr_code_y,
+		       orig_code_ff, corr_code_ff,
 		       orig_state_code, corr_state_code,
 		       orig_state_id, corr_state_id,
 		       orig_state_wages, corr_state_wages,
@@ -125,11 +223,12 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 		       orig_retirement_plan, corr_retirement_plan,
 		       orig_third_party_sick, corr_third_party_sick,
 		       created_at, updated_at
-		FROM employees WHERE submission_id=? ORDER BY id`, id)
+		FROM employees `+clauses, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	var list []domain.EmployeeRecord
 	for rows.Next() {
 		var e domain.EmployeeRecord
 		var (
@@ -142,6 +241,7 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 			&e.FirstName, &e.MiddleName, &e.LastName, &e.Suffix,
 			&e.OriginalFirstName, &e.OriginalMiddleName, &e.OriginalLastName, &e.OriginalSuffix,
 			&e.AddressLine1, &e.AddressLine2, &e.City, &e.State, &e.ZIP, &e.ZIPExtension,
+			&e.CountryCode, &e.ForeignStateProvince, &e.ForeignPostalCode,
 			&e.Amounts.OriginalWagesTipsOther, &e.Amounts.CorrectWagesTipsOther,
 			&e.Amounts.OriginalSocialSecurityWages, &e.Amounts.CorrectSocialSecurityWages,
 			&e.Amounts.OriginalMedicareWages, &e.Amounts.CorrectMedicareWages,
@@ -150,6 +250,14 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 			&e.Amounts.OriginalMedicareTax, &e.Amounts.CorrectMedicareTax,
 			&e.Amounts.OriginalSocialSecurityTips, &e.Amounts.CorrectSocialSecurityTips,
 			&e.Amounts.OriginalAllocatedTips, &e.Amounts.CorrectAllocatedTips,
+			&e.Amounts.OriginalUncollectedEETax, &e.Amounts.CorrectUncollectedEETax,
+			&e.Amounts.OriginalCodeR_MSA, &e.Amounts.CorrectCodeR_MSA,
+			&e.Amounts.OriginalCodeS_SIMPLE, &e.Amounts.CorrectCodeS_SIMPLE,
+			&e.Amounts.OriginalCodeT_Adoption, &e.Amounts.CorrectCodeT_Adoption,
+			&e.Amounts.OriginalCodeM_UncollSS, &e.Amounts.CorrectCodeM_UncollSS,
+			&e.Amounts.OriginalCodeN_UncollMed, &e.Amounts.CorrectCodeN_UncollMed,
+			&e.Amounts.OriginalCodeZ_409A, &e.Amounts.CorrectCodeZ_409A,
+			&e.Amounts.OriginalMedicaidWaiver, &e.Amounts.CorrectMedicaidWaiver,
 			&e.Amounts.OriginalDependentCare, &e.Amounts.CorrectDependentCare,
 			&e.Amounts.OriginalNonqualPlan457, &e.Amounts.CorrectNonqualPlan457,
 			&e.Amounts.OriginalNonqualNotSection457, &e.Amounts.CorrectNonqualNotSection457,
@@ -160,6 +268,13 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 			&e.Amounts.OriginalCodeAA_Roth401k, &e.Amounts.CorrectCodeAA_Roth401k,
 			&e.Amounts.OriginalCodeBB_Roth403b, &e.Amounts.CorrectCodeBB_Roth403b,
 			&e.Amounts.OriginalCodeDD_EmpHealth, &e.Amounts.CorrectCodeDD_EmpHealth,
+			&e.Amounts.OriginalCodeC_GroupTermLife, &e.Amounts.CorrectCodeC_GroupTermLife,
+			&e.Amounts.OriginalCodeF_SARSEP, &e.Amounts.CorrectCodeF_SARSEP,
+			&e.Amounts.OriginalCodeH_501c18D, &e.Amounts.CorrectCodeH_501c18D,
+			&e.Amounts.OriginalCodeQ_CombatPay, &e.Amounts.CorrectCodeQ_CombatPay,
+			&e.Amounts.OriginalCodeV_NSO, &e.Amounts.CorrectCodeV_NSO,
+			&e.Amounts.OriginalCodeY_409A, &e.Amounts.CorrectCodeY_409A,
+			&e.Amounts.OriginalCodeFF_QSEHRA, &e.Amounts.CorrectCodeFF_QSEHRA,
 			&e.OriginalStateCode, &e.CorrectStateCode,
 			&e.OriginalStateIDNumber, &e.CorrectStateIDNumber,
 			&e.Amounts.OriginalStateWages, &e.Amounts.CorrectStateWages,
@@ -176,15 +291,98 @@ func (r *Repository) GetSubmission(ctx context.Context, id int64) (*domain.Submi
 		}
 		e.Box13 = nullIntToBox13(origStatuory, corrStatutory, origRetirement, corrRetirement,
 			origThirdParty, corrThirdParty)
-		s.Employees = append(s.Employees, e)
+		list = append(list, e)
 	}
-	return s, nil
+	return list, rows.Err()
 }
 
 func (r *Repository) ListSubmissions(ctx context.Context) ([]domain.Submission, error) {
+	return r.listSubmissions(ctx, `ORDER BY created_at DESC, id DESC`)
+}
+
+// SearchSubmissions lists the submissions whose employer name contains
+// query (case-insensitive) or whose EIN contains its digits, newest first.
+// An empty query lists every submission, as ListSubmissions does.
+func (r *Repository) SearchSubmissions(ctx context.Context, query string) ([]domain.Submission, error) {
+	where, args := searchWhere(query)
+	return r.listSubmissions(ctx, where+` ORDER BY created_at DESC, id DESC`, args...)
+}
+
+// ListSubmissionsPage returns up to limit submissions matching query (see
+// SearchSubmissions), skipping the first offset, and the total number of
+// matches.
+func (r *Repository) ListSubmissionsPage(ctx context.Context, query string, limit, offset int) ([]domain.Submission, int, error) {
+	where, args := searchWhere(query)
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM submissions `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	list, err := r.listSubmissions(ctx, where+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...)
+	return list, total, err
+}
+
+// searchWhere returns the WHERE clause and arguments matching query; both
+// are empty for an empty query.
+func searchWhere(query string) (string, []any) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", nil
+	}
+	where := `WHERE employer_name LIKE ? ESCAPE '\'`
+	args := []any{"%" + likeEscaper.Replace(query) + "%"}
+	// EINs are stored as bare digits, so "12-345" matches on "12345".
+	if ein := strings.NewReplacer("-", "", " ", "").Replace(query); ein != "" && strings.Trim(ein, "0123456789") == "" {
+		where += ` OR ein LIKE ?`
+		args = append(args, "%"+ein+"%")
+	}
+	return where, args
+}
+
+// likeEscaper escapes LIKE wildcards so a query matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListSubmissionsByEIN lists the other submissions for employer ein (all
+// but excludeID), newest first. Employees are not loaded.
+func (r *Repository) ListSubmissionsByEIN(ctx context.Context, ein string, excludeID int64) ([]domain.Submission, error) {
+	return r.querySubmissions(ctx, `WHERE ein = ? AND id <> ? ORDER BY created_at DESC, id DESC`, ein, excludeID)
+}
+
+// listSubmissions lists the submissions selected by the clauses that follow
+// FROM (WHERE, ORDER BY, LIMIT), each with its employees loaded.
+func (r *Repository) listSubmissions(ctx context.Context, clauses string, args ...any) ([]domain.Submission, error) {
+	list, err := r.querySubmissions(ctx, clauses, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+	// Employees are loaded so the list view can show each submission's
+	// correction summary: one query, selecting the listed submissions with
+	// the same clauses.
+	byID := make(map[int64]*domain.Submission, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	employees, err := r.queryEmployees(ctx,
+		`WHERE submission_id IN (SELECT id FROM submissions `+clauses+`) ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range employees {
+		if s := byID[e.SubmissionID]; s != nil {
+			s.Employees = append(s.Employees, e)
+		}
+	}
+	return list, nil
+}
+
+// querySubmissions is listSubmissions without the employees.
+func (r *Repository) querySubmissions(ctx context.Context, clauses string, args ...any) ([]domain.Submission, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, ein, employer_name, notes, created_at
-		FROM submissions ORDER BY created_at DESC`)
+		SELECT id, ein, employer_name, notes, created_at, tax_year
+		FROM submissions `+clauses, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -192,27 +390,33 @@ func (r *Repository) ListSubmissions(ctx context.Context) ([]domain.Submission,
 	var list []domain.Submission
 	for rows.Next() {
 		var s domain.Submission
-		if err := rows.Scan(&s.ID, &s.Employer.EIN, &s.Employer.Name, &s.Notes, &s.CreatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.Employer.EIN, &s.Employer.Name, &s.Notes, &s.CreatedAt, &s.Employer.TaxYear); err != nil {
 			return nil, err
 		}
 		list = append(list, s)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
 func (r *Repository) UpdateSubmission(ctx context.Context, s *domain.Submission) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE submissions
 		SET ein=?, orig_ein=?, employer_name=?, addr1=?, addr2=?, city=?, state=?, zip=?, zip_ext=?,
+		    country_code=?, foreign_state_province=?, foreign_postal_code=?,
 		    agent_indicator=?, agent_ein=?, terminating=?, notes=?,
 		    bso_uid=?, contact_name=?, contact_phone=?, contact_email=?, preparer_code=?,
 		    employment_code=?, kind_of_employer=?,
 		    employer_contact_name=?, employer_contact_phone=?, employer_contact_email=?,
-		    tax_year=?
+		    tax_year=?, submitter_company_name=?,
+		    submitter_addr1=?, submitter_addr2=?, submitter_city=?, submitter_state=?, submitter_zip=?, submitter_zip_ext=?,
+		    orig_third_party_sick=?, corr_third_party_sick=?, resub_indicator=?, resub_wfid=?,
+		    software_code=?, software_vendor_code=?, phone_extension=?, contact_fax=?,
+		    employer_contact_phone_ext=?
         WHERE id=?`,
 		s.Employer.EIN, s.Employer.OriginalEIN, s.Employer.Name,
 		s.Employer.AddressLine1, s.Employer.AddressLine2,
 		s.Employer.City, s.Employer.State, s.Employer.ZIP, s.Employer.ZIPExtension,
+		s.Employer.CountryCode, s.Employer.ForeignStateProvince, s.Employer.ForeignPostalCode,
 		s.Employer.AgentIndicator, s.Employer.AgentEIN,
 		boolToInt(s.Employer.TerminatingBusiness),
 		s.Notes,
@@ -220,11 +424,31 @@ func (r *Repository) UpdateSubmission(ctx context.Context, s *domain.Submission)
 		s.Submitter.ContactPhone, s.Submitter.ContactEmail, s.Submitter.PreparerCode,
 		s.Employer.EmploymentCode, s.Employer.KindOfEmployer,
 		s.Employer.ContactName, s.Employer.ContactPhone, s.Employer.ContactEmail,
-		s.Employer.TaxYear, s.ID,
+		s.Employer.TaxYear, s.Submitter.CompanyName,
+		s.Submitter.AddressLine1, s.Submitter.AddressLine2, s.Submitter.City,
+		s.Submitter.State, s.Submitter.ZIP, s.Submitter.ZIPExtension,
+		s.Employer.OriginalThirdPartySick, s.Employer.CorrectThirdPartySick,
+		s.Submitter.ResubIndicator, s.Submitter.ResubWFID,
+		s.Submitter.SoftwareCode, s.Submitter.SoftwareVendorCode,
+		s.Submitter.PhoneExtension, s.Submitter.ContactFax,
+		s.Employer.ContactPhoneExtension,
+		s.ID,
 	)
 	return err
 }
 
+// IncrementGenerateCount bumps the submission's generate_count by one.
+func (r *Repository) IncrementGenerateCount(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE submissions SET generate_count = generate_count + 1 WHERE id=?`, id)
+	return err
+}
+
+// MarkSubmitted sets the submission's submitted_at to now.
+func (r *Repository) MarkSubmitted(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE submissions SET submitted_at = ? WHERE id=?`, time.Now(), id)
+	return err
+}
+
 func (r *Repository) DeleteSubmission(ctx context.Context, id int64) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM submissions WHERE id=?`, id)
 	return err
@@ -233,17 +457,77 @@ func (r *Repository) DeleteSubmission(ctx context.Context, id int64) error {
 // ── Employees ─────────────────────────────────────────────────────────────────
 
 func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *domain.EmployeeRecord) error {
+	return insertEmployee(ctx, r.db, submissionID, e)
+}
+
+// AddEmployees inserts a batch of employees in one transaction: either all
+// are added or, on the first error, none are. IDs are set on success only.
+func (r *Repository) AddEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	added := make([]domain.EmployeeRecord, len(employees))
+	copy(added, employees)
+	for i := range added {
+		if err := insertEmployee(ctx, tx, submissionID, &added[i]); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	copy(employees, added)
+	return nil
+}
+
+// UpsertEmployees saves a batch in one transaction: employees with an ID
+// are updated, the rest are added to the submission. Either every change
+// is applied or, on the first error, none is. IDs are set on success only.
+func (r *Repository) UpsertEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	saved := make([]domain.EmployeeRecord, len(employees))
+	copy(saved, employees)
+	for i := range saved {
+		if saved[i].ID != 0 {
+			err = updateEmployee(ctx, tx, &saved[i])
+		} else {
+			err = insertEmployee(ctx, tx, submissionID, &saved[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	copy(employees, saved)
+	return nil
+}
+
+// execer is the ExecContext method shared by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertEmployee(ctx context.Context, db execer, submissionID int64, e *domain.EmployeeRecord) error {
 	now := time.Now()
 	e.SubmissionID = submissionID
 	e.CreatedAt = now
 	e.UpdatedAt = now
 	b13 := box13ToNullInt(e.Box13)
-	res, err := r.db.ExecContext(ctx, `
+	res, err := db.ExecContext(ctx, `
 		INSERT INTO employees (
 			submission_id, ssn, original_ssn,
 			first_name, middle_name, last_name, suffix,
 			orig_first_name, orig_middle_name, orig_last_name, orig_suffix,
 			addr1, addr2, city, state, zip, zip_ext,
+			country_code, foreign_state_province, foreign_postal_code,
 			orig_wages, corr_wages,
 			orig_ss_wages, corr_ss_wages,
 			orig_med_wages, corr_med_wages,
@@ -252,6 +536,14 @@ func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *dom
 			orig_med_tax, corr_med_tax,
 			orig_ss_tips, corr_ss_tips,
 			orig_alloc_tips, corr_alloc_tips,
+			orig_uncoll_tips_tax, corr_uncoll_tips_tax,
+			orig_code_r, corr_code_r,
+			orig_code_s, corr_code_s,
+			orig_code_t, corr_code_t,
+			orig_code_m, corr_code_m,
+			orig_code_n, corr_code_n,
+			orig_code_z, corr_code_z,
+			orig_code_ii, corr_code_ii,
 			orig_dep_care, corr_dep_care,
 			orig_nonqual_457, corr_nonqual_457,
 			orig_nonqual_not457, corr_nonqual_not457,
@@ -262,6 +554,13 @@ func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *dom
 			orig_code_aa, corr_code_aa,
 			orig_code_bb, corr_code_bb,
 			orig_code_dd, corr_code_dd,
+			orig_code_c, corr_code_c,
+			orig_code_f, corr_code_f,
+			orig_code_h, corr_code_h,
+			orig_code_q, corr_code_q,
+			orig_code_v, corr_code_v,
+			orig_code_y, corr_code_y,
+			orig_code_ff, corr_code_ff,
 			orig_state_code, corr_state_code,
 			orig_state_id, corr_state_id,
 			orig_state_wages, corr_state_wages,
@@ -274,12 +573,13 @@ func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *dom
 			orig_third_party_sick, corr_third_party_sick,
 			created_at, updated_at
 		) VALUES (
-			?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?
+			?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?
 		)`,
 		submissionID, e.SSN, e.OriginalSSN,
 		e.FirstName, e.MiddleName, e.LastName, e.Suffix,
 		e.OriginalFirstName, e.OriginalMiddleName, e.OriginalLastName, e.OriginalSuffix,
 		e.AddressLine1, e.AddressLine2, e.City, e.State, e.ZIP, e.ZIPExtension,
+		e.CountryCode, e.ForeignStateProvince, e.ForeignPostalCode,
 		e.Amounts.OriginalWagesTipsOther, e.Amounts.CorrectWagesTipsOther,
 		e.Amounts.OriginalSocialSecurityWages, e.Amounts.CorrectSocialSecurityWages,
 		e.Amounts.OriginalMedicareWages, e.Amounts.CorrectMedicareWages,
@@ -288,6 +588,14 @@ func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *dom
 		e.Amounts.OriginalMedicareTax, e.Amounts.CorrectMedicareTax,
 		e.Amounts.OriginalSocialSecurityTips, e.Amounts.CorrectSocialSecurityTips,
 		e.Amounts.OriginalAllocatedTips, e.Amounts.CorrectAllocatedTips,
+		e.Amounts.OriginalUncollectedEETax, e.Amounts.CorrectUncollectedEETax,
+		e.Amounts.OriginalCodeR_MSA, e.Amounts.CorrectCodeR_MSA,
+		e.Amounts.OriginalCodeS_SIMPLE, e.Amounts.CorrectCodeS_SIMPLE,
+		e.Amounts.OriginalCodeT_Adoption, e.Amounts.CorrectCodeT_Adoption,
+		e.Amounts.OriginalCodeM_UncollSS, e.Amounts.CorrectCodeM_UncollSS,
+		e.Amounts.OriginalCodeN_UncollMed, e.Amounts.CorrectCodeN_UncollMed,
+		e.Amounts.OriginalCodeZ_409A, e.Amounts.CorrectCodeZ_409A,
+		e.Amounts.OriginalMedicaidWaiver, e.Amounts.CorrectMedicaidWaiver,
 		e.Amounts.OriginalDependentCare, e.Amounts.CorrectDependentCare,
 		e.Amounts.OriginalNonqualPlan457, e.Amounts.CorrectNonqualPlan457,
 		e.Amounts.OriginalNonqualNotSection457, e.Amounts.CorrectNonqualNotSection457,
@@ -298,6 +606,13 @@ func (r *Repository) AddEmployee(ctx context.Context, submissionID int64, e *dom
 		e.Amounts.OriginalCodeAA_Roth401k, e.Amounts.CorrectCodeAA_Roth401k,
 		e.Amounts.OriginalCodeBB_Roth403b, e.Amounts.CorrectCodeBB_Roth403b,
 		e.Amounts.OriginalCodeDD_EmpHealth, e.Amounts.CorrectCodeDD_EmpHealth,
+		e.Amounts.OriginalCodeC_GroupTermLife, e.Amounts.CorrectCodeC_GroupTermLife,
+		e.Amounts.OriginalCodeF_SARSEP, e.Amounts.CorrectCodeF_SARSEP,
+		e.Amounts.OriginalCodeH_501c18D, e.Amounts.CorrectCodeH_501c18D,
+		e.Amounts.OriginalCodeQ_CombatPay, e.Amounts.CorrectCodeQ_CombatPay,
+		e.Amounts.OriginalCodeV_NSO, e.Amounts.CorrectCodeV_NSO,
+		e.Amounts.OriginalCodeY_409A, e.Amounts.CorrectCodeY_409A,
+		e.Amounts.OriginalCodeFF_QSEHRA, e.Amounts.CorrectCodeFF_QSEHRA,
 		e.OriginalStateCode, e.CorrectStateCode,
 		e.OriginalStateIDNumber, e.CorrectStateIDNumber,
 		e.Amounts.OriginalStateWages, e.Amounts.CorrectStateWages,
@@ -330,6 +645,7 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		       first_name, middle_name, last_name, suffix,
 		       orig_first_name, orig_middle_name, orig_last_name, orig_suffix,
 		       addr1, addr2, city, state, zip, zip_ext,
+		       country_code, foreign_state_province, foreign_postal_code,
 		       orig_wages, corr_wages,
 		       orig_ss_wages, corr_ss_wages,
 		       orig_med_wages, corr_med_wages,
@@ -338,6 +654,14 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		       orig_med_tax, corr_med_tax,
 		       orig_ss_tips, corr_ss_tips,
 		       orig_alloc_tips, corr_alloc_tips,
+		       orig_uncoll_tips_tax, corr_uncoll_tips_tax,
+		       orig_code_r, corr_code_r,
+		       orig_code_s, corr_code_s,
+		       orig_code_t, corr_code_t,
+		       orig_code_m, corr_code_m,
+		       orig_code_n, corr_code_n,
+		       orig_code_z, corr_code_z,
+		       orig_code_ii, corr_code_ii,
 		       orig_dep_care, corr_dep_care,
 		       orig_nonqual_457, corr_nonqual_457,
 		       orig_nonqual_not457, corr_nonqual_not457,
@@ -348,6 +672,13 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		       orig_code_aa, corr_code_aa,
 		       orig_code_bb, corr_code_bb,
 		       orig_code_dd, corr_code_dd,
+		       orig_code_c, corr_code_c,
+		       orig_code_f, corr_code_f,
+		       orig_code_h, corr_code_h,
+		       orig_code_q, corr_code_q,
+		       orig_code_v, corr_code_v,
+		       orig_code_y, corr_code_y,
+		       orig_code_ff, corr_code_ff,
 		       orig_state_code, corr_state_code,
 		       orig_state_id, corr_state_id,
 		       orig_state_wages, corr_state_wages,
@@ -364,6 +695,7 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		&e.FirstName, &e.MiddleName, &e.LastName, &e.Suffix,
 		&e.OriginalFirstName, &e.OriginalMiddleName, &e.OriginalLastName, &e.OriginalSuffix,
 		&e.AddressLine1, &e.AddressLine2, &e.City, &e.State, &e.ZIP, &e.ZIPExtension,
+		&e.CountryCode, &e.ForeignStateProvince, &e.ForeignPostalCode,
 		&e.Amounts.OriginalWagesTipsOther, &e.Amounts.CorrectWagesTipsOther,
 		&e.Amounts.OriginalSocialSecurityWages, &e.Amounts.CorrectSocialSecurityWages,
 		&e.Amounts.OriginalMedicareWages, &e.Amounts.CorrectMedicareWages,
@@ -372,6 +704,14 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		&e.Amounts.OriginalMedicareTax, &e.Amounts.CorrectMedicareTax,
 		&e.Amounts.OriginalSocialSecurityTips, &e.Amounts.CorrectSocialSecurityTips,
 		&e.Amounts.OriginalAllocatedTips, &e.Amounts.CorrectAllocatedTips,
+		&e.Amounts.OriginalUncollectedEETax, &e.Amounts.CorrectUncollectedEETax,
+		&e.Amounts.OriginalCodeR_MSA, &e.Amounts.CorrectCodeR_MSA,
+		&e.Amounts.OriginalCodeS_SIMPLE, &e.Amounts.CorrectCodeS_SIMPLE,
+		&e.Amounts.OriginalCodeT_Adoption, &e.Amounts.CorrectCodeT_Adoption,
+		&e.Amounts.OriginalCodeM_UncollSS, &e.Amounts.CorrectCodeM_UncollSS,
+		&e.Amounts.OriginalCodeN_UncollMed, &e.Amounts.CorrectCodeN_UncollMed,
+		&e.Amounts.OriginalCodeZ_409A, &e.Amounts.CorrectCodeZ_409A,
+		&e.Amounts.OriginalMedicaidWaiver, &e.Amounts.CorrectMedicaidWaiver,
 		&e.Amounts.OriginalDependentCare, &e.Amounts.CorrectDependentCare,
 		&e.Amounts.OriginalNonqualPlan457, &e.Amounts.CorrectNonqualPlan457,
 		&e.Amounts.OriginalNonqualNotSection457, &e.Amounts.CorrectNonqualNotSection457,
@@ -382,6 +722,13 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 		&e.Amounts.OriginalCodeAA_Roth401k, &e.Amounts.CorrectCodeAA_Roth401k,
 		&e.Amounts.OriginalCodeBB_Roth403b, &e.Amounts.CorrectCodeBB_Roth403b,
 		&e.Amounts.OriginalCodeDD_EmpHealth, &e.Amounts.CorrectCodeDD_EmpHealth,
+		&e.Amounts.OriginalCodeC_GroupTermLife, &e.Amounts.CorrectCodeC_GroupTermLife,
+		&e.Amounts.OriginalCodeF_SARSEP, &e.Amounts.CorrectCodeF_SARSEP,
+		&e.Amounts.OriginalCodeH_501c18D, &e.Amounts.CorrectCodeH_501c18D,
+		&e.Amounts.OriginalCodeQ_CombatPay, &e.Amounts.CorrectCodeQ_CombatPay,
+		&e.Amounts.OriginalCodeV_NSO, &e.Amounts.CorrectCodeV_NSO,
+		&e.Amounts.OriginalCodeY_409A, &e.Amounts.CorrectCodeY_409A,
+		&e.Amounts.OriginalCodeFF_QSEHRA, &e.Amounts.CorrectCodeFF_QSEHRA,
 		&e.OriginalStateCode, &e.CorrectStateCode,
 		&e.OriginalStateIDNumber, &e.CorrectStateIDNumber,
 		&e.Amounts.OriginalStateWages, &e.Amounts.CorrectStateWages,
@@ -402,14 +749,19 @@ func (r *Repository) GetEmployee(ctx context.Context, id int64) (*domain.Employe
 }
 
 func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecord) error {
+	return updateEmployee(ctx, r.db, e)
+}
+
+func updateEmployee(ctx context.Context, db execer, e *domain.EmployeeRecord) error {
 	e.UpdatedAt = time.Now()
 	b13 := box13ToNullInt(e.Box13)
-	_, err := r.db.ExecContext(ctx, `
+	_, err := db.ExecContext(ctx, `
 		UPDATE employees
 		SET ssn=?, original_ssn=?,
 		    first_name=?, middle_name=?, last_name=?, suffix=?,
 		    orig_first_name=?, orig_middle_name=?, orig_last_name=?, orig_suffix=?,
 		    addr1=?, addr2=?, city=?, state=?, zip=?, zip_ext=?,
+		    country_code=?, foreign_state_province=?, foreign_postal_code=?,
 		    orig_wages=?, corr_wages=?,
 		    orig_ss_wages=?, corr_ss_wages=?,
 		    orig_med_wages=?, corr_med_wages=?,
@@ -418,6 +770,14 @@ func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecor
 		    orig_med_tax=?, corr_med_tax=?,
 		    orig_ss_tips=?, corr_ss_tips=?,
 		    orig_alloc_tips=?, corr_alloc_tips=?,
+		    orig_uncoll_tips_tax=?, corr_uncoll_tips_tax=?,
+		    orig_code_r=?, corr_code_r=?,
+		    orig_code_s=?, corr_code_s=?,
+		    orig_code_t=?, corr_code_t=?,
+		    orig_code_m=?, corr_code_m=?,
+		    orig_code_n=?, corr_code_n=?,
+		    orig_code_z=?, corr_code_z=?,
+		    orig_code_ii=?, corr_code_ii=?,
 		    orig_dep_care=?, corr_dep_care=?,
 		    orig_nonqual_457=?, corr_nonqual_457=?,
 		    orig_nonqual_not457=?, corr_nonqual_not457=?,
@@ -428,6 +788,13 @@ func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecor
 		    orig_code_aa=?, corr_code_aa=?,
 		    orig_code_bb=?, corr_code_bb=?,
 		    orig_code_dd=?, corr_code_dd=?,
+		    orig_code_c=?, corr_code_c=?,
+		    orig_code_f=?, corr_code_f=?,
+		    orig_code_h=?, corr_code_h=?,
+		    orig_code_q=?, corr_code_q=?,
+		    orig_code_v=?, corr_code_v=?,
+		    orig_code_y=?, corr_code_y=?,
+		    orig_code_ff=?, corr_code_ff=?,
 		    orig_state_code=?, corr_state_code=?,
 		    orig_state_id=?, corr_state_id=?,
 		    orig_state_wages=?, corr_state_wages=?,
@@ -444,6 +811,7 @@ func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecor
 		e.FirstName, e.MiddleName, e.LastName, e.Suffix,
 		e.OriginalFirstName, e.OriginalMiddleName, e.OriginalLastName, e.OriginalSuffix,
 		e.AddressLine1, e.AddressLine2, e.City, e.State, e.ZIP, e.ZIPExtension,
+		e.CountryCode, e.ForeignStateProvince, e.ForeignPostalCode,
 		e.Amounts.OriginalWagesTipsOther, e.Amounts.CorrectWagesTipsOther,
 		e.Amounts.OriginalSocialSecurityWages, e.Amounts.CorrectSocialSecurityWages,
 		e.Amounts.OriginalMedicareWages, e.Amounts.CorrectMedicareWages,
@@ -452,6 +820,14 @@ func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecor
 		e.Amounts.OriginalMedicareTax, e.Amounts.CorrectMedicareTax,
 		e.Amounts.OriginalSocialSecurityTips, e.Amounts.CorrectSocialSecurityTips,
 		e.Amounts.OriginalAllocatedTips, e.Amounts.CorrectAllocatedTips,
+		e.Amounts.OriginalUncollectedEETax, e.Amounts.CorrectUncollectedEETax,
+		e.Amounts.OriginalCodeR_MSA, e.Amounts.CorrectCodeR_MSA,
+		e.Amounts.OriginalCodeS_SIMPLE, e.Amounts.CorrectCodeS_SIMPLE,
+		e.Amounts.OriginalCodeT_Adoption, e.Amounts.CorrectCodeT_Adoption,
+		e.Amounts.OriginalCodeM_UncollSS, e.Amounts.CorrectCodeM_UncollSS,
+		e.Amounts.OriginalCodeN_UncollMed, e.Amounts.CorrectCodeN_UncollMed,
+		e.Amounts.OriginalCodeZ_409A, e.Amounts.CorrectCodeZ_409A,
+		e.Amounts.OriginalMedicaidWaiver, e.Amounts.CorrectMedicaidWaiver,
 		e.Amounts.OriginalDependentCare, e.Amounts.CorrectDependentCare,
 		e.Amounts.OriginalNonqualPlan457, e.Amounts.CorrectNonqualPlan457,
 		e.Amounts.OriginalNonqualNotSection457, e.Amounts.CorrectNonqualNotSection457,
@@ -462,6 +838,13 @@ func (r *Repository) UpdateEmployee(ctx context.Context, e *domain.EmployeeRecor
 		e.Amounts.OriginalCodeAA_Roth401k, e.Amounts.CorrectCodeAA_Roth401k,
 		e.Amounts.OriginalCodeBB_Roth403b, e.Amounts.CorrectCodeBB_Roth403b,
 		e.Amounts.OriginalCodeDD_EmpHealth, e.Amounts.CorrectCodeDD_EmpHealth,
+		e.Amounts.OriginalCodeC_GroupTermLife, e.Amounts.CorrectCodeC_GroupTermLife,
+		e.Amounts.OriginalCodeF_SARSEP, e.Amounts.CorrectCodeF_SARSEP,
+		e.Amounts.OriginalCodeH_501c18D, e.Amounts.CorrectCodeH_501c18D,
+		e.Amounts.OriginalCodeQ_CombatPay, e.Amounts.CorrectCodeQ_CombatPay,
+		e.Amounts.OriginalCodeV_NSO, e.Amounts.CorrectCodeV_NSO,
+		e.Amounts.OriginalCodeY_409A, e.Amounts.CorrectCodeY_409A,
+		e.Amounts.OriginalCodeFF_QSEHRA, e.Amounts.CorrectCodeFF_QSEHRA,
 		e.OriginalStateCode, e.CorrectStateCode,
 		e.OriginalStateIDNumber, e.CorrectStateIDNumber,
 		e.Amounts.OriginalStateWages, e.Amounts.CorrectStateWages,
@@ -482,6 +865,11 @@ func (r *Repository) DeleteEmployee(ctx context.Context, id int64) error {
 	return err
 }
 
+func (r *Repository) DeleteEmployeesBySubmission(ctx context.Context, submissionID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM employees WHERE submission_id=?`, submissionID)
+	return err
+}
+
 // ── Helpers ───────────────────────────────────────────────────────────────────
 
 func boolToInt(b bool) int {
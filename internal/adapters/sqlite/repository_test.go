@@ -0,0 +1,422 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/sqlite"
+	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/ports"
+)
+
+// newRepo opens a repository on a fresh database loaded with the dbmate
+// schema dump, as `dbmate up` would leave it, then runs any extra setup
+// statements.
+func newRepo(t *testing.T, setup ...string) *sqlite.Repository {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "w2c.db")
+	schema, err := os.ReadFile("../../../db/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	for _, stmt := range setup {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	db.Close()
+	repo, err := sqlite.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// TestEnsureSchema verifies an unmigrated database is reported with a
+// pointer to dbmate, and the migrated schema passes.
+func TestEnsureSchema(t *testing.T) {
+	ctx := context.Background()
+	empty, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := empty.EnsureSchema(ctx); err == nil || !strings.Contains(err.Error(), "no submissions table") || !strings.Contains(err.Error(), "dbmate up") {
+		t.Errorf("empty database: want a missing-table error naming dbmate up, got %v", err)
+	}
+
+	stale := newRepo(t, `ALTER TABLE submissions DROP COLUMN employer_contact_phone_ext`)
+	if err := stale.EnsureSchema(ctx); err == nil || !strings.Contains(err.Error(), "employer_contact_phone_ext") {
+		t.Errorf("stale database: want a missing-column error, got %v", err)
+	}
+	if err := newRepo(t).EnsureSchema(ctx); err != nil {
+		t.Errorf("migrated database: %v", err)
+	}
+}
+
+// TestSubmission_Resubmission verifies the RCA resubmission fields survive
+// a create, an update and a reload.
+func TestSubmission_Resubmission(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	s := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER", ResubIndicator: "1", ResubWFID: "ABC123"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}
+	if err := repo.CreateSubmission(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetSubmission(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Submitter.ResubIndicator != "1" || got.Submitter.ResubWFID != "ABC123" {
+		t.Errorf("created: got %q/%q, want 1/ABC123", got.Submitter.ResubIndicator, got.Submitter.ResubWFID)
+	}
+
+	got.Submitter.ResubIndicator, got.Submitter.ResubWFID = "0", ""
+	if err := repo.UpdateSubmission(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.GetSubmission(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got.Submitter.ResubIndicator != "0" || got.Submitter.ResubWFID != "" {
+		t.Errorf("updated: got %q/%q, want 0/blank", got.Submitter.ResubIndicator, got.Submitter.ResubWFID)
+	}
+}
+
+// TestMarkSubmitted verifies a new submission is a draft and MarkSubmitted
+// stamps it with the current time.
+func TestMarkSubmitted(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	s := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}
+	if err := repo.CreateSubmission(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetSubmission(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SubmittedAt != nil {
+		t.Fatalf("new submission: SubmittedAt = %v, want nil", got.SubmittedAt)
+	}
+
+	before := time.Now().Add(-time.Second)
+	if err := repo.MarkSubmitted(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err = repo.GetSubmission(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SubmittedAt == nil {
+		t.Fatal("SubmittedAt not set after MarkSubmitted")
+	}
+	if at := *got.SubmittedAt; at.Before(before) || at.After(time.Now().Add(time.Second)) {
+		t.Errorf("SubmittedAt = %v, want about now", at)
+	}
+}
+
+// TestAddEmployees_Rollback verifies a batch that fails on its third insert
+// leaves no employees behind.
+func TestAddEmployees_Rollback(t *testing.T) {
+	repo := newRepo(t, `CREATE TRIGGER fail_third BEFORE INSERT ON employees
+		WHEN NEW.ssn = '333333333' BEGIN SELECT RAISE(ABORT, 'forced failure'); END`)
+	ctx := context.Background()
+	s := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}
+	if err := repo.CreateSubmission(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	batch := []domain.EmployeeRecord{{SSN: "111111111"}, {SSN: "222222222"}, {SSN: "333333333"}, {SSN: "444444444"}}
+	if err := repo.AddEmployees(ctx, s.ID, batch); err == nil {
+		t.Fatal("AddEmployees: want the forced failure, got nil")
+	}
+	got, err := repo.GetSubmission(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Employees) != 0 {
+		t.Errorf("after rollback: want 0 employees, got %d", len(got.Employees))
+	}
+	if batch[0].ID != 0 {
+		t.Errorf("after rollback: batch[0].ID = %d, want 0", batch[0].ID)
+	}
+
+	if err := repo.AddEmployees(ctx, s.ID, batch[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.GetSubmission(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Employees) != 2 || batch[0].ID == 0 || batch[1].ID == 0 {
+		t.Errorf("committed batch: got %d employees, IDs %d/%d", len(got.Employees), batch[0].ID, batch[1].ID)
+	}
+}
+
+// TestDeleteEmployeesBySubmission verifies only the target submission's
+// employees are removed.
+func TestDeleteEmployeesBySubmission(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	var ids []int64
+	for _, ein := range []string{"123456789", "987654321"} {
+		s := &domain.Submission{
+			Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+			Employer:  domain.EmployerRecord{EIN: ein, Name: "ACME CORP", TaxYear: "2024"},
+		}
+		if err := repo.CreateSubmission(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddEmployees(ctx, s.ID, []domain.EmployeeRecord{{SSN: "111111111"}, {SSN: "222222222"}}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if err := repo.DeleteEmployeesBySubmission(ctx, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{0, 2} {
+		got, err := repo.GetSubmission(ctx, ids[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Employees) != want {
+			t.Errorf("submission %d: want %d employees, got %d", ids[i], want, len(got.Employees))
+		}
+	}
+}
+
+// TestSearchSubmissions verifies partial, case-insensitive employer-name
+// matches, EIN matches with or without the dash, and that an empty query
+// lists everything.
+func TestSearchSubmissions(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	for _, e := range []domain.EmployerRecord{
+		{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+		{EIN: "987654321", Name: "GLOBEX 100% INC", TaxYear: "2024"},
+		{EIN: "555000111", Name: "ACME WIDGETS", TaxYear: "2023"},
+	} {
+		if err := repo.CreateSubmission(ctx, &domain.Submission{Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"}, Employer: e}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(query string) []string {
+		t.Helper()
+		list, err := repo.SearchSubmissions(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, s := range list {
+			out = append(out, s.Employer.Name)
+		}
+		slices.Sort(out)
+		return out
+	}
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"acme", []string{"ACME CORP", "ACME WIDGETS"}},
+		{"widg", []string{"ACME WIDGETS"}},
+		{"12-3456", []string{"ACME CORP"}},
+		{"100%", []string{"GLOBEX 100% INC"}},
+		{"%", []string{"GLOBEX 100% INC"}},
+		{"nobody", nil},
+		{"", []string{"ACME CORP", "ACME WIDGETS", "GLOBEX 100% INC"}},
+	} {
+		if got := names(tc.query); !slices.Equal(got, tc.want) {
+			t.Errorf("SearchSubmissions(%q): want %v, got %v", tc.query, tc.want, got)
+		}
+	}
+}
+
+// TestListSubmissionsPage verifies the second 25-record page of 60 and the
+// total count, newest first.
+func TestListSubmissionsPage(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	for i := 1; i <= 60; i++ {
+		s := &domain.Submission{
+			Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+			Employer:  domain.EmployerRecord{EIN: fmt.Sprintf("%09d", i), Name: fmt.Sprintf("EMPLOYER %02d", i), TaxYear: "2024"},
+		}
+		if err := repo.CreateSubmission(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, total, err := repo.ListSubmissionsPage(ctx, "", 25, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 60 {
+		t.Errorf("total: want 60, got %d", total)
+	}
+	if len(page) != 25 {
+		t.Fatalf("page 2: want 25 submissions, got %d", len(page))
+	}
+	if first, last := page[0].Employer.Name, page[24].Employer.Name; first != "EMPLOYER 35" || last != "EMPLOYER 11" {
+		t.Errorf("page 2: want EMPLOYER 35 … EMPLOYER 11, got %s … %s", first, last)
+	}
+
+	page, _, err = repo.ListSubmissionsPage(ctx, "", 25, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 10 {
+		t.Errorf("page 3: want 10 submissions, got %d", len(page))
+	}
+	if _, total, _ = repo.ListSubmissionsPage(ctx, "EMPLOYER 0", 25, 0); total != 9 {
+		t.Errorf("filtered total: want 9, got %d", total)
+	}
+}
+
+// TestListSubmissionsByEIN verifies only the employer's other submissions
+// are listed, newest first, without their employees.
+func TestListSubmissionsByEIN(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	var ids []int64
+	for _, ein := range []string{"123456789", "987654321", "123456789", "123456789"} {
+		s := &domain.Submission{
+			Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+			Employer:  domain.EmployerRecord{EIN: ein, Name: "ACME CORP", TaxYear: "2024"},
+		}
+		if err := repo.CreateSubmission(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddEmployees(ctx, s.ID, []domain.EmployeeRecord{{SSN: "111111111"}}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, s.ID)
+	}
+	list, err := repo.ListSubmissionsByEIN(ctx, "123456789", ids[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for _, s := range list {
+		got = append(got, s.ID)
+		if len(s.Employees) != 0 {
+			t.Errorf("submission %d: employees loaded", s.ID)
+		}
+	}
+	if want := []int64{ids[3], ids[0]}; !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// TestListSubmissionsPage_Employees verifies each listed submission gets
+// its own employees, and only the listed page's are loaded.
+func TestListSubmissionsPage_Employees(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		s := &domain.Submission{
+			Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+			Employer:  domain.EmployerRecord{EIN: fmt.Sprintf("%09d", i), Name: fmt.Sprintf("EMPLOYER %d", i), TaxYear: "2024"},
+		}
+		if err := repo.CreateSubmission(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+		batch := make([]domain.EmployeeRecord, i)
+		for j := range batch {
+			batch[j].SSN = fmt.Sprintf("%d%08d", i, j+1)
+		}
+		if err := repo.AddEmployees(ctx, s.ID, batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, _, err := repo.ListSubmissionsPage(ctx, "", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("want 2 submissions, got %d", len(page))
+	}
+	for _, s := range page {
+		want := map[string]int{"EMPLOYER 3": 3, "EMPLOYER 2": 2}[s.Employer.Name]
+		if len(s.Employees) != want {
+			t.Errorf("%s: want %d employees, got %d", s.Employer.Name, want, len(s.Employees))
+		}
+		for _, e := range s.Employees {
+			if e.SubmissionID != s.ID {
+				t.Errorf("%s: employee %d belongs to submission %d", s.Employer.Name, e.ID, e.SubmissionID)
+			}
+		}
+	}
+}
+
+// TestUpsertEmployees_Rollback verifies a failed batch leaves both the
+// updated and the inserted employees as they were.
+func TestUpsertEmployees_Rollback(t *testing.T) {
+	repo := newRepo(t, `CREATE TRIGGER fail_third BEFORE INSERT ON employees
+		WHEN NEW.ssn = '333333333' BEGIN SELECT RAISE(ABORT, 'forced failure'); END`)
+	ctx := context.Background()
+	s := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}
+	if err := repo.CreateSubmission(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	stored := []domain.EmployeeRecord{{SSN: "111111111", LastName: "SMITH"}}
+	if err := repo.AddEmployees(ctx, s.ID, stored); err != nil {
+		t.Fatal(err)
+	}
+	update := stored[0]
+	update.LastName = "JONES"
+	batch := []domain.EmployeeRecord{update, {SSN: "222222222"}, {SSN: "333333333"}}
+	if err := repo.UpsertEmployees(ctx, s.ID, batch); err == nil {
+		t.Fatal("UpsertEmployees: want the forced failure, got nil")
+	}
+	got, err := repo.GetSubmission(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Employees) != 1 || got.Employees[0].LastName != "SMITH" {
+		t.Errorf("after rollback: want only SMITH, got %+v", got.Employees)
+	}
+
+	if err := repo.UpsertEmployees(ctx, s.ID, batch[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.GetSubmission(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Employees) != 2 || got.Employees[0].LastName != "JONES" || batch[1].ID == 0 {
+		t.Errorf("committed batch: got %+v", got.Employees)
+	}
+}
+
+// TestGetSubmission_NotFound checks that a missing ID wraps
+// ports.ErrNotFound so callers can tell it from a database failure.
+func TestGetSubmission_NotFound(t *testing.T) {
+	repo := newRepo(t)
+	if _, err := repo.GetSubmission(context.Background(), 42); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("want ports.ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+// Severity ranks an audit finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // SSA would reject the file
+	SeverityWarning Severity = "warning" // likely a data-entry mistake; the file is still accepted
+	SeverityInfo    Severity = "info"    // no action needed
+)
+
+// AuditFinding is one item on a pre-submission audit checklist.
+type AuditFinding struct {
+	Severity Severity
+	Code     string // the ValidationError or Warning code, or an audit-only code
+	Record   string // record type the problem lands in: RCA, RCE, RCW, RCO, RCS or RCT
+	Field    string
+	Employee int // index into Submission.Employees; -1 for submission-level findings
+	Message  string
+}
+
+// AuditReport lists everything an SSA pre-submission check such as
+// AccuWage would flag in a submission, errors first.
+type AuditReport struct {
+	Findings []AuditFinding
+}
+
+// Count returns the number of findings with severity sev.
+func (r *AuditReport) Count(sev Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// Clean reports whether the audit found no errors.
+func (r *AuditReport) Clean() bool {
+	return r.Count(SeverityError) == 0
+}
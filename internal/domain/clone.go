@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Clone returns a deep copy of s to start a new submission from: the
+// submitter, employer and every employee are copied, with IDs, timestamps,
+// filing status and the download count reset so the copy is a fresh draft.
+// Box 13 flags are copied by value, so the copy shares no pointers with s.
+func (s *Submission) Clone() *Submission {
+	c := &Submission{
+		Submitter: s.Submitter,
+		Employer:  s.Employer,
+		Employees: cloneEmployees(s.Employees),
+		Notes:     s.Notes,
+	}
+	for _, b := range s.AdditionalEmployers {
+		c.AdditionalEmployers = append(c.AdditionalEmployers, EmployerBlock{
+			Employer:  b.Employer,
+			Employees: cloneEmployees(b.Employees),
+		})
+	}
+	return c
+}
+
+func cloneEmployees(src []EmployeeRecord) []EmployeeRecord {
+	if src == nil {
+		return nil
+	}
+	out := make([]EmployeeRecord, len(src))
+	for i, e := range src {
+		e.ID, e.SubmissionID = 0, 0
+		e.CreatedAt, e.UpdatedAt = time.Time{}, time.Time{}
+		e.Box13 = e.Box13.clone()
+		out[i] = e
+	}
+	return out
+}
+
+// clone copies each set flag into a new bool.
+func (b Box13Flags) clone() Box13Flags {
+	for _, p := range []**bool{
+		&b.OrigStatutoryEmployee, &b.CorrectStatutoryEmployee,
+		&b.OrigRetirementPlan, &b.CorrectRetirementPlan,
+		&b.OrigThirdPartySickPay, &b.CorrectThirdPartySickPay,
+	} {
+		if *p != nil {
+			v := **p
+			*p = &v
+		}
+	}
+	return b
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+func TestClone_DeepCopiesBox13(t *testing.T) {
+	yes, no := true, false
+	now := time.Now()
+	s := &domain.Submission{
+		ID:          7,
+		Employer:    domain.EmployerRecord{EIN: "123456789"},
+		CreatedAt:   now,
+		SubmittedAt: &now,
+		Employees: []domain.EmployeeRecord{{
+			ID: 3, SubmissionID: 7, SSN: "111111111", CreatedAt: now,
+			Box13: domain.Box13Flags{OrigRetirementPlan: &no, CorrectRetirementPlan: &yes},
+		}},
+	}
+
+	c := s.Clone()
+	if c.ID != 0 || !c.CreatedAt.IsZero() || c.SubmittedAt != nil {
+		t.Errorf("clone: want zero ID/CreatedAt and no SubmittedAt, got %d %v %v", c.ID, c.CreatedAt, c.SubmittedAt)
+	}
+	e := &c.Employees[0]
+	if e.ID != 0 || e.SubmissionID != 0 || !e.CreatedAt.IsZero() {
+		t.Errorf("employee: want zero IDs and CreatedAt, got %d/%d %v", e.ID, e.SubmissionID, e.CreatedAt)
+	}
+	if e.SSN != "111111111" || c.Employer.EIN != "123456789" {
+		t.Errorf("clone lost data: SSN %q EIN %q", e.SSN, c.Employer.EIN)
+	}
+
+	*e.Box13.CorrectRetirementPlan = false
+	*e.Box13.OrigRetirementPlan = true
+	c.Employees[0].SSN = "222222222"
+	orig := s.Employees[0]
+	if !*orig.Box13.CorrectRetirementPlan || *orig.Box13.OrigRetirementPlan {
+		t.Error("mutating the clone's Box 13 flags changed the original's")
+	}
+	if orig.SSN != "111111111" {
+		t.Error("mutating the clone's employees changed the original's")
+	}
+	if e.Box13.OrigStatutoryEmployee != nil {
+		t.Error("unset Box 13 flag became set in the clone")
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+// RecordDump is one record of a generated EFW2C file split into its layout
+// fields, for finding the field SSA rejected without counting columns.
+type RecordDump struct {
+	Index  int    // 0-based position of the record in the file
+	ID     string // record identifier: RCA, RCE, RCW, ...
+	Fields []FieldDump
+}
+
+// FieldDump is one field of a RecordDump. Start and End are the 1-based,
+// inclusive positions from the spec; Value is the raw text there, padding
+// included.
+type FieldDump struct {
+	Name       string
+	Start, End int
+	Type       string
+	Value      string
+}
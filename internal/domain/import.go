@@ -0,0 +1,47 @@
+package domain
+
+// ImportRowError is a problem with one row of an employee import file.
+// Line is the 1-based line the row starts on.
+type ImportRowError struct {
+	Line    int
+	Message string
+}
+
+// ImportOutcome is what an import did with one row.
+type ImportOutcome string
+
+const (
+	ImportInserted ImportOutcome = "inserted" // new SSN, employee added
+	ImportUpdated  ImportOutcome = "updated"  // SSN already present, employee replaced
+	ImportSkipped  ImportOutcome = "skipped"  // SSN already present with identical data
+)
+
+// ImportRow records the outcome for one imported row.
+type ImportRow struct {
+	Line    int
+	SSN     string
+	Outcome ImportOutcome
+}
+
+// ImportResult summarises a bulk employee import: what happened to each
+// accepted row and which rows were rejected.
+type ImportResult struct {
+	Rows   []ImportRow
+	Errors []ImportRowError
+}
+
+// Count returns the number of rows with outcome o.
+func (r ImportResult) Count(o ImportOutcome) int {
+	n := 0
+	for _, row := range r.Rows {
+		if row.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// Imported is the number of rows that added or changed an employee.
+func (r ImportResult) Imported() int {
+	return r.Count(ImportInserted) + r.Count(ImportUpdated)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 const DefaultTaxYear = "2024"
 
@@ -16,6 +19,22 @@ type SubmitterInfo struct {
 	// BSOUID is the 8-char BSO User ID from SSA registration (required).
 	// Obtain at: https://www.ssa.gov/employer/
 	BSOUID string
+
+	// CompanyName is the submitter's company name written to the RCA
+	// record. A service bureau or accounting firm submits on behalf of the
+	// employer, so it differs from EmployerRecord.Name; leave blank for the
+	// self-prepared case to use the employer's name.
+	CompanyName string
+
+	// Submitter mailing address (RCA positions 89-165). Leave all blank for
+	// the self-prepared case to use the employer's address.
+	AddressLine1 string
+	AddressLine2 string
+	City         string
+	State        string
+	ZIP          string
+	ZIPExtension string
+
 	// ContactName is the human contact at the submitter.
 	// Allowed chars: A-Z, 0-9, space, hyphen, period, apostrophe.
 	ContactName string
@@ -24,6 +43,12 @@ type SubmitterInfo struct {
 	// Example: "8005551234"
 	ContactPhone string
 
+	// PhoneExtension is the contact's extension, up to 5 digits.
+	PhoneExtension string
+
+	// ContactFax is the contact's fax number, 10 digits.
+	ContactFax string
+
 	// ContactEmail must be a valid email address.
 	ContactEmail string
 
@@ -36,6 +61,20 @@ type SubmitterInfo struct {
 
 	// ResubWFID is the original Wage File ID (resubmissions only).
 	ResubWFID string
+
+	// SoftwareCode: "98"=In-House Program, "99"=Off-the-Shelf Software;
+	// blank leaves the RCA field empty.
+	SoftwareCode string
+
+	// SoftwareVendorCode is the 4-digit NACTP vendor code, required (and
+	// written) only when SoftwareCode is "99".
+	SoftwareVendorCode string
+}
+
+// HasAddress reports whether any submitter address field is populated.
+func (s SubmitterInfo) HasAddress() bool {
+	return s.AddressLine1 != "" || s.AddressLine2 != "" || s.City != "" ||
+		s.State != "" || s.ZIP != "" || s.ZIPExtension != ""
 }
 
 type EmployerRecord struct {
@@ -51,12 +90,28 @@ type EmployerRecord struct {
 	TaxYear             string // e.g. "2024" — written into RCE record
 	AgentIndicator      string
 	AgentEIN            string
-	TerminatingBusiness bool
+	TerminatingBusiness bool   // stored only: unlike the EFW2 RE, the EFW2C RCE (Pub 42-014 §5.6) has no such position
 	EmploymentCode      string // A/H/M/Q/R/X/F — defaults to "R"
 	KindOfEmployer      string // F/S/T/Y/N
 	ContactName         string
 	ContactPhone        string
 	ContactEmail        string
+
+	// ContactPhoneExtension is the employer contact's extension, up to 5
+	// digits.
+	ContactPhoneExtension string
+
+	// RCE third-party sick pay indicators: "1" yes, "0" no, blank when
+	// not being corrected.
+	OriginalThirdPartySick string
+	CorrectThirdPartySick  string
+
+	// Foreign address: CountryCode is the SSA country code (blank or "US"
+	// for a domestic address); the province and postal code replace State
+	// and ZIP when it is foreign.
+	CountryCode          string
+	ForeignStateProvince string
+	ForeignPostalCode    string
 }
 
 // MonetaryAmounts holds all monetary correction fields for an employee.
@@ -84,6 +139,34 @@ type MonetaryAmounts struct {
 	OriginalAllocatedTips int64
 	CorrectAllocatedTips  int64
 
+	// Box 12 Codes A & B — Uncollected social security/RRTA and Medicare tax
+	// on tips (RCO record, positions 35-56); one combined amount per spec.
+	OriginalUncollectedEETax int64
+	CorrectUncollectedEETax  int64
+
+	// Box 12 codes written to the RCO record
+	// Code R — Employer contributions to an Archer MSA (RCO positions 57-78)
+	OriginalCodeR_MSA int64
+	CorrectCodeR_MSA  int64
+	// Code S — Employee salary reduction contributions under a 408(p) SIMPLE (RCO positions 79-100)
+	OriginalCodeS_SIMPLE int64
+	CorrectCodeS_SIMPLE  int64
+	// Code T — Adoption benefits (RCO positions 101-122)
+	OriginalCodeT_Adoption int64
+	CorrectCodeT_Adoption  int64
+	// Code M — Uncollected social security or RRTA tax on group-term life over $50,000 (RCO positions 123-144)
+	OriginalCodeM_UncollSS int64
+	CorrectCodeM_UncollSS  int64
+	// Code N — Uncollected Medicare tax on group-term life over $50,000 (RCO positions 145-166)
+	OriginalCodeN_UncollMed int64
+	CorrectCodeN_UncollMed  int64
+	// Code Z — Income under a 409A nonqualified deferred compensation plan that fails section 409A (RCO positions 167-188)
+	OriginalCodeZ_409A int64
+	CorrectCodeZ_409A  int64
+	// Code II — Medicaid waiver payments excluded from income (RCO positions 277-298, TY2024+)
+	OriginalMedicaidWaiver int64
+	CorrectMedicaidWaiver  int64
+
 	// Box 10 — Dependent Care Benefits (RCW, positions 420-441)
 	OriginalDependentCare int64
 	CorrectDependentCare  int64
@@ -118,6 +201,27 @@ type MonetaryAmounts struct {
 	// Code DD — Employer-sponsored health coverage cost (positions 816-837)
 	OriginalCodeDD_EmpHealth int64
 	CorrectCodeDD_EmpHealth  int64
+	// Code C — Taxable cost of group-term life insurance over $50,000 (positions 706-727)
+	OriginalCodeC_GroupTermLife int64
+	CorrectCodeC_GroupTermLife  int64
+	// Code F — Elective deferrals under a 408(k)(6) salary reduction SEP (positions 486-507)
+	OriginalCodeF_SARSEP int64
+	CorrectCodeF_SARSEP  int64
+	// Code H — Elective deferrals to a 501(c)(18)(D) tax-exempt plan (positions 530-551)
+	OriginalCodeH_501c18D int64
+	CorrectCodeH_501c18D  int64
+	// Code Q — Nontaxable combat pay (positions 662-683)
+	OriginalCodeQ_CombatPay int64
+	CorrectCodeQ_CombatPay  int64
+	// Code V — Income from exercise of nonstatutory stock options (positions 728-749)
+	OriginalCodeV_NSO int64
+	CorrectCodeV_NSO  int64
+	// Code Y — Deferrals under a 409A nonqualified deferred compensation plan (positions 750-771)
+	OriginalCodeY_409A int64
+	CorrectCodeY_409A  int64
+	// Code FF — Permitted benefits under a QSEHRA (positions 838-859)
+	OriginalCodeFF_QSEHRA int64
+	CorrectCodeFF_QSEHRA  int64
 
 	// Box 16 — State wages, tips, etc. (RCS record)
 	OriginalStateWages int64
@@ -173,6 +277,10 @@ type EmployeeRecord struct {
 	State        string
 	ZIP          string
 	ZIPExtension string
+	// Foreign address; see EmployerRecord.CountryCode.
+	CountryCode          string
+	ForeignStateProvince string
+	ForeignPostalCode    string
 
 	Amounts MonetaryAmounts
 
@@ -192,12 +300,44 @@ type EmployeeRecord struct {
 	UpdatedAt time.Time
 }
 
+// EmployerBlock is one employer and its employee corrections — the RCE,
+// RCW … and RCT records of a multi-employer file.
+type EmployerBlock struct {
+	Employer  EmployerRecord
+	Employees []EmployeeRecord
+}
+
 type Submission struct {
-	ID          int64
-	Submitter   SubmitterInfo
-	Employer    EmployerRecord
-	Employees   []EmployeeRecord
-	CreatedAt   time.Time
-	SubmittedAt *time.Time
-	Notes       string
+	ID        int64
+	Submitter SubmitterInfo
+	Employer  EmployerRecord
+	Employees []EmployeeRecord
+	// AdditionalEmployers are further employers filed under the same
+	// submitter, each written as its own RCE … RCT block after Employer's.
+	// Empty for the usual single-employer file; not persisted.
+	AdditionalEmployers []EmployerBlock
+	CreatedAt           time.Time
+	SubmittedAt         *time.Time
+	Notes               string
+
+	// GenerateCount is how many times the EFW2C file has been generated for
+	// download. Maintained by the repository; never written by UpdateSubmission.
+	GenerateCount int
+}
+
+// EmployerBlocks returns every employer in s in file order: Employer with
+// Employees first, then AdditionalEmployers. The blocks share s's slices.
+func (s *Submission) EmployerBlocks() []EmployerBlock {
+	return append([]EmployerBlock{{Employer: s.Employer, Employees: s.Employees}}, s.AdditionalEmployers...)
+}
+
+// HasForeignAddress reports whether the employer's address is outside the US.
+func (e EmployerRecord) HasForeignAddress() bool { return isForeign(e.CountryCode) }
+
+// HasForeignAddress reports whether the employee's address is outside the US.
+func (e EmployeeRecord) HasForeignAddress() bool { return isForeign(e.CountryCode) }
+
+func isForeign(countryCode string) bool {
+	c := strings.TrimSpace(countryCode)
+	return c != "" && !strings.EqualFold(c, "US")
 }
@@ -0,0 +1,27 @@
+package domain
+
+// DefaultPageSize is the number of submissions listed per index page.
+const DefaultPageSize = 25
+
+// SubmissionPage is one page of the submission list.
+type SubmissionPage struct {
+	Submissions []Submission
+	Query       string // search filter; empty for all submissions
+	Page        int    // 1-based
+	PageSize    int
+	Total       int // submissions matching Query across every page
+}
+
+// Pages returns the number of pages, at least 1.
+func (p SubmissionPage) Pages() int {
+	if p.PageSize <= 0 || p.Total <= p.PageSize {
+		return 1
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+// HasPrev reports whether there is a page before p.
+func (p SubmissionPage) HasPrev() bool { return p.Page > 1 }
+
+// HasNext reports whether there is a page after p.
+func (p SubmissionPage) HasNext() bool { return p.Page < p.Pages() }
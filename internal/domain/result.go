@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// GenerateResult describes an EFW2C file that was written.
+type GenerateResult struct {
+	RecordCount int // records of every type, RCA through RCF
+	ByteCount   int // bytes written, including any line endings
+	RCWCount    int // employee records, as counted in the RCF
+	// Truncations lists the text values cut to fit their fields (only
+	// possible under a truncating trim policy), as field_truncated warnings.
+	Truncations []Warning
+	// Warnings are the reconciliation advisories for the submission, as
+	// from EFW2CGenerator.Check.
+	Warnings []Warning
+}
+
+// Manifest is an archival sidecar for a generated EFW2C file: enough to
+// show later that a stored file is the one that was generated, and what it
+// holds.
+type Manifest struct {
+	SHA256    string         // hex digest of every byte written
+	ByteCount int            // bytes written, including any line endings
+	Records   map[string]int // record count by identifier, e.g. "RCW"
+	EIN       string         // employer EIN, as stored
+	TaxYear   string
+	// GeneratedAt is when the file was written, in UTC.
+	GeneratedAt time.Time
+}
@@ -0,0 +1,77 @@
+package domain
+
+// RollForward copies every corrected amount in prior into the matching
+// original amount in dst. When a W-2c amends an earlier W-2c, the figures
+// the earlier one corrected to are what SSA now has on file, so they become
+// the new originals. dst's corrected amounts are left alone.
+func RollForward(dst *MonetaryAmounts, prior *MonetaryAmounts) {
+	dst.OriginalWagesTipsOther = prior.CorrectWagesTipsOther
+	dst.OriginalFederalIncomeTax = prior.CorrectFederalIncomeTax
+	dst.OriginalSocialSecurityWages = prior.CorrectSocialSecurityWages
+	dst.OriginalSocialSecurityTax = prior.CorrectSocialSecurityTax
+	dst.OriginalMedicareWages = prior.CorrectMedicareWages
+	dst.OriginalMedicareTax = prior.CorrectMedicareTax
+	dst.OriginalSocialSecurityTips = prior.CorrectSocialSecurityTips
+	dst.OriginalAllocatedTips = prior.CorrectAllocatedTips
+	dst.OriginalUncollectedEETax = prior.CorrectUncollectedEETax
+	dst.OriginalCodeR_MSA = prior.CorrectCodeR_MSA
+	dst.OriginalCodeS_SIMPLE = prior.CorrectCodeS_SIMPLE
+	dst.OriginalCodeT_Adoption = prior.CorrectCodeT_Adoption
+	dst.OriginalCodeM_UncollSS = prior.CorrectCodeM_UncollSS
+	dst.OriginalCodeN_UncollMed = prior.CorrectCodeN_UncollMed
+	dst.OriginalCodeZ_409A = prior.CorrectCodeZ_409A
+	dst.OriginalMedicaidWaiver = prior.CorrectMedicaidWaiver
+	dst.OriginalDependentCare = prior.CorrectDependentCare
+	dst.OriginalNonqualPlan457 = prior.CorrectNonqualPlan457
+	dst.OriginalNonqualNotSection457 = prior.CorrectNonqualNotSection457
+	dst.OriginalCode401k = prior.CorrectCode401k
+	dst.OriginalCode403b = prior.CorrectCode403b
+	dst.OriginalCode457bGovt = prior.CorrectCode457bGovt
+	dst.OriginalCodeW_HSA = prior.CorrectCodeW_HSA
+	dst.OriginalCodeAA_Roth401k = prior.CorrectCodeAA_Roth401k
+	dst.OriginalCodeBB_Roth403b = prior.CorrectCodeBB_Roth403b
+	dst.OriginalCodeDD_EmpHealth = prior.CorrectCodeDD_EmpHealth
+	dst.OriginalCodeC_GroupTermLife = prior.CorrectCodeC_GroupTermLife
+	dst.OriginalCodeF_SARSEP = prior.CorrectCodeF_SARSEP
+	dst.OriginalCodeH_501c18D = prior.CorrectCodeH_501c18D
+	dst.OriginalCodeQ_CombatPay = prior.CorrectCodeQ_CombatPay
+	dst.OriginalCodeV_NSO = prior.CorrectCodeV_NSO
+	dst.OriginalCodeY_409A = prior.CorrectCodeY_409A
+	dst.OriginalCodeFF_QSEHRA = prior.CorrectCodeFF_QSEHRA
+	dst.OriginalStateWages = prior.CorrectStateWages
+	dst.OriginalStateIncomeTax = prior.CorrectStateIncomeTax
+	dst.OriginalLocalWages = prior.CorrectLocalWages
+	dst.OriginalLocalIncomeTax = prior.CorrectLocalIncomeTax
+}
+
+// SeedOriginalsFrom rolls prior's corrected amounts forward into the
+// original amounts of each employee in s with the same SSN (compared on
+// digits only). Employees with no match in prior are left untouched. It
+// returns the indices into s.Employees that were seeded.
+func (s *Submission) SeedOriginalsFrom(prior *Submission) []int {
+	bySSN := make(map[string]*EmployeeRecord, len(prior.Employees))
+	for i := range prior.Employees {
+		bySSN[ssnDigits(prior.Employees[i].SSN)] = &prior.Employees[i]
+	}
+	var seeded []int
+	for i := range s.Employees {
+		p, ok := bySSN[ssnDigits(s.Employees[i].SSN)]
+		if !ok {
+			continue
+		}
+		RollForward(&s.Employees[i].Amounts, &p.Amounts)
+		seeded = append(seeded, i)
+	}
+	return seeded
+}
+
+// ssnDigits drops everything but digits so "123-45-6789" matches "123456789".
+func ssnDigits(ssn string) string {
+	b := make([]byte, 0, 9)
+	for i := 0; i < len(ssn); i++ {
+		if ssn[i] >= '0' && ssn[i] <= '9' {
+			b = append(b, ssn[i])
+		}
+	}
+	return string(b)
+}
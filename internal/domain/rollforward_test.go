@@ -0,0 +1,50 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+func TestSeedOriginalsFrom_MatchesBySSN(t *testing.T) {
+	prior := &domain.Submission{
+		Employees: []domain.EmployeeRecord{
+			{
+				SSN: "111-11-1111",
+				Amounts: domain.MonetaryAmounts{
+					OriginalWagesTipsOther: 5000000,
+					CorrectWagesTipsOther:  5100000,
+					CorrectStateWages:      5100000,
+				},
+			},
+			{SSN: "999999999", Amounts: domain.MonetaryAmounts{CorrectWagesTipsOther: 100}},
+		},
+	}
+	s := &domain.Submission{
+		Employees: []domain.EmployeeRecord{
+			{SSN: "111111111", Amounts: domain.MonetaryAmounts{CorrectWagesTipsOther: 5200000}},
+			{SSN: "222222222", Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 700, CorrectWagesTipsOther: 800}},
+		},
+	}
+
+	seeded := s.SeedOriginalsFrom(prior)
+	if len(seeded) != 1 || seeded[0] != 0 {
+		t.Fatalf("seeded: want [0], got %v", seeded)
+	}
+
+	got := s.Employees[0].Amounts
+	if got.OriginalWagesTipsOther != 5100000 {
+		t.Errorf("matched OriginalWagesTipsOther: want prior corrected 5100000, got %d", got.OriginalWagesTipsOther)
+	}
+	if got.OriginalStateWages != 5100000 {
+		t.Errorf("matched OriginalStateWages: want 5100000, got %d", got.OriginalStateWages)
+	}
+	if got.CorrectWagesTipsOther != 5200000 {
+		t.Errorf("matched CorrectWagesTipsOther: want unchanged 5200000, got %d", got.CorrectWagesTipsOther)
+	}
+
+	un := s.Employees[1].Amounts
+	if un.OriginalWagesTipsOther != 700 || un.CorrectWagesTipsOther != 800 {
+		t.Errorf("unmatched employee changed: got orig %d corr %d", un.OriginalWagesTipsOther, un.CorrectWagesTipsOther)
+	}
+}
@@ -0,0 +1,255 @@
+package domain
+
+// BoxDeltas holds the aggregate (corrected − original) change, in cents, for
+// each tracked W-2c box across every employee in a submission.
+type BoxDeltas struct {
+	WagesTipsOther      int64 // Box 1
+	FederalIncomeTax    int64 // Box 2
+	SocialSecurityWages int64 // Box 3
+	SocialSecurityTax   int64 // Box 4
+	MedicareWages       int64 // Box 5
+	MedicareTax         int64 // Box 6
+	SocialSecurityTips  int64 // Box 7
+	AllocatedTips       int64 // Box 8
+	StateWages          int64 // Box 16
+	StateIncomeTax      int64 // Box 17
+	LocalWages          int64 // Box 18
+	LocalIncomeTax      int64 // Box 19
+}
+
+// CorrectionSummary is a roll-up of a submission used by the list view,
+// the detail header, and the PDF summary page. It covers every employer
+// block, AdditionalEmployers included.
+type CorrectionSummary struct {
+	// Employees is the total number of employee records on the submission.
+	Employees int
+	// EmployeesWithChanges counts employees whose record actually corrects
+	// something (an amount, SSN, name, Box 13 flag, or state/locality value).
+	EmployeesWithChanges int
+
+	// Record counts as they will appear in the generated EFW2C file with
+	// default options.
+	RCWRecords int
+	RCORecords int
+	RCSRecords int
+	// RCURecords counts the employer blocks with an RCO: each such block
+	// gets one RCU totalling them.
+	RCURecords int
+	// TotalRecords includes RCA and RCF, and each block's RCE, RCT and
+	// RCU. It assumes the default layout: a generator option that drops
+	// records (efw2c.WithoutRCT) writes fewer, so GenerateResult's
+	// RecordCount is the exact figure for a written file.
+	TotalRecords int
+
+	Deltas BoxDeltas
+}
+
+// Summary computes the CorrectionSummary for s.
+func (s *Submission) Summary() CorrectionSummary {
+	var sum CorrectionSummary
+	blocks := s.EmployerBlocks()
+	for _, b := range blocks {
+		rco := sum.RCORecords
+		sum.Employees += len(b.Employees)
+		for i := range b.Employees {
+			e := &b.Employees[i]
+			a := &e.Amounts
+
+			if e.HasCorrection() {
+				sum.EmployeesWithChanges++
+			}
+			sum.RCWRecords++
+			if e.HasRCOData() {
+				sum.RCORecords++
+			}
+			if e.HasRCSData() {
+				sum.RCSRecords++
+			}
+
+			d := &sum.Deltas
+			d.WagesTipsOther += a.CorrectWagesTipsOther - a.OriginalWagesTipsOther
+			d.FederalIncomeTax += a.CorrectFederalIncomeTax - a.OriginalFederalIncomeTax
+			d.SocialSecurityWages += a.CorrectSocialSecurityWages - a.OriginalSocialSecurityWages
+			d.SocialSecurityTax += a.CorrectSocialSecurityTax - a.OriginalSocialSecurityTax
+			d.MedicareWages += a.CorrectMedicareWages - a.OriginalMedicareWages
+			d.MedicareTax += a.CorrectMedicareTax - a.OriginalMedicareTax
+			d.SocialSecurityTips += a.CorrectSocialSecurityTips - a.OriginalSocialSecurityTips
+			d.AllocatedTips += a.CorrectAllocatedTips - a.OriginalAllocatedTips
+			d.StateWages += a.CorrectStateWages - a.OriginalStateWages
+			d.StateIncomeTax += a.CorrectStateIncomeTax - a.OriginalStateIncomeTax
+			d.LocalWages += a.CorrectLocalWages - a.OriginalLocalWages
+			d.LocalIncomeTax += a.CorrectLocalIncomeTax - a.OriginalLocalIncomeTax
+		}
+		if sum.RCORecords > rco {
+			sum.RCURecords++
+		}
+	}
+	// RCA + RCF wrap the file, and an RCE + RCT each employer block.
+	sum.TotalRecords = 2 + 2*len(blocks) + sum.RCWRecords + sum.RCORecords + sum.RCSRecords + sum.RCURecords
+	return sum
+}
+
+// HasRCOData reports whether e carries any field written to the RCO
+// (Employee Optional) record: Box 8 allocated tips or one of the Box 12
+// codes the RCO carries (A/B, R, S, T, M, N, Z and, from TY2024, II).
+// Code II counts in every year; the generator rejects it before TY2024.
+func (e *EmployeeRecord) HasRCOData() bool {
+	a := &e.Amounts
+	for _, v := range []int64{
+		a.OriginalAllocatedTips, a.CorrectAllocatedTips,
+		a.OriginalUncollectedEETax, a.CorrectUncollectedEETax,
+		a.OriginalCodeR_MSA, a.CorrectCodeR_MSA,
+		a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE,
+		a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption,
+		a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS,
+		a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed,
+		a.OriginalCodeZ_409A, a.CorrectCodeZ_409A,
+		a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver,
+	} {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRCSData reports whether e carries any state or local data written to
+// the RCS (State Wage) record.
+func (e *EmployeeRecord) HasRCSData() bool {
+	return e.OriginalStateCode != "" || e.CorrectStateCode != "" ||
+		e.Amounts.OriginalStateWages != 0 || e.Amounts.CorrectStateWages != 0 ||
+		e.Amounts.OriginalStateIncomeTax != 0 || e.Amounts.CorrectStateIncomeTax != 0 ||
+		e.Amounts.OriginalLocalWages != 0 || e.Amounts.CorrectLocalWages != 0 ||
+		e.Amounts.OriginalLocalIncomeTax != 0 || e.Amounts.CorrectLocalIncomeTax != 0 ||
+		e.OriginalLocalityName != "" || e.CorrectLocalityName != ""
+}
+
+// HasCorrection reports whether any original/correct pair on e differs.
+// State and local amounts count on their own: an employee whose federal
+// boxes are unchanged but whose Box 16–19 values differ still needs an RCW
+// (federal amounts zeroed) followed by an RCS.
+func (e *EmployeeRecord) HasCorrection() bool {
+	if e.OriginalSSN != "" && e.OriginalSSN != e.SSN {
+		return true
+	}
+	if (e.OriginalFirstName != "" || e.OriginalLastName != "") &&
+		(e.OriginalFirstName != e.FirstName || e.OriginalMiddleName != e.MiddleName ||
+			e.OriginalLastName != e.LastName || e.OriginalSuffix != e.Suffix) {
+		return true
+	}
+
+	a := &e.Amounts
+	pairs := [][2]int64{
+		{a.OriginalWagesTipsOther, a.CorrectWagesTipsOther},
+		{a.OriginalFederalIncomeTax, a.CorrectFederalIncomeTax},
+		{a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages},
+		{a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax},
+		{a.OriginalMedicareWages, a.CorrectMedicareWages},
+		{a.OriginalMedicareTax, a.CorrectMedicareTax},
+		{a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips},
+		{a.OriginalAllocatedTips, a.CorrectAllocatedTips},
+		{a.OriginalUncollectedEETax, a.CorrectUncollectedEETax},
+		{a.OriginalCodeR_MSA, a.CorrectCodeR_MSA},
+		{a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE},
+		{a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption},
+		{a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS},
+		{a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed},
+		{a.OriginalCodeZ_409A, a.CorrectCodeZ_409A},
+		{a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver},
+		{a.OriginalDependentCare, a.CorrectDependentCare},
+		{a.OriginalNonqualPlan457, a.CorrectNonqualPlan457},
+		{a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457},
+		{a.OriginalCode401k, a.CorrectCode401k},
+		{a.OriginalCode403b, a.CorrectCode403b},
+		{a.OriginalCode457bGovt, a.CorrectCode457bGovt},
+		{a.OriginalCodeW_HSA, a.CorrectCodeW_HSA},
+		{a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k},
+		{a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b},
+		{a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth},
+		{a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife},
+		{a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP},
+		{a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D},
+		{a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay},
+		{a.OriginalCodeV_NSO, a.CorrectCodeV_NSO},
+		{a.OriginalCodeY_409A, a.CorrectCodeY_409A},
+		{a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA},
+		{a.OriginalStateWages, a.CorrectStateWages},
+		{a.OriginalStateIncomeTax, a.CorrectStateIncomeTax},
+		{a.OriginalLocalWages, a.CorrectLocalWages},
+		{a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax},
+	}
+	for _, p := range pairs {
+		if p[0] != p[1] {
+			return true
+		}
+	}
+
+	b := &e.Box13
+	flags := [][2]*bool{
+		{b.OrigStatutoryEmployee, b.CorrectStatutoryEmployee},
+		{b.OrigRetirementPlan, b.CorrectRetirementPlan},
+		{b.OrigThirdPartySickPay, b.CorrectThirdPartySickPay},
+	}
+	for _, f := range flags {
+		if (f[0] == nil) != (f[1] == nil) || (f[0] != nil && *f[0] != *f[1]) {
+			return true
+		}
+	}
+
+	// A lone state code/ID/locality only identifies where wages were
+	// reported; it is a correction only when both sides are present and differ.
+	return differs(e.OriginalStateCode, e.CorrectStateCode) ||
+		differs(e.OriginalStateIDNumber, e.CorrectStateIDNumber) ||
+		differs(e.OriginalLocalityName, e.CorrectLocalityName)
+}
+
+func differs(orig, corr string) bool {
+	return orig != "" && corr != "" && orig != corr
+}
+
+// BoxTotal is an original/corrected pair of summed amounts, in cents.
+type BoxTotal struct {
+	Original int64
+	Correct  int64
+}
+
+// Add adds one employee's original and corrected amounts.
+func (t *BoxTotal) Add(orig, corr int64) {
+	t.Original += orig
+	t.Correct += corr
+}
+
+// BoxTotals sums Boxes 1–7 across employees. The generator accumulates the
+// RCT totals with it, so these are the figures the file will carry.
+type BoxTotals struct {
+	WagesTipsOther      BoxTotal // Box 1
+	FederalIncomeTax    BoxTotal // Box 2
+	SocialSecurityWages BoxTotal // Box 3
+	SocialSecurityTax   BoxTotal // Box 4
+	MedicareWages       BoxTotal // Box 5
+	MedicareTax         BoxTotal // Box 6
+	SocialSecurityTips  BoxTotal // Box 7
+}
+
+// Add adds one employee's amounts.
+func (t *BoxTotals) Add(a *MonetaryAmounts) {
+	t.WagesTipsOther.Add(a.OriginalWagesTipsOther, a.CorrectWagesTipsOther)
+	t.FederalIncomeTax.Add(a.OriginalFederalIncomeTax, a.CorrectFederalIncomeTax)
+	t.SocialSecurityWages.Add(a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages)
+	t.SocialSecurityTax.Add(a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax)
+	t.MedicareWages.Add(a.OriginalMedicareWages, a.CorrectMedicareWages)
+	t.MedicareTax.Add(a.OriginalMedicareTax, a.CorrectMedicareTax)
+	t.SocialSecurityTips.Add(a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips)
+}
+
+// Totals sums Boxes 1–7 over the employees of every employer block, for
+// checking against a payroll register before generating.
+func (s *Submission) Totals() BoxTotals {
+	var t BoxTotals
+	for _, b := range s.EmployerBlocks() {
+		for i := range b.Employees {
+			t.Add(&b.Employees[i].Amounts)
+		}
+	}
+	return t
+}
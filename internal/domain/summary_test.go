@@ -0,0 +1,127 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+func TestSummary_ExcludesAllZeroEmployees(t *testing.T) {
+	s := &domain.Submission{
+		Employees: []domain.EmployeeRecord{
+			{SSN: "111111111", FirstName: "ZERO", LastName: "ONE"},
+			{
+				SSN: "222222222", FirstName: "JANE", LastName: "DOE",
+				Amounts: domain.MonetaryAmounts{
+					OriginalWagesTipsOther: 5000000,
+					CorrectWagesTipsOther:  5500000,
+				},
+			},
+			{SSN: "333333333", FirstName: "ZERO", LastName: "TWO"},
+			{
+				SSN: "444444444", FirstName: "JOHN", LastName: "ROE",
+				Amounts: domain.MonetaryAmounts{
+					OriginalWagesTipsOther: 4000000,
+					CorrectWagesTipsOther:  3900000,
+					OriginalAllocatedTips:  0,
+					CorrectAllocatedTips:   10000,
+				},
+				CorrectStateCode: "IL",
+			},
+		},
+	}
+
+	sum := s.Summary()
+	if sum.Employees != 4 {
+		t.Errorf("Employees: want 4, got %d", sum.Employees)
+	}
+	if sum.EmployeesWithChanges != 2 {
+		t.Errorf("EmployeesWithChanges: want 2 (all-zero employees excluded), got %d", sum.EmployeesWithChanges)
+	}
+	if sum.RCWRecords != 4 || sum.RCORecords != 1 || sum.RCSRecords != 1 {
+		t.Errorf("record counts: want RCW=4 RCO=1 RCS=1, got RCW=%d RCO=%d RCS=%d",
+			sum.RCWRecords, sum.RCORecords, sum.RCSRecords)
+	}
+	if sum.RCURecords != 1 {
+		t.Errorf("RCURecords: want 1 (an RCO is present), got %d", sum.RCURecords)
+	}
+	if sum.TotalRecords != 11 {
+		t.Errorf("TotalRecords: want 11, got %d", sum.TotalRecords)
+	}
+	if sum.Deltas.WagesTipsOther != 400000 {
+		t.Errorf("Deltas.WagesTipsOther: want 400000, got %d", sum.Deltas.WagesTipsOther)
+	}
+}
+
+func TestHasCorrection_StateOnly(t *testing.T) {
+	e := domain.EmployeeRecord{
+		SSN: "987654321", FirstName: "JOHN", LastName: "SMITH",
+		CorrectStateCode: "IL",
+		Amounts: domain.MonetaryAmounts{
+			OriginalStateWages: 5000000,
+			CorrectStateWages:  5100000,
+		},
+	}
+	if !e.HasCorrection() {
+		t.Error("HasCorrection: want true for a state-wages-only correction")
+	}
+
+	e.Amounts.CorrectStateWages = e.Amounts.OriginalStateWages
+	if e.HasCorrection() {
+		t.Error("HasCorrection: want false once state wages match")
+	}
+}
+
+func TestTotals_SumsBoxes(t *testing.T) {
+	s := &domain.Submission{
+		Employees: []domain.EmployeeRecord{
+			{SSN: "111111111", Amounts: domain.MonetaryAmounts{
+				OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5500000,
+				OriginalMedicareTax: 72500, CorrectMedicareTax: 79750,
+			}},
+			{SSN: "222222222", Amounts: domain.MonetaryAmounts{
+				OriginalWagesTipsOther: 3000000, CorrectWagesTipsOther: 2900000,
+			}},
+		},
+	}
+	got := s.Totals()
+	if got.WagesTipsOther != (domain.BoxTotal{Original: 8000000, Correct: 8400000}) {
+		t.Errorf("Box 1: want 8000000/8400000, got %+v", got.WagesTipsOther)
+	}
+	if got.MedicareTax != (domain.BoxTotal{Original: 72500, Correct: 79750}) {
+		t.Errorf("Box 6: want 72500/79750, got %+v", got.MedicareTax)
+	}
+	if got.FederalIncomeTax != (domain.BoxTotal{}) {
+		t.Errorf("Box 2: want zero, got %+v", got.FederalIncomeTax)
+	}
+}
+
+// TestSummary_AdditionalEmployers verifies every employer block adds its
+// RCE and RCT, and an RCU when it has an RCO.
+func TestSummary_AdditionalEmployers(t *testing.T) {
+	changed := domain.MonetaryAmounts{OriginalWagesTipsOther: 100, CorrectWagesTipsOther: 200}
+	withRCO := changed
+	withRCO.CorrectAllocatedTips = 50
+	s := &domain.Submission{
+		Employees: []domain.EmployeeRecord{{SSN: "111111111", Amounts: changed}},
+		AdditionalEmployers: []domain.EmployerBlock{
+			{Employees: []domain.EmployeeRecord{{SSN: "222222222", Amounts: withRCO}}},
+			{Employees: []domain.EmployeeRecord{{SSN: "333333333", Amounts: changed}, {SSN: "444444444", Amounts: withRCO}}},
+		},
+	}
+	sum := s.Summary()
+	if sum.Employees != 4 || sum.RCWRecords != 4 || sum.RCORecords != 2 || sum.RCURecords != 2 {
+		t.Errorf("got Employees=%d RCW=%d RCO=%d RCU=%d, want 4/4/2/2",
+			sum.Employees, sum.RCWRecords, sum.RCORecords, sum.RCURecords)
+	}
+	// RCA, RCF, 3 × (RCE, RCT), 4 RCW, 2 RCO, 2 RCU.
+	if sum.TotalRecords != 16 {
+		t.Errorf("TotalRecords: want 16, got %d", sum.TotalRecords)
+	}
+	if sum.Deltas.WagesTipsOther != 400 {
+		t.Errorf("Deltas.WagesTipsOther: want 400, got %d", sum.Deltas.WagesTipsOther)
+	}
+	if got := s.Totals().WagesTipsOther; got != (domain.BoxTotal{Original: 400, Correct: 800}) {
+		t.Errorf("Totals Box 1: want 400/800, got %+v", got)
+	}
+}
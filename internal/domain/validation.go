@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a blocking problem: the EFW2C file must not be
+// generated until it is fixed.
+type ValidationError struct {
+	Code     string // machine-readable, e.g. "invalid_ssn"; the HTML layer keys off it
+	Field    string // domain field, e.g. "SocialSecurityTax"
+	Employee int    // index into Submission.Employees; -1 for submission-level fields
+	Message  string
+	// Limit and Got are set by width/overflow checks: the largest value the
+	// field can hold and the offending value, both formatted for display.
+	Limit string
+	Got   string
+}
+
+func (e ValidationError) Error() string {
+	if e.Employee >= 0 {
+		return fmt.Sprintf("employee %d: %s: %s", e.Employee+1, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every blocking problem found in a submission.
+type ValidationErrors []ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return "efw2c: validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Warning is a non-fatal reconciliation advisory about a submission. The
+// EFW2C file can still be generated, but SSA may question or reject the
+// affected values.
+type Warning struct {
+	Code     string // machine-readable, e.g. "ss_tax_rate"
+	Field    string // MonetaryAmounts field without the Original/Correct prefix
+	Employee int    // index into Submission.Employees; -1 for submission-level fields
+	Message  string
+	// Expected and Delta (got minus expected), in cents, are set by the
+	// tax-rate and wage-cap checks so the UI can show "expected $X, got $Y";
+	// zero otherwise.
+	Expected int64
+	Delta    int64
+}
+
+// WarningsFor returns the warnings that apply to the employee at index i.
+func WarningsFor(ws []Warning, i int) []Warning {
+	var out []Warning
+	for _, w := range ws {
+		if w.Employee == i {
+			out = append(out, w)
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+// Package fixtures builds a set of representative W-2c submissions for demo
+// data and support reproductions. Every fixture passes the generator's
+// identifier validation and produces a well-formed EFW2C file.
+//
+// The domain model has no foreign-address fields yet, so there is no
+// foreign-address fixture.
+package fixtures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/ports"
+)
+
+// Submissions returns fresh copies of every fixture, in load order:
+// minimal, multi-employee, name correction, SSN correction, all boxes.
+func Submissions() []*domain.Submission {
+	multi := Minimal()
+	multi.Notes = "Fixture: multi-employee"
+	multi.Employees = append(multi.Employees,
+		employee("234567890", "ALICE", "JONES", 3000000, 3100000),
+		employee("345678901", "BOB", "BROWN", 4200000, 4150000),
+	)
+
+	name := Minimal()
+	name.Notes = "Fixture: name correction"
+	name.Employees[0].OriginalFirstName = "JON"
+	name.Employees[0].OriginalLastName = "SMYTH"
+
+	ssn := Minimal()
+	ssn.Notes = "Fixture: SSN correction"
+	ssn.Employees[0].OriginalSSN = "123456780"
+
+	return []*domain.Submission{Minimal(), multi, name, ssn, AllBoxes()}
+}
+
+// Minimal is a single-employee Box 1-6 wage correction.
+func Minimal() *domain.Submission {
+	return &domain.Submission{
+		Submitter: domain.SubmitterInfo{
+			BSOUID:       "TESTUSER",
+			ContactName:  "JANE DOE",
+			ContactPhone: "8005551234",
+			ContactEmail: "jane@example.com",
+			PreparerCode: "L",
+		},
+		Employer: domain.EmployerRecord{
+			EIN:            "123456789",
+			Name:           "ACME CORP",
+			AddressLine1:   "100 MAIN ST",
+			AddressLine2:   "SUITE 200",
+			City:           "SPRINGFIELD",
+			State:          "IL",
+			ZIP:            "62701",
+			ZIPExtension:   "1234",
+			TaxYear:        domain.DefaultTaxYear,
+			EmploymentCode: "R",
+			KindOfEmployer: "N",
+			AgentIndicator: "0",
+		},
+		Employees: []domain.EmployeeRecord{
+			employee("123456789", "JOHN", "SMITH", 5000000, 5100000),
+		},
+		Notes: "Fixture: minimal",
+	}
+}
+
+// AllBoxes sets every amount pair, a Box 13 correction, and state data so
+// the file carries RCW, RCO and RCS records.
+func AllBoxes() *domain.Submission {
+	s := Minimal()
+	s.Notes = "Fixture: all boxes"
+	e := &s.Employees[0]
+	e.AddressLine1 = "200 OAK AVE"
+	e.City = "SPRINGFIELD"
+	e.State = "IL"
+	e.ZIP = "62702"
+	e.OriginalStateCode, e.CorrectStateCode = "IL", "IL"
+	e.OriginalLocalityName, e.CorrectLocalityName = "SPRINGFIELD", "SPRINGFIELD"
+	no, yes := false, true
+	e.Box13.OrigRetirementPlan, e.Box13.CorrectRetirementPlan = &no, &yes
+
+	a := &e.Amounts
+	a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips = 100000, 120000
+	a.OriginalAllocatedTips, a.CorrectAllocatedTips = 50000, 60000
+	a.OriginalUncollectedEETax, a.CorrectUncollectedEETax = 3825, 4590
+	a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption = 0, 500000
+	a.OriginalDependentCare, a.CorrectDependentCare = 250000, 500000
+	a.OriginalNonqualPlan457, a.CorrectNonqualPlan457 = 100000, 150000
+	a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457 = 100000, 150000
+	a.OriginalCode401k, a.CorrectCode401k = 500000, 600000
+	a.OriginalCode403b, a.CorrectCode403b = 100000, 110000
+	a.OriginalCode457bGovt, a.CorrectCode457bGovt = 100000, 110000
+	a.OriginalCodeW_HSA, a.CorrectCodeW_HSA = 200000, 250000
+	a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k = 100000, 120000
+	a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b = 100000, 120000
+	a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth = 900000, 950000
+	a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife = 12000, 15000
+	a.OriginalCodeV_NSO, a.CorrectCodeV_NSO = 1000000, 1250000
+	a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA = 300000, 350000
+	a.OriginalStateWages, a.CorrectStateWages = 5000000, 5100000
+	a.OriginalStateIncomeTax, a.CorrectStateIncomeTax = 247500, 252450
+	a.OriginalLocalWages, a.CorrectLocalWages = 5000000, 5100000
+	a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax = 50000, 51000
+	return s
+}
+
+// employee builds a Box 1-6 correction from original and corrected wages,
+// deriving federal, Social Security and Medicare tax at typical rates.
+func employee(ssn, first, last string, origWages, corrWages int64) domain.EmployeeRecord {
+	return domain.EmployeeRecord{
+		SSN:       ssn,
+		FirstName: first,
+		LastName:  last,
+		Amounts: domain.MonetaryAmounts{
+			OriginalWagesTipsOther:      origWages,
+			CorrectWagesTipsOther:       corrWages,
+			OriginalFederalIncomeTax:    origWages * 16 / 100,
+			CorrectFederalIncomeTax:     corrWages * 16 / 100,
+			OriginalSocialSecurityWages: origWages,
+			CorrectSocialSecurityWages:  corrWages,
+			OriginalSocialSecurityTax:   origWages * 62 / 1000,
+			CorrectSocialSecurityTax:    corrWages * 62 / 1000,
+			OriginalMedicareWages:       origWages,
+			CorrectMedicareWages:        corrWages,
+			OriginalMedicareTax:         origWages * 145 / 10000,
+			CorrectMedicareTax:          corrWages * 145 / 10000,
+		},
+	}
+}
+
+// Load writes every fixture submission and its employees to repo and
+// returns how many submissions were created.
+func Load(ctx context.Context, repo ports.SubmissionRepository) (int, error) {
+	subs := Submissions()
+	for i, s := range subs {
+		employees := s.Employees
+		if err := repo.CreateSubmission(ctx, s); err != nil {
+			return i, fmt.Errorf("fixture %q: %w", s.Notes, err)
+		}
+		for j := range employees {
+			if err := repo.AddEmployee(ctx, s.ID, &employees[j]); err != nil {
+				return i, fmt.Errorf("fixture %q employee %d: %w", s.Notes, j+1, err)
+			}
+		}
+	}
+	return len(subs), nil
+}
@@ -0,0 +1,70 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c"
+	sqliteadapter "github.com/csg33k/w2c-generator/internal/adapters/sqlite"
+	"github.com/csg33k/w2c-generator/internal/fixtures"
+)
+
+// TestLoad loads the fixtures into a migrated SQLite database and checks every
+// stored submission validates and generates cleanly.
+func TestLoad(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "fixtures.db")
+	schema, err := os.ReadFile("../../db/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	db.Close()
+
+	repo, err := sqliteadapter.New(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	n, err := fixtures.Load(ctx, repo)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := len(fixtures.Submissions()); n != want {
+		t.Errorf("Load: want %d submissions, got %d", want, n)
+	}
+
+	list, err := repo.ListSubmissions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != n {
+		t.Fatalf("ListSubmissions: want %d, got %d", n, len(list))
+	}
+	g := efw2c.MustNew(0)
+	for _, item := range list {
+		s, err := repo.GetSubmission(ctx, item.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Employees) == 0 {
+			t.Errorf("%s: no employees stored", s.Notes)
+		}
+		if errs := g.Validate(s); len(errs) > 0 {
+			t.Errorf("%s: Validate: %v", s.Notes, errs)
+		}
+		var buf bytes.Buffer
+		if err := g.Generate(ctx, s, &buf); err != nil {
+			t.Errorf("%s: Generate: %v", s.Notes, err)
+		}
+	}
+}
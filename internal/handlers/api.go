@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/ports"
+)
+
+// ---------------------------------------------------------------------------
+// JSON API
+//
+// The /api routes drive the same repository and generator as the HTMX
+// pages for callers such as payroll services. Submissions are domain
+// structs encoded with their Go field names; money inside Amounts may be
+// integer cents (512345) or a decimal dollar string ("5123.45").
+// ---------------------------------------------------------------------------
+
+// Cents is a money amount that unmarshals from integer cents or a decimal
+// dollar string.
+type Cents int64
+
+func (c *Cents) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("invalid money amount %q: want dollars with up to 2 decimals", s)
+		}
+		v, err := ParseCentsStrict(s)
+		if err != nil {
+			return err
+		}
+		*c = Cents(v)
+		return nil
+	}
+	v, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money amount %s: want integer cents or a decimal string", b)
+	}
+	*c = Cents(v)
+	return nil
+}
+
+// apiSubmission and apiEmployee decode a domain.Submission whose Amounts
+// fields are Cents; the outer Amounts/Employees shadow the embedded ones.
+// AdditionalEmployers is shadowed only so decodeSubmission can refuse it:
+// the repository does not persist extra employer blocks.
+type apiSubmission struct {
+	domain.Submission
+	Employees           []apiEmployee
+	AdditionalEmployers []json.RawMessage
+}
+
+type apiEmployee struct {
+	domain.EmployeeRecord
+	Amounts map[string]Cents
+}
+
+// amounts converts the decoded Cents map to MonetaryAmounts, rejecting
+// names that are not MonetaryAmounts fields.
+func (e *apiEmployee) amounts() (domain.MonetaryAmounts, error) {
+	plain := make(map[string]int64, len(e.Amounts))
+	for k, v := range e.Amounts {
+		plain[k] = int64(v)
+	}
+	b, _ := json.Marshal(plain)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var a domain.MonetaryAmounts
+	err := dec.Decode(&a)
+	return a, err
+}
+
+// apiValidationError is the JSON shape of a domain.ValidationError.
+type apiValidationError struct {
+	Code     string `json:"code"`
+	Field    string `json:"field"`
+	Employee int    `json:"employee"`
+	Message  string `json:"message"`
+	Limit    string `json:"limit,omitempty"`
+	Got      string `json:"got,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiError writes {"error": msg} with status.
+func apiError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// apiValidationErrors writes errs as 422 {"errors": [...]}.
+func apiValidationErrors(w http.ResponseWriter, errs domain.ValidationErrors) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"errors": toAPIErrors(errs)})
+}
+
+func toAPIErrors(errs domain.ValidationErrors) []apiValidationError {
+	out := make([]apiValidationError, len(errs))
+	for i, e := range errs {
+		out[i] = apiValidationError{e.Code, e.Field, e.Employee, e.Message, e.Limit, e.Got}
+	}
+	return out
+}
+
+// apiAuditFinding is the JSON shape of a domain.AuditFinding.
+type apiAuditFinding struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Record   string `json:"record,omitempty"`
+	Field    string `json:"field"`
+	Employee int    `json:"employee"`
+	Message  string `json:"message"`
+}
+
+// apiAuditReport is the JSON shape of a domain.AuditReport, with its
+// error and warning counts up front for callers that only gate on them.
+type apiAuditReport struct {
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Findings []apiAuditFinding `json:"findings"`
+}
+
+// decodeSubmission reads a JSON submission from r's body, converting each
+// employee's Amounts, and defaults a blank tax year to defaultYear. Errors
+// are client mistakes, reported as a 400.
+func decodeSubmission(r *http.Request, defaultYear string) (*domain.Submission, error) {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	var in apiSubmission
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if len(in.AdditionalEmployers) > 0 {
+		return nil, errors.New("AdditionalEmployers is not supported: create one submission per employer")
+	}
+	s := in.Submission
+	s.Employees = make([]domain.EmployeeRecord, len(in.Employees))
+	for i := range in.Employees {
+		a, err := in.Employees[i].amounts()
+		if err != nil {
+			return nil, fmt.Errorf("employee %d: Amounts: %w", i+1, err)
+		}
+		s.Employees[i] = in.Employees[i].EmployeeRecord
+		s.Employees[i].Amounts = a
+	}
+	if s.Employer.TaxYear == "" {
+		s.Employer.TaxYear = defaultYear
+	}
+	return &s, nil
+}
+
+// apiCreateSubmission stores a JSON submission and its employees and
+// answers 201 with the stored submission. Identifier problems found by
+// the generator's Validate are a 422 and nothing is stored.
+func (h *Handler) apiCreateSubmission(w http.ResponseWriter, r *http.Request) {
+	in, err := decodeSubmission(r, h.defaultYear())
+	if err != nil {
+		apiError(w, 400, err.Error())
+		return
+	}
+	s := *in
+	// A malformed identifier is refused outright rather than stored for
+	// cleanDigits to zero-pad later; /api/validate reports it instead.
+	if msg := nineDigitsProblem("EIN", s.Employer.EIN, true); msg != "" {
+		apiError(w, 400, msg)
+		return
+	}
+	for i := range s.Employees {
+		if msg := employeeSSNProblem(&s.Employees[i]); msg != "" {
+			apiError(w, 400, fmt.Sprintf("employee %d: %s", i+1, msg))
+			return
+		}
+	}
+	if errs := h.gen.Validate(&s); len(errs) > 0 {
+		apiValidationErrors(w, errs)
+		return
+	}
+
+	employees := s.Employees
+	s.Employees = nil
+	if err := h.repo.CreateSubmission(r.Context(), &s); err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	if err := h.repo.AddEmployees(r.Context(), s.ID, employees); err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	stored, err := h.repo.GetSubmission(r.Context(), s.ID)
+	if err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/submissions/%d", s.ID))
+	writeJSON(w, http.StatusCreated, stored)
+}
+
+// apiValidate audits a JSON submission without storing it. The report is
+// a 200 whatever it finds; callers gate on its error count.
+func (h *Handler) apiValidate(w http.ResponseWriter, r *http.Request) {
+	s, err := decodeSubmission(r, h.defaultYear())
+	if err != nil {
+		apiError(w, 400, err.Error())
+		return
+	}
+	rep := h.gen.Audit(s)
+	out := apiAuditReport{
+		Errors:   rep.Count(domain.SeverityError),
+		Warnings: rep.Count(domain.SeverityWarning),
+		Findings: make([]apiAuditFinding, len(rep.Findings)),
+	}
+	for i, f := range rep.Findings {
+		out.Findings[i] = apiAuditFinding{string(f.Severity), f.Code, f.Record, f.Field, f.Employee, f.Message}
+	}
+	writeJSON(w, 200, out)
+}
+
+func (h *Handler) apiGetSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		apiError(w, 400, "invalid id")
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if errors.Is(err, ports.ErrNotFound) {
+		apiError(w, 404, "submission not found")
+		return
+	}
+	if err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, 200, s)
+}
+
+// apiGenerate returns the EFW2C file for a stored submission. Like the
+// HTML download it counts toward the submission's generate count; blocking
+// problems are a 422 with the structured errors.
+func (h *Handler) apiGenerate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		apiError(w, 400, "invalid id")
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if errors.Is(err, ports.ErrNotFound) {
+		apiError(w, 404, "submission not found")
+		return
+	}
+	if err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	if len(s.Employees) == 0 {
+		apiError(w, 400, "no employees in submission")
+		return
+	}
+	if errs := h.gen.Validate(s); len(errs) > 0 {
+		apiValidationErrors(w, errs)
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.gen.Generate(r.Context(), s, &buf); err != nil {
+		var verrs domain.ValidationErrors
+		if errors.As(err, &verrs) {
+			apiValidationErrors(w, verrs)
+			return
+		}
+		generationError(w, err)
+		return
+	}
+	if err := h.repo.IncrementGenerateCount(r.Context(), id); err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	filedHeader(w, s)
+	w.Write(buf.Bytes())
+}
+
+// apiManifest is the JSON shape of a domain.Manifest.
+type apiManifest struct {
+	SHA256      string         `json:"sha256"`
+	Bytes       int            `json:"bytes"`
+	Records     map[string]int `json:"records"`
+	EIN         string         `json:"ein"`
+	TaxYear     string         `json:"tax_year"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
+
+// submissionManifest handles GET /submissions/{id}/manifest: it generates
+// the submission's file and answers with its archival manifest as JSON,
+// to be kept beside the downloaded file. The file itself is discarded, so
+// this does not count as a download; generation is deterministic, so the
+// digest matches the file GET /submissions/{id}/generate returns.
+func (h *Handler) submissionManifest(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		apiError(w, 400, "invalid id")
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if errors.Is(err, ports.ErrNotFound) {
+		apiError(w, 404, "submission not found")
+		return
+	}
+	if err != nil {
+		apiError(w, 500, err.Error())
+		return
+	}
+	if len(s.Employees) == 0 {
+		apiError(w, 400, "no employees in submission")
+		return
+	}
+	if errs := h.gen.Validate(s); len(errs) > 0 {
+		apiValidationErrors(w, errs)
+		return
+	}
+	m, err := h.gen.GenerateWithManifest(r.Context(), s, io.Discard)
+	if err != nil {
+		var verrs domain.ValidationErrors
+		if errors.As(err, &verrs) {
+			apiValidationErrors(w, verrs)
+			return
+		}
+		generationError(w, err)
+		return
+	}
+	writeJSON(w, 200, apiManifest{m.SHA256, m.ByteCount, m.Records, m.EIN, m.TaxYear, m.GeneratedAt})
+}
+
+// apiBatchRequest is the body of POST /api/generate-batch.
+type apiBatchRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// apiGenerateBatch answers {"ids": [...]} with a ZIP holding each
+// submission's EFW2C file as W2C_<EIN>.txt, for service bureaus uploading
+// several employers in one BSO session. A second submission for the same
+// EIN gets its id appended to the name; an id listed twice is a 400. Every
+// file is built before the ZIP starts, so a missing submission is a 404, a
+// repository failure a 500 and a blocking problem a 422 {"submission": id,
+// "errors": [...]}, and nothing is counted as downloaded; otherwise each
+// counts toward its generate count.
+func (h *Handler) apiGenerateBatch(w http.ResponseWriter, r *http.Request) {
+	var req apiBatchRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		apiError(w, 400, "invalid JSON: "+err.Error())
+		return
+	}
+	if len(req.IDs) == 0 {
+		apiError(w, 400, "ids: want at least one submission id")
+		return
+	}
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			apiError(w, 400, fmt.Sprintf("ids: submission %d listed more than once", id))
+			return
+		}
+		seen[id] = true
+	}
+
+	type entry struct {
+		name string
+		file []byte
+	}
+	entries := make([]entry, 0, len(req.IDs))
+	names := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		s, err := h.repo.GetSubmission(r.Context(), id)
+		if errors.Is(err, ports.ErrNotFound) {
+			apiError(w, 404, fmt.Sprintf("submission %d not found", id))
+			return
+		}
+		if err != nil {
+			apiError(w, 500, fmt.Sprintf("submission %d: %v", id, err))
+			return
+		}
+		if len(s.Employees) == 0 {
+			apiError(w, 400, fmt.Sprintf("submission %d: no employees in submission", id))
+			return
+		}
+		if errs := h.gen.Validate(s); len(errs) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"submission": id, "errors": toAPIErrors(errs)})
+			return
+		}
+		var buf bytes.Buffer
+		if err := h.gen.Generate(r.Context(), s, &buf); err != nil {
+			var verrs domain.ValidationErrors
+			if errors.As(err, &verrs) {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"submission": id, "errors": toAPIErrors(verrs)})
+				return
+			}
+			generationError(w, fmt.Errorf("submission %d: %w", id, err))
+			return
+		}
+		name := fmt.Sprintf("W2C_%s.txt", s.Employer.EIN)
+		if names[name] {
+			name = fmt.Sprintf("W2C_%s_%d.txt", s.Employer.EIN, id)
+		}
+		names[name] = true
+		entries = append(entries, entry{name, buf.Bytes()})
+	}
+	for _, id := range req.IDs {
+		if err := h.repo.IncrementGenerateCount(r.Context(), id); err != nil {
+			apiError(w, 500, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="W2C_batch_%s.zip"`, time.Now().Format("20060102")))
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		f, err := zw.Create(e.name)
+		if err == nil {
+			_, err = f.Write(e.file)
+		}
+		if err != nil {
+			// The ZIP has started; abort so the client sees a failed transfer.
+			slog.Error("EFW2C batch stream failed", "entry", e.name, "err", err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		slog.Error("EFW2C batch stream failed", "err", err)
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,280 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c"
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// postJSON sends body to u and returns the status and response body.
+func postJSON(t *testing.T, u, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(u, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+const apiSubmissionJSON = `{
+	"Submitter": {"BSOUID": "APIUSER1", "ContactEmail": "ops@example.com"},
+	"Employer": {"EIN": "123456789", "Name": "API CORP", "TaxYear": "2024"},
+	"Employees": [{
+		"SSN": "123456789", "FirstName": "JOHN", "LastName": "SMITH",
+		"Amounts": {"OriginalWagesTipsOther": 5000000, "CorrectWagesTipsOther": "51,000.00"}
+	}]
+}`
+
+// TestAPI_CreateGetGenerate posts a submission mixing integer cents and a
+// decimal string, reads it back, and downloads its EFW2C file.
+func TestAPI_CreateGetGenerate(t *testing.T) {
+	srv, _ := newServer(t)
+	body := strings.Replace(apiSubmissionJSON, `"51,000.00"`, `"51000.00"`, 1)
+	status, resp := postJSON(t, srv.URL+"/api/submissions", body)
+	if status != http.StatusCreated {
+		t.Fatalf("POST: want 201, got %d: %s", status, resp)
+	}
+	var created domain.Submission
+	if err := json.Unmarshal([]byte(resp), &created); err != nil {
+		t.Fatalf("POST response: %v", err)
+	}
+	if len(created.Employees) != 1 || created.Employees[0].Amounts.CorrectWagesTipsOther != 5100000 ||
+		created.Employees[0].Amounts.OriginalWagesTipsOther != 5000000 {
+		t.Fatalf("POST: want stored Box 1 5000000 -> 5100000, got %+v", created.Employees)
+	}
+
+	status, resp = do(t, http.MethodGet, srv.URL+fmt.Sprintf("/api/submissions/%d", created.ID), nil)
+	var got domain.Submission
+	if status != 200 || json.Unmarshal([]byte(resp), &got) != nil || got.Employer.Name != "API CORP" {
+		t.Fatalf("GET: want 200 with API CORP, got %d: %s", status, resp)
+	}
+
+	status, resp = do(t, http.MethodGet, srv.URL+fmt.Sprintf("/api/submissions/%d/efw2c", created.ID), nil)
+	if status != 200 || len(resp) == 0 || len(resp)%1024 != 0 || resp[:3] != "RCA" {
+		t.Fatalf("GET efw2c: want a 1024-byte record file, got %d (%d bytes)", status, len(resp))
+	}
+}
+
+// TestAPI_LookupErrors checks that only a missing submission is a 404;
+// any other repository failure is a 500.
+func TestAPI_LookupErrors(t *testing.T) {
+	for _, path := range []string{"/api/submissions/%d", "/api/submissions/%d/efw2c", "/submissions/%d/manifest"} {
+		srv, repo := newServer(t)
+		status, resp := do(t, http.MethodGet, srv.URL+fmt.Sprintf(path, 999), nil)
+		if status != http.StatusNotFound || !strings.Contains(resp, "submission not found") {
+			t.Errorf("%s missing: want 404 submission not found, got %d: %s", path, status, resp)
+		}
+
+		repo.getErr = errors.New("database is locked")
+		status, resp = do(t, http.MethodGet, srv.URL+fmt.Sprintf(path, 1), nil)
+		if status != http.StatusInternalServerError {
+			t.Errorf("%s repository failure: want 500, got %d: %s", path, status, resp)
+		}
+	}
+}
+
+// TestAPI_InvalidInput covers malformed money and a short SSN (400) and
+// an invalid SSN, which is a structured 422.
+func TestAPI_InvalidInput(t *testing.T) {
+	srv, repo := newServer(t)
+
+	// "51,000.00" is not a plain decimal string.
+	if status, resp := postJSON(t, srv.URL+"/api/submissions", apiSubmissionJSON); status != 400 || !strings.Contains(resp, "invalid money amount") {
+		t.Errorf("malformed money: want 400 invalid money amount, got %d: %s", status, resp)
+	}
+	if status, _ := postJSON(t, srv.URL+"/api/submissions", strings.Replace(apiSubmissionJSON, "OriginalWagesTipsOther", "OriginalWages", 1)); status != 400 {
+		t.Errorf("unknown Amounts field: want 400, got %d", status)
+	}
+	body := strings.NewReplacer(`"51,000.00"`, `5100000`, `"Employees": [`, `"AdditionalEmployers": [{"Employer": {"EIN": "987654321"}}], "Employees": [`).Replace(apiSubmissionJSON)
+	if status, resp := postJSON(t, srv.URL+"/api/submissions", body); status != 400 || !strings.Contains(resp, "AdditionalEmployers") {
+		t.Errorf("AdditionalEmployers: want 400, got %d: %s", status, resp)
+	}
+
+	body = strings.NewReplacer(`"51,000.00"`, `5100000`, `"SSN": "123456789"`, `"SSN": "1234567"`).Replace(apiSubmissionJSON)
+	if status, resp := postJSON(t, srv.URL+"/api/submissions", body); status != 400 || !strings.Contains(resp, "SSN must be 9 digits") {
+		t.Errorf("7-digit SSN: want 400, got %d: %s", status, resp)
+	}
+
+	body = strings.NewReplacer(`"51,000.00"`, `5100000`, `"SSN": "123456789"`, `"SSN": "000123456"`).Replace(apiSubmissionJSON)
+	status, resp := postJSON(t, srv.URL+"/api/submissions", body)
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid SSN: want 422, got %d: %s", status, resp)
+	}
+	var out struct {
+		Errors []struct {
+			Code     string `json:"code"`
+			Field    string `json:"field"`
+			Employee int    `json:"employee"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(resp), &out); err != nil || len(out.Errors) == 0 {
+		t.Fatalf("invalid SSN: want errors array, got %s", resp)
+	}
+	if e := out.Errors[0]; e.Code != efw2c.CodeInvalidSSN || e.Employee != 0 {
+		t.Errorf("invalid SSN: want invalid_ssn on employee 0, got %+v", e)
+	}
+	if subs, _ := repo.ListSubmissions(context.Background()); len(subs) != 1 {
+		t.Errorf("invalid submission must not be stored; have %d submissions", len(subs))
+	}
+}
+
+// TestAPI_Validate audits a submission with one bad SSN: the report is a
+// 200 with exactly one error, and nothing is stored.
+func TestAPI_Validate(t *testing.T) {
+	srv, repo := newServer(t)
+	body := strings.NewReplacer(`"51,000.00"`, `5100000`, `"SSN": "123456789"`, `"SSN": "000123456"`).Replace(apiSubmissionJSON)
+	status, resp := postJSON(t, srv.URL+"/api/validate", body)
+	if status != 200 {
+		t.Fatalf("want 200, got %d: %s", status, resp)
+	}
+	var out struct {
+		Errors   int `json:"errors"`
+		Findings []struct {
+			Severity string `json:"severity"`
+			Code     string `json:"code"`
+			Employee int    `json:"employee"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(resp), &out); err != nil {
+		t.Fatalf("response: %v: %s", err, resp)
+	}
+	var errs []string
+	for _, f := range out.Findings {
+		if f.Severity == "error" {
+			errs = append(errs, f.Code)
+		}
+	}
+	if out.Errors != 1 || len(errs) != 1 || errs[0] != efw2c.CodeInvalidSSN {
+		t.Errorf("want errors=1 with one invalid_ssn finding, got errors=%d %v", out.Errors, errs)
+	}
+	if subs, _ := repo.ListSubmissions(context.Background()); len(subs) != 1 {
+		t.Errorf("validate must not store anything; have %d submissions", len(subs))
+	}
+
+	if status, _ := postJSON(t, srv.URL+"/api/validate", "{"); status != 400 {
+		t.Errorf("malformed JSON: want 400, got %d", status)
+	}
+}
+
+// TestAPI_GenerateBatch creates three submissions, two for the same EIN,
+// and expects a ZIP with one EFW2C file per requested id, uniquely named.
+func TestAPI_GenerateBatch(t *testing.T) {
+	srv, repo := newServer(t)
+	var ids []int64
+	for _, ein := range []string{"123456789", "987654321", "987654321"} {
+		body := strings.NewReplacer(`"51,000.00"`, `5100000`, `"EIN": "123456789"`, `"EIN": "`+ein+`"`).Replace(apiSubmissionJSON)
+		status, resp := postJSON(t, srv.URL+"/api/submissions", body)
+		var created domain.Submission
+		if status != http.StatusCreated || json.Unmarshal([]byte(resp), &created) != nil {
+			t.Fatalf("create %s: want 201, got %d: %s", ein, status, resp)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	body, _ := json.Marshal(map[string][]int64{"ids": ids})
+	status, resp := postJSON(t, srv.URL+"/api/generate-batch", string(body))
+	if status != 200 {
+		t.Fatalf("want 200, got %d: %s", status, resp)
+	}
+	zr, err := zip.NewReader(bytes.NewReader([]byte(resp)), int64(len(resp)))
+	if err != nil {
+		t.Fatalf("response is not a ZIP: %v", err)
+	}
+	want := []string{"W2C_123456789.txt", "W2C_987654321.txt", fmt.Sprintf("W2C_987654321_%d.txt", ids[2])}
+	if len(zr.File) != len(want) {
+		t.Fatalf("want %d entries, got %d", len(want), len(zr.File))
+	}
+	for i, f := range zr.File {
+		if f.Name != want[i] {
+			t.Errorf("entry %d: want %s, got %s", i, want[i], f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if len(data) == 0 || len(data)%1024 != 0 || string(data[:3]) != "RCA" {
+			t.Errorf("%s: want a 1024-byte record file, got %d bytes", f.Name, len(data))
+		}
+	}
+
+	// Submission 1 has no employees.
+	if status, _ := postJSON(t, srv.URL+"/api/generate-batch", fmt.Sprintf(`{"ids": [%d, 1]}`, ids[0])); status != 400 {
+		t.Errorf("empty submission: want 400, got %d", status)
+	}
+	if status, _ := postJSON(t, srv.URL+"/api/generate-batch", `{"ids": [999]}`); status != 404 {
+		t.Errorf("unknown id: want 404, got %d", status)
+	}
+	repo.getErr = errors.New("database is locked")
+	if status, _ := postJSON(t, srv.URL+"/api/generate-batch", fmt.Sprintf(`{"ids": [%d]}`, ids[0])); status != 500 {
+		t.Errorf("repository failure: want 500, got %d", status)
+	}
+	repo.getErr = nil
+	dup := fmt.Sprintf(`{"ids": [%d, %d, %d]}`, ids[0], ids[1], ids[0])
+	if status, resp := postJSON(t, srv.URL+"/api/generate-batch", dup); status != 400 || !strings.Contains(resp, "more than once") {
+		t.Errorf("duplicate id: want 400, got %d: %s", status, resp)
+	}
+	if s, _ := repo.GetSubmission(context.Background(), ids[0]); s.GenerateCount != 1 {
+		t.Errorf("duplicate id: want generate count to stay 1, got %d", s.GenerateCount)
+	}
+}
+
+// TestSubmissionManifest expects the manifest of a two-employee submission
+// to count its records and digest the file the download returns.
+func TestSubmissionManifest(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployees(context.Background(), 1, []domain.EmployeeRecord{
+		{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH", Amounts: domain.MonetaryAmounts{
+			OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}},
+		{SSN: "234567890", FirstName: "MARY", LastName: "JONES", Amounts: domain.MonetaryAmounts{
+			OriginalFederalIncomeTax: 400000, CorrectFederalIncomeTax: 450000}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1/manifest", nil)
+	if status != 200 {
+		t.Fatalf("want 200, got %d: %s", status, body)
+	}
+	var m struct {
+		SHA256  string         `json:"sha256"`
+		Bytes   int            `json:"bytes"`
+		Records map[string]int `json:"records"`
+		EIN     string         `json:"ein"`
+		TaxYear string         `json:"tax_year"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		t.Fatalf("manifest is not JSON: %v\n%s", err, body)
+	}
+	want := map[string]int{"RCA": 1, "RCE": 1, "RCW": 2, "RCT": 1, "RCF": 1}
+	if fmt.Sprint(m.Records) != fmt.Sprint(want) {
+		t.Errorf("records: want %v, got %v", want, m.Records)
+	}
+	if m.EIN != "123456789" || m.TaxYear != "2024" {
+		t.Errorf("got EIN %q, tax year %q", m.EIN, m.TaxYear)
+	}
+
+	_, file := do(t, http.MethodGet, srv.URL+"/submissions/1/generate", nil)
+	if sum := sha256.Sum256([]byte(file)); m.SHA256 != hex.EncodeToString(sum[:]) || m.Bytes != len(file) {
+		t.Errorf("manifest %s (%d bytes) does not match the downloaded file (%d bytes)", m.SHA256, m.Bytes, len(file))
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/submissions/99/manifest", nil); status != 404 {
+		t.Errorf("missing submission: want 404, got %d", status)
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+// exportCSV streams every employee of a submission as CSV with the
+// csvHeader columns, so the file can be edited and imported again.
+func (h *Handler) exportCSV(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="W2C_%s_employees.csv"`, s.Employer.EIN))
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	rec := make([]string, len(csvHeader))
+	for i := range s.Employees {
+		vals := employeeCSVValues(&s.Employees[i])
+		for j, c := range csvHeader {
+			rec[j] = vals[c]
+		}
+		cw.Write(rec)
+	}
+	cw.Flush()
+}
+
+// employeeCSVValues is the inverse of parseEmployeeForm for the CSV
+// columns: money as decimal dollars, Box 13 flags as 1/0 (blank when not
+// being corrected). Zero amounts are written as 0.00.
+func employeeCSVValues(e *domain.EmployeeRecord) map[string]string {
+	flag := func(b *bool) string {
+		switch {
+		case b == nil:
+			return ""
+		case *b:
+			return "1"
+		}
+		return "0"
+	}
+	v := map[string]string{
+		"ssn":                  e.SSN,
+		"original_ssn":         e.OriginalSSN,
+		"first_name":           e.FirstName,
+		"middle_name":          e.MiddleName,
+		"last_name":            e.LastName,
+		"suffix":               e.Suffix,
+		"orig_first_name":      e.OriginalFirstName,
+		"orig_middle_name":     e.OriginalMiddleName,
+		"orig_last_name":       e.OriginalLastName,
+		"orig_suffix":          e.OriginalSuffix,
+		"emp_addr1":            e.AddressLine1,
+		"emp_addr2":            e.AddressLine2,
+		"emp_city":             e.City,
+		"emp_state":            e.State,
+		"emp_zip":              e.ZIP,
+		"emp_zip_ext":          e.ZIPExtension,
+		"emp_foreign_province": e.ForeignStateProvince,
+		"emp_foreign_postal":   e.ForeignPostalCode,
+		"emp_country_code":     e.CountryCode,
+		"orig_state_code":      e.OriginalStateCode,
+		"corr_state_code":      e.CorrectStateCode,
+		"orig_state_id":        e.OriginalStateIDNumber,
+		"corr_state_id":        e.CorrectStateIDNumber,
+		"orig_locality_name":   e.OriginalLocalityName,
+		"corr_locality_name":   e.CorrectLocalityName,
+
+		"orig_statutory_emp":    flag(e.Box13.OrigStatutoryEmployee),
+		"corr_statutory_emp":    flag(e.Box13.CorrectStatutoryEmployee),
+		"orig_retirement_plan":  flag(e.Box13.OrigRetirementPlan),
+		"corr_retirement_plan":  flag(e.Box13.CorrectRetirementPlan),
+		"orig_third_party_sick": flag(e.Box13.OrigThirdPartySickPay),
+		"corr_third_party_sick": flag(e.Box13.CorrectThirdPartySickPay),
+	}
+	a := &e.Amounts
+	for name, pair := range map[string][2]int64{
+		"wages":           {a.OriginalWagesTipsOther, a.CorrectWagesTipsOther},
+		"fed_tax":         {a.OriginalFederalIncomeTax, a.CorrectFederalIncomeTax},
+		"ss_wages":        {a.OriginalSocialSecurityWages, a.CorrectSocialSecurityWages},
+		"ss_tax":          {a.OriginalSocialSecurityTax, a.CorrectSocialSecurityTax},
+		"med_wages":       {a.OriginalMedicareWages, a.CorrectMedicareWages},
+		"med_tax":         {a.OriginalMedicareTax, a.CorrectMedicareTax},
+		"ss_tips":         {a.OriginalSocialSecurityTips, a.CorrectSocialSecurityTips},
+		"alloc_tips":      {a.OriginalAllocatedTips, a.CorrectAllocatedTips},
+		"uncoll_tips_tax": {a.OriginalUncollectedEETax, a.CorrectUncollectedEETax},
+		"dep_care":        {a.OriginalDependentCare, a.CorrectDependentCare},
+		"nonqual_457":     {a.OriginalNonqualPlan457, a.CorrectNonqualPlan457},
+		"nonqual_not457":  {a.OriginalNonqualNotSection457, a.CorrectNonqualNotSection457},
+		"code_c":          {a.OriginalCodeC_GroupTermLife, a.CorrectCodeC_GroupTermLife},
+		"code_d":          {a.OriginalCode401k, a.CorrectCode401k},
+		"code_e":          {a.OriginalCode403b, a.CorrectCode403b},
+		"code_f":          {a.OriginalCodeF_SARSEP, a.CorrectCodeF_SARSEP},
+		"code_g":          {a.OriginalCode457bGovt, a.CorrectCode457bGovt},
+		"code_h":          {a.OriginalCodeH_501c18D, a.CorrectCodeH_501c18D},
+		"code_m":          {a.OriginalCodeM_UncollSS, a.CorrectCodeM_UncollSS},
+		"code_n":          {a.OriginalCodeN_UncollMed, a.CorrectCodeN_UncollMed},
+		"code_q":          {a.OriginalCodeQ_CombatPay, a.CorrectCodeQ_CombatPay},
+		"code_r":          {a.OriginalCodeR_MSA, a.CorrectCodeR_MSA},
+		"code_s":          {a.OriginalCodeS_SIMPLE, a.CorrectCodeS_SIMPLE},
+		"code_t":          {a.OriginalCodeT_Adoption, a.CorrectCodeT_Adoption},
+		"code_v":          {a.OriginalCodeV_NSO, a.CorrectCodeV_NSO},
+		"code_w":          {a.OriginalCodeW_HSA, a.CorrectCodeW_HSA},
+		"code_y":          {a.OriginalCodeY_409A, a.CorrectCodeY_409A},
+		"code_z":          {a.OriginalCodeZ_409A, a.CorrectCodeZ_409A},
+		"code_aa":         {a.OriginalCodeAA_Roth401k, a.CorrectCodeAA_Roth401k},
+		"code_bb":         {a.OriginalCodeBB_Roth403b, a.CorrectCodeBB_Roth403b},
+		"code_dd":         {a.OriginalCodeDD_EmpHealth, a.CorrectCodeDD_EmpHealth},
+		"code_ff":         {a.OriginalCodeFF_QSEHRA, a.CorrectCodeFF_QSEHRA},
+		"code_ii":         {a.OriginalMedicaidWaiver, a.CorrectMedicaidWaiver},
+		"state_wages":     {a.OriginalStateWages, a.CorrectStateWages},
+		"state_tax":       {a.OriginalStateIncomeTax, a.CorrectStateIncomeTax},
+		"local_wages":     {a.OriginalLocalWages, a.CorrectLocalWages},
+		"local_tax":       {a.OriginalLocalIncomeTax, a.CorrectLocalIncomeTax},
+	} {
+		v["orig_"+name] = centsToDisplay(pair[0])
+		v["corr_"+name] = centsToDisplay(pair[1])
+	}
+	return v
+}
+
+// centsToDisplay formats cents as decimal dollars ("5100.00"), as the
+// templates and PDF do.
+func centsToDisplay(cents int64) string {
+	return fmt.Sprintf("%.2f", float64(cents)/100)
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/templates"
+)
+
+// ---------------------------------------------------------------------------
+// CSV employee import
+//
+// The header row names columns after the employee form fields, so a CSV
+// row is read exactly as the form would be. Only ssn is required; columns
+// may appear in any order and missing ones are left zero.
+// ---------------------------------------------------------------------------
+
+// csvTextColumns are the identity, name, address and state columns.
+var csvTextColumns = []string{
+	"ssn", "original_ssn",
+	"first_name", "middle_name", "last_name", "suffix",
+	"orig_first_name", "orig_middle_name", "orig_last_name", "orig_suffix",
+	"emp_addr1", "emp_addr2", "emp_city", "emp_state", "emp_zip", "emp_zip_ext",
+	"emp_foreign_province", "emp_foreign_postal", "emp_country_code",
+	"orig_state_code", "corr_state_code", "orig_state_id", "corr_state_id",
+	"orig_locality_name", "corr_locality_name",
+}
+
+// csvMoneyColumns are dollar amounts; each name is used with an orig_ and
+// a corr_ prefix (orig_wages, corr_wages, ...).
+var csvMoneyColumns = []string{
+	"wages", "fed_tax", "ss_wages", "ss_tax", "med_wages", "med_tax", "ss_tips",
+	"alloc_tips", "uncoll_tips_tax", "dep_care", "nonqual_457", "nonqual_not457",
+	"code_c", "code_d", "code_e", "code_f", "code_g", "code_h", "code_m", "code_n",
+	"code_q", "code_r", "code_s", "code_t", "code_v", "code_w", "code_y", "code_z",
+	"code_aa", "code_bb", "code_dd", "code_ff", "code_ii",
+	"state_wages", "state_tax", "local_wages", "local_tax",
+}
+
+// csvFlagColumns are the Box 13 checkboxes: 1 (checked), 0 or blank (not
+// being corrected).
+var csvFlagColumns = []string{
+	"orig_statutory_emp", "corr_statutory_emp",
+	"orig_retirement_plan", "corr_retirement_plan",
+	"orig_third_party_sick", "corr_third_party_sick",
+}
+
+type csvKind int
+
+const (
+	csvText csvKind = iota
+	csvMoney
+	csvFlag
+)
+
+// csvHeader is every column in export order; import accepts any subset.
+var csvHeader = func() []string {
+	h := append([]string(nil), csvTextColumns...)
+	for _, c := range csvMoneyColumns {
+		h = append(h, "orig_"+c, "corr_"+c)
+	}
+	return append(h, csvFlagColumns...)
+}()
+
+// csvColumns maps every accepted header name to its kind.
+var csvColumns = func() map[string]csvKind {
+	m := make(map[string]csvKind, len(csvHeader))
+	for _, c := range csvTextColumns {
+		m[c] = csvText
+	}
+	for _, c := range csvMoneyColumns {
+		m["orig_"+c] = csvMoney
+		m["corr_"+c] = csvMoney
+	}
+	for _, c := range csvFlagColumns {
+		m[c] = csvFlag
+	}
+	return m
+}()
+
+// csvRow is one employee decoded from an import file. Box13 reports
+// whether the file had Box 13 columns at all.
+type csvRow struct {
+	Line     int
+	Employee *domain.EmployeeRecord
+	Box13    bool
+}
+
+// parseEmployeeCSV reads an employee import file. A bad header is an
+// error; a bad row is reported in rowErrs and the rest are still returned.
+// Rows whose fields are all blank are skipped.
+func parseEmployeeCSV(r io.Reader) (rows []csvRow, rowErrs []domain.ImportRowError, err error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("empty CSV: want a header row")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("CSV header: %w", err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if i == 0 {
+			h = strings.TrimPrefix(h, "\ufeff") // spreadsheet byte-order mark
+		}
+		if _, ok := csvColumns[h]; !ok {
+			return nil, nil, fmt.Errorf("CSV header: unknown column %q", header[i])
+		}
+		if seen[h] {
+			return nil, nil, fmt.Errorf("CSV header: duplicate column %q", h)
+		}
+		seen[h] = true
+		header[i] = h
+	}
+	if !seen["ssn"] {
+		return nil, nil, errors.New(`CSV header: missing required column "ssn"`)
+	}
+	box13 := false
+	for _, c := range csvFlagColumns {
+		box13 = box13 || seen[c]
+	}
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return nil, nil, err
+			}
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: pe.StartLine, Message: pe.Err.Error()})
+			continue
+		}
+		if blankRow(rec) {
+			continue
+		}
+		e, msg := employeeFromCSV(header, rec)
+		if msg != "" {
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: line, Message: msg})
+			continue
+		}
+		rows = append(rows, csvRow{Line: line, Employee: e, Box13: box13})
+	}
+	return rows, rowErrs, nil
+}
+
+// employeeFromCSV decodes one row through parseEmployeeForm, checking its
+// money columns strictly and its SSNs as addEmployee does. msg is the
+// row's problem, if any.
+func employeeFromCSV(header, rec []string) (e *domain.EmployeeRecord, msg string) {
+	var problems []string
+	form := make(url.Values, len(header))
+	for i, h := range header {
+		v := strings.TrimSpace(rec[i])
+		switch csvColumns[h] {
+		case csvMoney:
+			if _, err := ParseCentsStrict(v); err != nil {
+				problems = append(problems, h+": "+err.Error())
+			}
+		case csvFlag:
+			if v != "" && v != "0" && v != "1" {
+				problems = append(problems, fmt.Sprintf("%s: want 1, 0 or blank, got %q", h, v))
+			}
+		}
+		form.Set(h, v)
+	}
+	// parseEmployeeForm only calls FormValue, which reads a preset Form.
+	e = parseEmployeeForm(&http.Request{Form: form})
+	if strings.TrimSpace(form.Get("ssn")) == "" {
+		problems = append(problems, "ssn: required")
+	} else if msg := employeeSSNProblem(e); msg != "" {
+		problems = append(problems, msg)
+	}
+	if len(problems) > 0 {
+		return nil, strings.Join(problems, "; ")
+	}
+	return e, ""
+}
+
+func blankRow(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// maxImportBytes bounds a CSV upload; 10 MB is tens of thousands of rows.
+const maxImportBytes = 10 << 20
+
+// importCSV upserts one employee per row of an uploaded CSV (form field
+// "file"), matching existing employees by SSN. A row for a new SSN is
+// inserted; one for a stored SSN replaces that employee (keeping its Box 13
+// flags if the file has no Box 13 columns) or is skipped if nothing changed. Good
+// rows are applied even when others are rejected, all in one transaction; the
+// response reports every row's outcome, and each rejected row's line
+// number, above the refreshed list.
+func (h *Handler) importCSV(w http.ResponseWriter, r *http.Request) {
+	subID, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "CSV upload: "+err.Error(), 400)
+		return
+	}
+	defer f.Close()
+	s, err := h.repo.GetSubmission(r.Context(), subID)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	rows, rowErrs, err := parseEmployeeCSV(f)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	bySSN := make(map[string]*domain.EmployeeRecord, len(s.Employees))
+	for i := range s.Employees {
+		bySSN[stripDashes(s.Employees[i].SSN)] = &s.Employees[i]
+	}
+	res := domain.ImportResult{Errors: rowErrs}
+	// Accepted rows are saved together, so a failure leaves the submission
+	// as it was. A later row for an SSN already in the batch replaces it.
+	var batch []domain.EmployeeRecord
+	inBatch := make(map[string]int)
+	for _, row := range rows {
+		e := row.Employee
+		outcome := domain.ImportInserted
+		if old, ok := bySSN[e.SSN]; ok {
+			e.ID, e.SubmissionID = old.ID, old.SubmissionID
+			e.CreatedAt, e.UpdatedAt = old.CreatedAt, old.UpdatedAt
+			if !row.Box13 {
+				e.Box13 = old.Box13
+			}
+			outcome = domain.ImportUpdated
+			if reflect.DeepEqual(e, old) {
+				outcome = domain.ImportSkipped
+			}
+		}
+		if outcome != domain.ImportSkipped {
+			if i, ok := inBatch[e.SSN]; ok {
+				batch[i] = *e
+			} else {
+				inBatch[e.SSN] = len(batch)
+				batch = append(batch, *e)
+			}
+		}
+		// A later row with the same SSN is compared against this one.
+		bySSN[e.SSN] = e
+		res.Rows = append(res.Rows, domain.ImportRow{Line: row.Line, SSN: e.SSN, Outcome: outcome})
+	}
+	if err := h.repo.UpsertEmployees(r.Context(), subID, batch); err != nil {
+		http.Error(w, "import failed, no rows saved: "+err.Error(), 500)
+		return
+	}
+	s, err = h.repo.GetSubmission(r.Context(), subID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.CSVImportResult(s, h.gen.Check(s), res))
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
+)
+
+func TestParseEmployeeCSV_QuotedFields(t *testing.T) {
+	in := "ssn,first_name,last_name,emp_addr1,orig_wages,corr_wages\n" +
+		`123-45-6789,"Mary Ann","O'Neil, Jr.","12 Main St, Apt ""B""",50000,"51,000.5"` + "\n"
+	rows, rowErrs, err := parseEmployeeCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "51,000.5" is quoted so the comma survives parsing, then fails as money.
+	if len(rows) != 0 || len(rowErrs) != 1 || rowErrs[0].Line != 2 {
+		t.Fatalf("want line 2 rejected for its comma amount, got rows=%v errs=%+v", rows, rowErrs)
+	}
+
+	in = strings.Replace(in, `"51,000.5"`, `"51000.5"`, 1)
+	rows, rowErrs, err = parseEmployeeCSV(strings.NewReader(in))
+	if err != nil || len(rowErrs) != 0 || len(rows) != 1 {
+		t.Fatalf("want one row, got rows=%v errs=%+v err=%v", rows, rowErrs, err)
+	}
+	e := rows[0].Employee
+	if e.SSN != "123456789" || e.FirstName != "Mary Ann" || e.LastName != "O'Neil, Jr." {
+		t.Errorf("identity: got %q %q %q", e.SSN, e.FirstName, e.LastName)
+	}
+	if e.AddressLine1 != `12 Main St, Apt "B"` {
+		t.Errorf("AddressLine1: got %q", e.AddressLine1)
+	}
+	if e.Amounts.OriginalWagesTipsOther != 5000000 || e.Amounts.CorrectWagesTipsOther != 5100050 {
+		t.Errorf("wages: got %d / %d", e.Amounts.OriginalWagesTipsOther, e.Amounts.CorrectWagesTipsOther)
+	}
+}
+
+func TestParseEmployeeCSV_MalformedMoney(t *testing.T) {
+	in := "ssn,orig_wages,corr_wages\n" +
+		"123456789,100.00,200.00\n" +
+		"\n" +
+		",,\n" +
+		"234567890,12.345,200\n" +
+		"345678901,$50,abc\n" +
+		"456789012,-10.5,\n"
+	rows, rowErrs, err := parseEmployeeCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Line != 2 || rows[1].Line != 7 {
+		t.Fatalf("want rows from lines 2 and 7, got %+v", rows)
+	}
+	if got := rows[1].Employee.Amounts.OriginalWagesTipsOther; got != -1050 {
+		t.Errorf("line 7 orig_wages: want -1050, got %d", got)
+	}
+	if len(rowErrs) != 2 {
+		t.Fatalf("want 2 row errors, got %+v", rowErrs)
+	}
+	if rowErrs[0].Line != 5 || !strings.Contains(rowErrs[0].Message, "orig_wages") {
+		t.Errorf("first error: want line 5 orig_wages, got %+v", rowErrs[0])
+	}
+	if e := rowErrs[1]; e.Line != 6 || !strings.Contains(e.Message, "orig_wages") || !strings.Contains(e.Message, "corr_wages") {
+		t.Errorf("second error: want line 6 naming both columns, got %+v", e)
+	}
+}
+
+func TestParseEmployeeCSV_MalformedSSN(t *testing.T) {
+	in := "ssn,original_ssn,orig_wages,corr_wages\n" +
+		"123-45-6789,,100,200\n" +
+		"12-34,,100,200\n" +
+		"234567890,98765,100,200\n"
+	rows, rowErrs, err := parseEmployeeCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Line != 2 || rows[0].Employee.SSN != "123456789" {
+		t.Fatalf("want only line 2, got %+v", rows)
+	}
+	if len(rowErrs) != 2 {
+		t.Fatalf("want 2 row errors, got %+v", rowErrs)
+	}
+	if e := rowErrs[0]; e.Line != 3 || !strings.Contains(e.Message, "SSN must be 9 digits") {
+		t.Errorf("first error: want line 3 SSN, got %+v", e)
+	}
+	if e := rowErrs[1]; e.Line != 4 || !strings.Contains(e.Message, "Original SSN must be 9 digits") {
+		t.Errorf("second error: want line 4 Original SSN, got %+v", e)
+	}
+}
+
+func TestParseEmployeeCSV_Header(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"first_name,last_name\nMARY,JONES\n",
+		"ssn,wages\n123456789,1\n",
+		"ssn,ssn\n123456789,123456789\n",
+	} {
+		if _, _, err := parseEmployeeCSV(strings.NewReader(in)); err == nil {
+			t.Errorf("%q: want header error", in)
+		}
+	}
+	rows, _, err := parseEmployeeCSV(strings.NewReader("\ufeffSSN, Last_Name\n123456789,JONES\n"))
+	if err != nil || len(rows) != 1 || rows[0].Employee.LastName != "JONES" {
+		t.Errorf("BOM and mixed-case header: got rows=%+v err=%v", rows, err)
+	}
+}
+
+// TestEmployeeCSV_RoundTrip verifies every column survives import, export
+// and import again, so export and import agree on each column's field.
+func TestEmployeeCSV_RoundTrip(t *testing.T) {
+	in := make([]string, len(csvHeader))
+	for i, c := range csvHeader {
+		switch csvColumns[c] {
+		case csvMoney:
+			in[i] = fmt.Sprintf("%d.%02d", i, i)
+		case csvFlag:
+			in[i] = strconv.Itoa(i % 2)
+		default:
+			in[i] = fmt.Sprintf("V%d", i)
+		}
+	}
+	in[0] = "123456789" // ssn
+	in[slices.Index(csvHeader, "original_ssn")] = "987654321"
+	parse := func(rec []string) *domain.EmployeeRecord {
+		t.Helper()
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		w.Write(csvHeader)
+		w.Write(rec)
+		w.Flush()
+		rows, rowErrs, err := parseEmployeeCSV(strings.NewReader(b.String()))
+		if err != nil || len(rowErrs) != 0 || len(rows) != 1 {
+			t.Fatalf("parse: rows=%d errs=%+v err=%v", len(rows), rowErrs, err)
+		}
+		return rows[0].Employee
+	}
+
+	first := parse(in)
+	vals := employeeCSVValues(first)
+	out := make([]string, len(csvHeader))
+	for i, c := range csvHeader {
+		out[i] = vals[c]
+		if out[i] != in[i] {
+			t.Errorf("%s: imported %q, exported %q", c, in[i], out[i])
+		}
+	}
+	if second := parse(out); !reflect.DeepEqual(first, second) {
+		t.Errorf("re-import differs:\n%+v\n%+v", first, second)
+	}
+}
+
+// TestParseCents checks the form parser rounds sub-cent input half-up
+// while ParseCentsStrict rejects it.
+func TestParseCents(t *testing.T) {
+	for in, want := range map[string]int64{
+		"": 0, "1234": 123400, "1234.5": 123450, "1234.99": 123499,
+		"1234.994": 123499, "1234.995": 123500, "1234.999": 123500, "-0.125": -13,
+	} {
+		if got := parseCents(in); got != want {
+			t.Errorf("parseCents(%q) = %d, want %d", in, got, want)
+		}
+	}
+
+	if got, err := ParseCentsStrict("1234.99"); err != nil || got != 123499 {
+		t.Errorf("ParseCentsStrict(1234.99) = %d, %v; want 123499", got, err)
+	}
+	for _, in := range []string{"1234.999", "1234.995", "12,34", "$5"} {
+		if _, err := ParseCentsStrict(in); err == nil {
+			t.Errorf("ParseCentsStrict(%q): want error, got nil", in)
+		}
+	}
+	if _, err := ParseCentsStrict("1234.999"); err == nil || !strings.Contains(err.Error(), "more than 2 decimal places") {
+		t.Errorf("ParseCentsStrict(1234.999): want a decimal places error, got %v", err)
+	}
+}
@@ -2,16 +2,24 @@ package handlers
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/a-h/templ"
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c/spec"
 	"github.com/csg33k/w2c-generator/internal/adapters/pdf"
 	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/fixtures"
 	"github.com/csg33k/w2c-generator/internal/ports"
 	"github.com/csg33k/w2c-generator/internal/templates"
 )
@@ -19,40 +27,92 @@ import (
 type Handler struct {
 	repo ports.SubmissionRepository
 	gen  ports.EFW2CGenerator
+	// timeout bounds the generation routes; zero means no limit.
+	timeout time.Duration
 }
 
-func New(repo ports.SubmissionRepository, gen ports.EFW2CGenerator) *Handler {
-	return &Handler{repo: repo, gen: gen}
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithTimeout bounds the EFW2C and PDF generation routes to d. A request
+// that runs past it is answered with 504 Gateway Timeout. Zero disables
+// the limit.
+func WithTimeout(d time.Duration) Option {
+	return func(h *Handler) { h.timeout = d }
+}
+
+func New(repo ports.SubmissionRepository, gen ports.EFW2CGenerator, opts ...Option) *Handler {
+	h := &Handler{repo: repo, gen: gen}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
 }
 
 func (h *Handler) Routes() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /", h.index)
 	mux.HandleFunc("POST /submissions", h.createSubmission)
+	mux.HandleFunc("POST /submissions/import-efw2c", h.importEFW2C)
 	mux.HandleFunc("GET /submissions/{id}", h.viewSubmission)
 	mux.HandleFunc("DELETE /submissions/{id}", h.deleteSubmission)
 	mux.HandleFunc("GET /submissions/{id}/edit", h.editSubmissionForm)
 	mux.HandleFunc("GET /submissions/{id}/header", h.getSubmissionHeader)
 	mux.HandleFunc("PUT /submissions/{id}", h.updateSubmission)
 	mux.HandleFunc("POST /submissions/{id}/employees", h.addEmployee)
+	mux.HandleFunc("DELETE /submissions/{id}/employees", h.deleteEmployees)
+	mux.HandleFunc("POST /submissions/{id}/seed-originals", h.seedOriginals)
+	mux.HandleFunc("POST /submissions/{id}/employees/import-rcw", h.importRCW)
+	mux.HandleFunc("POST /submissions/{id}/employees/import", h.importCSV)
+	mux.HandleFunc("GET /submissions/{id}/csv", h.exportCSV)
+	mux.HandleFunc("GET /submissions/{id}/audit", h.auditSubmission)
+	mux.HandleFunc("GET /submissions/{id}/debug", h.debugSubmission)
+	mux.HandleFunc("POST /submissions/{id}/mark-submitted", h.markSubmitted)
+	mux.HandleFunc("POST /submissions/{id}/duplicate", h.duplicateSubmission)
 	mux.HandleFunc("GET /employees/{id}/edit", h.editEmployeeForm)
 	mux.HandleFunc("GET /employees/{id}/card", h.getEmployeeCard)
 	mux.HandleFunc("PUT /employees/{id}", h.updateEmployee)
 	mux.HandleFunc("DELETE /employees/{id}", h.deleteEmployee)
-	mux.HandleFunc("GET /submissions/{id}/generate", h.generateFile)
-	mux.HandleFunc("GET /submissions/{id}/pdf", h.generatePDF)
+	mux.HandleFunc("GET /employees/{id}/pdf", h.withTimeout(h.generateEmployeePDF))
+	mux.HandleFunc("GET /submissions/{id}/generate", h.withTimeout(h.generateFile))
+	mux.HandleFunc("GET /submissions/{id}/manifest", h.withTimeout(h.submissionManifest))
+	mux.HandleFunc("GET /submissions/{id}/preview", h.withTimeout(h.previewFile))
+	mux.HandleFunc("GET /submissions/{id}/pdf", h.withTimeout(h.generatePDF))
+	mux.HandleFunc("POST /submissions/{id}/pdf", h.withTimeout(h.generatePDF))
+	mux.HandleFunc("GET /spec/{year}/ruler.txt", h.specRuler)
+	mux.HandleFunc("GET /spec/diff", h.specDiff)
+	mux.HandleFunc("GET /selftest", h.withTimeout(h.selfTest))
+
+	// JSON API; see api.go.
+	mux.HandleFunc("POST /api/submissions", h.apiCreateSubmission)
+	mux.HandleFunc("POST /api/validate", h.apiValidate)
+	mux.HandleFunc("GET /api/submissions/{id}", h.apiGetSubmission)
+	mux.HandleFunc("GET /api/submissions/{id}/efw2c", h.withTimeout(h.apiGenerate))
+	mux.HandleFunc("POST /api/generate-batch", h.withTimeout(h.apiGenerateBatch))
 	return mux
 }
 
+// index lists the submissions a page (?page=, from 1) at a time, filtered
+// to those matching ?q= (employer name or EIN) when given.
 func (h *Handler) index(w http.ResponseWriter, r *http.Request) {
-	submissions, err := h.repo.ListSubmissions(r.Context())
+	p := domain.SubmissionPage{
+		Query:    strings.TrimSpace(r.URL.Query().Get("q")),
+		PageSize: domain.DefaultPageSize,
+	}
+	p.Page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	p.Page = max(p.Page, 1)
+	var err error
+	p.Submissions, p.Total, err = h.repo.ListSubmissionsPage(r.Context(), p.Query, p.PageSize, (p.Page-1)*p.PageSize)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	render(w, r, templates.Index(submissions, h.gen.SupportedYears()))
+	render(w, r, templates.Index(p, h.gen.SupportedYears(), h.defaultYear()))
 }
 
+// defaultYear is the generator's default tax year as a form value.
+func (h *Handler) defaultYear() string { return strconv.Itoa(h.gen.Year()) }
+
 func (h *Handler) createSubmission(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, err.Error(), 400)
@@ -61,34 +121,57 @@ func (h *Handler) createSubmission(w http.ResponseWriter, r *http.Request) {
 	s := &domain.Submission{
 		Submitter: domain.SubmitterInfo{
 			BSOUID:       r.FormValue("bso_uid"),
+			CompanyName:  r.FormValue("submitter_company_name"),
 			ContactName:  r.FormValue("contact_name"),
 			ContactPhone: stripNonDigits(r.FormValue("contact_phone")),
 			ContactEmail: r.FormValue("contact_email"),
 			PreparerCode: r.FormValue("preparer_code"),
+
+			PhoneExtension: stripNonDigits(r.FormValue("phone_extension")),
+			ContactFax:     stripNonDigits(r.FormValue("contact_fax")),
+
+			AddressLine1: r.FormValue("submitter_addr1"),
+			AddressLine2: r.FormValue("submitter_addr2"),
+			City:         r.FormValue("submitter_city"),
+			State:        r.FormValue("submitter_state"),
+			ZIP:          r.FormValue("submitter_zip"),
+			ZIPExtension: r.FormValue("submitter_zip_ext"),
 		},
 		Employer: domain.EmployerRecord{
-			EmploymentCode: r.FormValue("employment_code"),
-			KindOfEmployer: r.FormValue("kind_of_employer"),
-			ContactName:    r.FormValue("employer_contact_name"),
-			ContactPhone:   stripNonDigits(r.FormValue("employer_contact_phone")),
-			ContactEmail:   r.FormValue("employer_contact_email"),
-			EIN:            stripDashes(r.FormValue("ein")),
-			Name:           r.FormValue("employer_name"),
-			AddressLine1:   r.FormValue("emp_addr1"),
-			AddressLine2:   r.FormValue("emp_addr2"),
-			City:           r.FormValue("emp_city"),
-			State:          r.FormValue("emp_state"),
-			ZIP:            r.FormValue("emp_zip"),
-			ZIPExtension:   r.FormValue("emp_zip_ext"),
-			AgentIndicator: "0",
-			TaxYear:        r.FormValue("tax_year"),
+			EmploymentCode:        r.FormValue("employment_code"),
+			KindOfEmployer:        r.FormValue("kind_of_employer"),
+			ContactName:           r.FormValue("employer_contact_name"),
+			ContactPhone:          stripNonDigits(r.FormValue("employer_contact_phone")),
+			ContactPhoneExtension: stripNonDigits(r.FormValue("employer_contact_phone_ext")),
+			ContactEmail:          r.FormValue("employer_contact_email"),
+			EIN:                   stripDashes(r.FormValue("ein")),
+			Name:                  r.FormValue("employer_name"),
+			AddressLine1:          r.FormValue("emp_addr1"),
+			AddressLine2:          r.FormValue("emp_addr2"),
+			City:                  r.FormValue("emp_city"),
+			State:                 r.FormValue("emp_state"),
+			ZIP:                   r.FormValue("emp_zip"),
+			ZIPExtension:          r.FormValue("emp_zip_ext"),
+			AgentIndicator:        "0",
+			TaxYear:               r.FormValue("tax_year"),
+
+			OriginalThirdPartySick: indicator(r.FormValue("orig_third_party_sick")),
+			CorrectThirdPartySick:  indicator(r.FormValue("corr_third_party_sick")),
+
+			ForeignStateProvince: r.FormValue("emp_foreign_province"),
+			ForeignPostalCode:    r.FormValue("emp_foreign_postal"),
+			CountryCode:          countryCode(r.FormValue("emp_country_code")),
 		},
 		Notes: r.FormValue("notes"),
 	}
-	// Validate: if the submitted year isn't supported, fall back to default.
+	s.Submitter.ResubIndicator, s.Submitter.ResubWFID = resubmission(r)
+	s.Submitter.SoftwareCode, s.Submitter.SoftwareVendorCode = software(r)
 	if s.Employer.TaxYear == "" {
-		supported := h.gen.SupportedYears()
-		s.Employer.TaxYear = supported[len(supported)-1].Year
+		s.Employer.TaxYear = h.defaultYear()
+	}
+	if msg := nineDigitsProblem("EIN", s.Employer.EIN, true); msg != "" {
+		http.Error(w, msg, 400)
+		return
 	}
 	if err := h.repo.CreateSubmission(r.Context(), s); err != nil {
 		http.Error(w, err.Error(), 500)
@@ -109,7 +192,299 @@ func (h *Handler) viewSubmission(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	render(w, r, templates.Detail(s))
+	priors, err := h.priorSubmissions(r.Context(), s)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	sortEmployees(s.Employees, r.URL.Query().Get("sort"))
+	render(w, r, templates.Detail(s, h.gen.Check(s), priors))
+}
+
+// sortEmployees orders employees for display by ?sort=: "name" (last,
+// first, middle), "ssn" or "created". Anything else keeps id order. Sort
+// before Check, whose warnings refer to employees by index.
+func sortEmployees(employees []domain.EmployeeRecord, by string) {
+	var compare func(a, b domain.EmployeeRecord) int
+	switch by {
+	case "name":
+		compare = func(a, b domain.EmployeeRecord) int {
+			return cmp.Or(
+				strings.Compare(strings.ToUpper(a.LastName), strings.ToUpper(b.LastName)),
+				strings.Compare(strings.ToUpper(a.FirstName), strings.ToUpper(b.FirstName)),
+				strings.Compare(strings.ToUpper(a.MiddleName), strings.ToUpper(b.MiddleName)),
+			)
+		}
+	case "ssn":
+		compare = func(a, b domain.EmployeeRecord) int { return strings.Compare(a.SSN, b.SSN) }
+	case "created":
+		compare = func(a, b domain.EmployeeRecord) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	default:
+		return
+	}
+	slices.SortStableFunc(employees, func(a, b domain.EmployeeRecord) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// auditSubmission renders the pre-submission audit checklist: everything
+// AccuWage would flag, so it can be fixed before the file is generated.
+func (h *Handler) auditSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	render(w, r, templates.Audit(s, h.gen.Audit(s)))
+}
+
+// debugSubmission renders the generated file as a table of records split
+// into their spec fields, to find the field at a position SSA rejected.
+func (h *Handler) debugSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	dumps, err := h.gen.Annotate(s)
+	if err != nil {
+		var verrs domain.ValidationErrors
+		if errors.As(err, &verrs) {
+			validationError(w, verrs)
+			return
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.RecordDebug(s, dumps))
+}
+
+// previewFile handles GET /submissions/{id}/preview: the generated file as
+// inline plain text for reading in a browser tab. With ?ruler=1 each record
+// is put on its own line under a column ruler. Nothing is recorded, so it
+// can be reloaded freely.
+func (h *Handler) previewFile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	if errs := h.gen.Validate(s); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.gen.Generate(r.Context(), s, &buf); err != nil {
+		var verrs domain.ValidationErrors
+		if errors.As(err, &verrs) {
+			validationError(w, verrs)
+			return
+		}
+		generationError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if r.URL.Query().Get("ruler") != "1" {
+		w.Write(buf.Bytes())
+		return
+	}
+	ruler := columnRuler(spec.RecordLen)
+	for rec := range slices.Chunk(buf.Bytes(), spec.RecordLen) {
+		fmt.Fprintf(w, "%s\n%s\n", ruler, rec)
+	}
+}
+
+// columnRuler returns an n-column position ruler: the tens digit at every
+// tenth column, "+" at every fifth and "-" elsewhere.
+func columnRuler(n int) string {
+	var b strings.Builder
+	for pos := 1; pos <= n; pos++ {
+		switch {
+		case pos%10 == 0:
+			b.WriteByte('0' + byte(pos/10%10))
+		case pos%5 == 0:
+			b.WriteByte('+')
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// duplicateSubmission handles POST /submissions/{id}/duplicate: it saves a
+// copy of the submission and its employees as a new draft and redirects to
+// it.
+func (h *Handler) duplicateSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	c := s.Clone()
+	employees := c.Employees
+	c.Employees = nil
+	if err := h.repo.CreateSubmission(r.Context(), c); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if err := h.repo.AddEmployees(r.Context(), c.ID, employees); err != nil {
+		// AddEmployees saved none of them; do not leave an empty copy.
+		if derr := h.repo.DeleteSubmission(r.Context(), c.ID); derr != nil {
+			slog.Error("removing failed duplicate", "submission", c.ID, "err", derr)
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/submissions/%d", c.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// importEFW2C handles POST /submissions/import-efw2c: it parses an uploaded
+// EFW2C file (form field "file"), saves it as a new draft marked as a
+// resubmission and redirects to it. Files without an RCE or RCW are
+// refused, as are files with more than one RCE, since only the first
+// employer block would be kept.
+func (h *Handler) importEFW2C(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	f, hdr, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "EFW2C upload: "+err.Error(), 400)
+		return
+	}
+	defer f.Close()
+	s, err := h.gen.Parse(f)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	switch {
+	case s.Employer == (domain.EmployerRecord{}):
+		http.Error(w, "file has no RCE record", 400)
+		return
+	case len(s.Employees) == 0:
+		http.Error(w, "file has no RCW records", 400)
+		return
+	case len(s.AdditionalEmployers) > 0:
+		http.Error(w, fmt.Sprintf("file has %d employers; import one employer per file", len(s.AdditionalEmployers)+1), 400)
+		return
+	}
+	s.Submitter.ResubIndicator = "1"
+	s.Notes = "Imported from " + hdr.Filename
+	employees := s.Employees
+	s.Employees = nil
+	if err := h.repo.CreateSubmission(r.Context(), s); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if err := h.repo.AddEmployees(r.Context(), s.ID, employees); err != nil {
+		// AddEmployees saved none of them; do not leave an empty draft.
+		if derr := h.repo.DeleteSubmission(r.Context(), s.ID); derr != nil {
+			slog.Error("removing failed EFW2C import", "submission", s.ID, "err", derr)
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/submissions/%d", s.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// markSubmitted handles POST /submissions/{id}/mark-submitted: it records
+// that the file was filed with SSA and renders the updated header.
+func (h *Handler) markSubmitted(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	if _, err := h.repo.GetSubmission(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	if err := h.repo.MarkSubmitted(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.SubmissionHeader(s))
+}
+
+// filedHeader flags a download of a submission already marked submitted,
+// so a re-download is not mistaken for a new file to upload.
+func filedHeader(w http.ResponseWriter, s *domain.Submission) {
+	if s.SubmittedAt != nil {
+		w.Header().Set("X-W2C-Submitted", "re-download of submission filed "+s.SubmittedAt.Format("2006-01-02"))
+	}
+}
+
+// priorSubmissions lists the other submissions for s's employer (same EIN).
+func (h *Handler) priorSubmissions(ctx context.Context, s *domain.Submission) ([]domain.Submission, error) {
+	return h.repo.ListSubmissionsByEIN(ctx, s.Employer.EIN, s.ID)
+}
+
+// seedOriginals handles POST /submissions/{id}/seed-originals: every
+// employee whose SSN also appears on the prior submission (prior_id) gets
+// that submission's corrected amounts as its originals. Renders the
+// updated employee list.
+func (h *Handler) seedOriginals(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	priorID, err := strconv.ParseInt(r.FormValue("prior_id"), 10, 64)
+	if err != nil || priorID == id {
+		http.Error(w, "invalid prior_id", 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	prior, err := h.repo.GetSubmission(r.Context(), priorID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if prior.Employer.EIN != s.Employer.EIN {
+		http.Error(w, "prior submission is for a different employer", 400)
+		return
+	}
+	for _, i := range s.SeedOriginalsFrom(prior) {
+		if err := h.repo.UpdateEmployee(r.Context(), &s.Employees[i]); err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+	}
+	render(w, r, templates.EmployeeList(s, h.gen.Check(s)))
 }
 
 // editSubmissionForm renders the inline edit form for the submission header.
@@ -160,10 +535,21 @@ func (h *Handler) updateSubmission(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s.Submitter.BSOUID = r.FormValue("bso_uid")
+	s.Submitter.CompanyName = r.FormValue("submitter_company_name")
 	s.Submitter.ContactName = r.FormValue("contact_name")
 	s.Submitter.ContactPhone = stripNonDigits(r.FormValue("contact_phone"))
+	s.Submitter.PhoneExtension = stripNonDigits(r.FormValue("phone_extension"))
+	s.Submitter.ContactFax = stripNonDigits(r.FormValue("contact_fax"))
 	s.Submitter.ContactEmail = r.FormValue("contact_email")
 	s.Submitter.PreparerCode = r.FormValue("preparer_code")
+	s.Submitter.ResubIndicator, s.Submitter.ResubWFID = resubmission(r)
+	s.Submitter.SoftwareCode, s.Submitter.SoftwareVendorCode = software(r)
+	s.Submitter.AddressLine1 = r.FormValue("submitter_addr1")
+	s.Submitter.AddressLine2 = r.FormValue("submitter_addr2")
+	s.Submitter.City = r.FormValue("submitter_city")
+	s.Submitter.State = r.FormValue("submitter_state")
+	s.Submitter.ZIP = r.FormValue("submitter_zip")
+	s.Submitter.ZIPExtension = r.FormValue("submitter_zip_ext")
 	s.Employer.EIN = stripDashes(r.FormValue("ein"))
 	s.Employer.Name = r.FormValue("employer_name")
 	s.Employer.AddressLine1 = r.FormValue("emp_addr1")
@@ -172,16 +558,25 @@ func (h *Handler) updateSubmission(w http.ResponseWriter, r *http.Request) {
 	s.Employer.State = r.FormValue("emp_state")
 	s.Employer.ZIP = r.FormValue("emp_zip")
 	s.Employer.ZIPExtension = r.FormValue("emp_zip_ext")
+	s.Employer.ForeignStateProvince = r.FormValue("emp_foreign_province")
+	s.Employer.ForeignPostalCode = r.FormValue("emp_foreign_postal")
+	s.Employer.CountryCode = countryCode(r.FormValue("emp_country_code"))
 	s.Employer.EmploymentCode = r.FormValue("employment_code")
 	s.Employer.KindOfEmployer = r.FormValue("kind_of_employer")
+	s.Employer.OriginalThirdPartySick = indicator(r.FormValue("orig_third_party_sick"))
+	s.Employer.CorrectThirdPartySick = indicator(r.FormValue("corr_third_party_sick"))
 	s.Employer.ContactName = r.FormValue("employer_contact_name")
 	s.Employer.ContactPhone = stripNonDigits(r.FormValue("employer_contact_phone"))
+	s.Employer.ContactPhoneExtension = stripNonDigits(r.FormValue("employer_contact_phone_ext"))
 	s.Employer.ContactEmail = r.FormValue("employer_contact_email")
 	s.Employer.TaxYear = r.FormValue("tax_year")
 	s.Notes = r.FormValue("notes")
 	if s.Employer.TaxYear == "" {
-		supported := h.gen.SupportedYears()
-		s.Employer.TaxYear = supported[len(supported)-1].Year
+		s.Employer.TaxYear = h.defaultYear()
+	}
+	if msg := nineDigitsProblem("EIN", s.Employer.EIN, true); msg != "" {
+		http.Error(w, msg, 400)
+		return
 	}
 	if err := h.repo.UpdateSubmission(r.Context(), s); err != nil {
 		http.Error(w, err.Error(), 500)
@@ -215,6 +610,10 @@ func (h *Handler) addEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	e := parseEmployeeForm(r)
+	if msg := employeeSSNProblem(e); msg != "" {
+		http.Error(w, msg, 400)
+		return
+	}
 	if err := h.repo.AddEmployee(r.Context(), subID, e); err != nil {
 		http.Error(w, err.Error(), 500)
 		return
@@ -224,7 +623,44 @@ func (h *Handler) addEmployee(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	render(w, r, templates.EmployeeList(s))
+	render(w, r, templates.EmployeeList(s, h.gen.Check(s)))
+}
+
+// importRCW adds the employees decoded from pasted RCW (RCO, RCS) records,
+// e.g. cut from a rejected file, so they can be fixed and resubmitted. The
+// records are read with the submission's tax-year layout; nothing is added
+// if any record fails to parse.
+func (h *Handler) importRCW(w http.ResponseWriter, r *http.Request) {
+	subID, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), subID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	year, _ := strconv.Atoi(s.Employer.TaxYear)
+	emps, err := h.gen.ParseEmployees(strings.NewReader(r.FormValue("records")), year)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.repo.AddEmployees(r.Context(), subID, emps); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s, err = h.repo.GetSubmission(r.Context(), subID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.EmployeeList(s, h.gen.Check(s)))
 }
 
 // editEmployeeForm renders the inline edit form for a single employee card.
@@ -254,7 +690,12 @@ func (h *Handler) getEmployeeCard(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	render(w, r, templates.EmployeeCard(*e, e.SubmissionID))
+	warnings, err := h.employeeWarnings(r.Context(), e)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.EmployeeCard(*e, e.SubmissionID, warnings))
 }
 
 // updateEmployee handles PUT /employees/{id} and renders the updated card.
@@ -275,6 +716,10 @@ func (h *Handler) updateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	e := parseEmployeeForm(r)
+	if msg := employeeSSNProblem(e); msg != "" {
+		http.Error(w, msg, 400)
+		return
+	}
 	e.ID = existing.ID
 	e.SubmissionID = existing.SubmissionID
 	e.CreatedAt = existing.CreatedAt
@@ -282,7 +727,12 @@ func (h *Handler) updateEmployee(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	render(w, r, templates.EmployeeCard(*e, e.SubmissionID))
+	warnings, err := h.employeeWarnings(r.Context(), e)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.EmployeeCard(*e, e.SubmissionID, warnings))
 }
 
 // parseEmployeeForm reads all employee correction fields from an HTTP form request
@@ -305,16 +755,19 @@ func parseEmployeeForm(r *http.Request) *domain.EmployeeRecord {
 		LastName:    r.FormValue("last_name"),
 		Suffix:      r.FormValue("suffix"),
 		// Name correction fields (only populated when correcting a previously wrong name)
-		OriginalFirstName:  r.FormValue("orig_first_name"),
-		OriginalMiddleName: r.FormValue("orig_middle_name"),
-		OriginalLastName:   r.FormValue("orig_last_name"),
-		OriginalSuffix:     r.FormValue("orig_suffix"),
-		AddressLine1: r.FormValue("emp_addr1"),
-		AddressLine2: r.FormValue("emp_addr2"),
-		City:         r.FormValue("emp_city"),
-		State:        r.FormValue("emp_state"),
-		ZIP:          r.FormValue("emp_zip"),
-		ZIPExtension: r.FormValue("emp_zip_ext"),
+		OriginalFirstName:     r.FormValue("orig_first_name"),
+		OriginalMiddleName:    r.FormValue("orig_middle_name"),
+		OriginalLastName:      r.FormValue("orig_last_name"),
+		OriginalSuffix:        r.FormValue("orig_suffix"),
+		AddressLine1:          r.FormValue("emp_addr1"),
+		AddressLine2:          r.FormValue("emp_addr2"),
+		City:                  r.FormValue("emp_city"),
+		State:                 r.FormValue("emp_state"),
+		ZIP:                   r.FormValue("emp_zip"),
+		ZIPExtension:          r.FormValue("emp_zip_ext"),
+		ForeignStateProvince:  r.FormValue("emp_foreign_province"),
+		ForeignPostalCode:     r.FormValue("emp_foreign_postal"),
+		CountryCode:           countryCode(r.FormValue("emp_country_code")),
 		OriginalStateCode:     strings.ToUpper(strings.TrimSpace(r.FormValue("orig_state_code"))),
 		CorrectStateCode:      strings.ToUpper(strings.TrimSpace(r.FormValue("corr_state_code"))),
 		OriginalStateIDNumber: r.FormValue("orig_state_id"),
@@ -340,6 +793,24 @@ func parseEmployeeForm(r *http.Request) *domain.EmployeeRecord {
 			// Box 8 — Allocated Tips
 			OriginalAllocatedTips: parseCents(r.FormValue("orig_alloc_tips")),
 			CorrectAllocatedTips:  parseCents(r.FormValue("corr_alloc_tips")),
+			// Box 12 Codes A & B — Uncollected tax on tips
+			OriginalUncollectedEETax: parseCents(r.FormValue("orig_uncoll_tips_tax")),
+			CorrectUncollectedEETax:  parseCents(r.FormValue("corr_uncoll_tips_tax")),
+			// Box 12 codes carried on the RCO record (R, S, T, M, N, Z)
+			OriginalCodeR_MSA:       parseCents(r.FormValue("orig_code_r")),
+			CorrectCodeR_MSA:        parseCents(r.FormValue("corr_code_r")),
+			OriginalCodeS_SIMPLE:    parseCents(r.FormValue("orig_code_s")),
+			CorrectCodeS_SIMPLE:     parseCents(r.FormValue("corr_code_s")),
+			OriginalCodeT_Adoption:  parseCents(r.FormValue("orig_code_t")),
+			CorrectCodeT_Adoption:   parseCents(r.FormValue("corr_code_t")),
+			OriginalCodeM_UncollSS:  parseCents(r.FormValue("orig_code_m")),
+			CorrectCodeM_UncollSS:   parseCents(r.FormValue("corr_code_m")),
+			OriginalCodeN_UncollMed: parseCents(r.FormValue("orig_code_n")),
+			CorrectCodeN_UncollMed:  parseCents(r.FormValue("corr_code_n")),
+			OriginalCodeZ_409A:      parseCents(r.FormValue("orig_code_z")),
+			CorrectCodeZ_409A:       parseCents(r.FormValue("corr_code_z")),
+			OriginalMedicaidWaiver:  parseCents(r.FormValue("orig_code_ii")),
+			CorrectMedicaidWaiver:   parseCents(r.FormValue("corr_code_ii")),
 			// Box 10 — Dependent Care Benefits
 			OriginalDependentCare: parseCents(r.FormValue("orig_dep_care")),
 			CorrectDependentCare:  parseCents(r.FormValue("corr_dep_care")),
@@ -363,6 +834,21 @@ func parseEmployeeForm(r *http.Request) *domain.EmployeeRecord {
 			CorrectCodeBB_Roth403b:   parseCents(r.FormValue("corr_code_bb")),
 			OriginalCodeDD_EmpHealth: parseCents(r.FormValue("orig_code_dd")),
 			CorrectCodeDD_EmpHealth:  parseCents(r.FormValue("corr_code_dd")),
+			// Further Box 12 codes (C, F, H, Q, V, Y, FF)
+			OriginalCodeC_GroupTermLife: parseCents(r.FormValue("orig_code_c")),
+			CorrectCodeC_GroupTermLife:  parseCents(r.FormValue("corr_code_c")),
+			OriginalCodeF_SARSEP:        parseCents(r.FormValue("orig_code_f")),
+			CorrectCodeF_SARSEP:         parseCents(r.FormValue("corr_code_f")),
+			OriginalCodeH_501c18D:       parseCents(r.FormValue("orig_code_h")),
+			CorrectCodeH_501c18D:        parseCents(r.FormValue("corr_code_h")),
+			OriginalCodeQ_CombatPay:     parseCents(r.FormValue("orig_code_q")),
+			CorrectCodeQ_CombatPay:      parseCents(r.FormValue("corr_code_q")),
+			OriginalCodeV_NSO:           parseCents(r.FormValue("orig_code_v")),
+			CorrectCodeV_NSO:            parseCents(r.FormValue("corr_code_v")),
+			OriginalCodeY_409A:          parseCents(r.FormValue("orig_code_y")),
+			CorrectCodeY_409A:           parseCents(r.FormValue("corr_code_y")),
+			OriginalCodeFF_QSEHRA:       parseCents(r.FormValue("orig_code_ff")),
+			CorrectCodeFF_QSEHRA:        parseCents(r.FormValue("corr_code_ff")),
 			// Boxes 16–19 — State / Local
 			OriginalStateWages:     parseCents(r.FormValue("orig_state_wages")),
 			CorrectStateWages:      parseCents(r.FormValue("corr_state_wages")),
@@ -403,12 +889,32 @@ func (h *Handler) deleteEmployee(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), 500)
 			return
 		}
-		render(w, r, templates.EmployeeList(s))
+		render(w, r, templates.EmployeeList(s, h.gen.Check(s)))
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
 
+// deleteEmployees removes every employee of the submission, e.g. before
+// re-importing a corrected CSV, and re-renders the empty list.
+func (h *Handler) deleteEmployees(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	if err := h.repo.DeleteEmployeesBySubmission(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s, err := h.repo.GetSubmission(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	render(w, r, templates.EmployeeList(s, h.gen.Check(s)))
+}
+
 func (h *Handler) generateFile(w http.ResponseWriter, r *http.Request) {
 	id, err := pathID(r, "id")
 	if err != nil {
@@ -424,15 +930,55 @@ func (h *Handler) generateFile(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "no employees in submission", 400)
 		return
 	}
-	var buf bytes.Buffer
-	if err := h.gen.Generate(context.Background(), s, &buf); err != nil {
-		http.Error(w, err.Error(), 500)
+	if errs := h.gen.Validate(s); len(errs) > 0 {
+		validationError(w, errs)
 		return
 	}
+	// ?disposition=inline shows the file in the browser for a quick look;
+	// previews are not counted as downloads.
+	disposition := "attachment"
+	if r.URL.Query().Get("disposition") == "inline" {
+		disposition = "inline"
+	}
+	// The file streams straight to the client. Headers go out with the
+	// first record, so until then a failure can still be a clean error.
 	filename := fmt.Sprintf("W2C_%s_%s.txt", s.Employer.EIN, time.Now().Format("20060102"))
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
-	w.Write(buf.Bytes())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, filename))
+	filedHeader(w, s)
+	cw := &countingWriter{w: w}
+	res, err := h.gen.GenerateResult(r.Context(), s, cw)
+	if err != nil {
+		if cw.n == 0 {
+			w.Header().Del("Content-Disposition")
+			generationError(w, err)
+			return
+		}
+		// Too late for an error status: abort the response so the client
+		// sees a failed transfer rather than a short file.
+		slog.Error("EFW2C stream failed", "submission", id, "bytes", cw.n, "err", err)
+		panic(http.ErrAbortHandler)
+	}
+	slog.Info("generated EFW2C file", "submission", id, "records", res.RecordCount,
+		"rcw", res.RCWCount, "bytes", res.ByteCount, "warnings", len(res.Warnings), "truncations", len(res.Truncations))
+	if disposition == "attachment" {
+		if err := h.repo.IncrementGenerateCount(r.Context(), id); err != nil {
+			slog.Error("count EFW2C download", "submission", id, "err", err)
+		}
+	}
+}
+
+// countingWriter counts the bytes written through it, so a handler knows
+// whether a streamed response has started.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
 }
 
 func (h *Handler) generatePDF(w http.ResponseWriter, r *http.Request) {
@@ -450,17 +996,232 @@ func (h *Handler) generatePDF(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "no employees in submission", 400)
 		return
 	}
+	// A password is only accepted in a POST body, never the query string,
+	// so it stays out of access logs and browser history.
+	var opts []pdf.Option
+	if pw := r.PostFormValue("pdf_password"); pw != "" {
+		opts = append(opts, pdf.WithPassword(pw, ""))
+	}
+	// ?maskSSN=true produces a distribution copy showing only the last four
+	// SSN digits.
+	if mask, _ := strconv.ParseBool(r.URL.Query().Get("maskSSN")); mask {
+		opts = append(opts, pdf.WithMaskedSSN())
+	}
+	// Unlike the EFW2C file, the PDF is buffered: the PDF library lays out
+	// the whole document (and encrypts it, with a password) in memory
+	// before its Output writes anything, so streaming would save nothing
+	// and would lose the clean 500/504 on a failed build.
+	// TODO: stream once reports routinely outgrow memory; that needs a
+	// page-at-a-time PDF writer.
+	// ?layout=form draws Copy B of the IRS form, one page per employee,
+	// instead of the report.
+	build, kind := pdf.GeneratePDF, "report"
+	if r.URL.Query().Get("layout") == "form" {
+		build, kind = pdf.GenerateW2cFormPDF, "form"
+	}
 	var buf bytes.Buffer
-	if err := pdf.GeneratePDF(s, &buf); err != nil {
+	if err := build(r.Context(), s, &buf, opts...); err != nil {
+		generationError(w, err)
+		return
+	}
+	filename := fmt.Sprintf("W2C_%s_%s_%s.pdf", s.Employer.EIN, time.Now().Format("20060102"), kind)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	filedHeader(w, s)
+	w.Write(buf.Bytes())
+}
+
+// generateEmployeePDF serves a single employee's page of the PDF report,
+// for handing one corrected W-2c to that employee. ?maskSSN=true works as
+// for the full report.
+func (h *Handler) generateEmployeePDF(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		http.Error(w, "invalid id", 400)
+		return
+	}
+	e, err := h.repo.GetEmployee(r.Context(), id)
+	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	filename := fmt.Sprintf("W2C_%s_%s_report.pdf", s.Employer.EIN, time.Now().Format("20060102"))
+	s, err := h.repo.GetSubmission(r.Context(), e.SubmissionID)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	var opts []pdf.Option
+	if mask, _ := strconv.ParseBool(r.URL.Query().Get("maskSSN")); mask {
+		opts = append(opts, pdf.WithMaskedSSN())
+	}
+	var buf bytes.Buffer
+	if err := pdf.GenerateEmployeePDF(s, e, &buf, opts...); err != nil {
+		generationError(w, err)
+		return
+	}
+	filename := fmt.Sprintf("W2C_%s_%s.pdf", s.Employer.EIN, filenamePart(e.LastName))
 	w.Header().Set("Content-Type", "application/pdf")
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
 	w.Write(buf.Bytes())
 }
 
+// filenamePart reduces s to the letters, digits and hyphens that are safe in
+// a Content-Disposition filename, uppercased; "employee" when none remain.
+func filenamePart(s string) string {
+	part := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return -1
+	}, strings.ToUpper(s))
+	if part == "" {
+		return "employee"
+	}
+	return part
+}
+
+// withTimeout runs next under a context that expires after h.timeout.
+// A deadline that passes before any output is written surfaces as an
+// error; generationError turns that into a 504. (The PDF is buffered, so
+// that is always the case for it; a streamed EFW2C file that is cut off
+// partway is aborted instead.)
+func (h *Handler) withTimeout(next http.HandlerFunc) http.HandlerFunc {
+	if h.timeout <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+		defer cancel()
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// generationError reports a failed EFW2C or PDF build: 504 when the request
+// deadline passed, 500 otherwise.
+func generationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "generation timed out", http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, err.Error(), 500)
+}
+
+// validationError answers 400 with one problem per line, each prefixed
+// with its machine-readable code.
+func validationError(w http.ResponseWriter, errs domain.ValidationErrors) {
+	var b strings.Builder
+	b.WriteString("submission failed validation:\n")
+	for _, e := range errs {
+		fmt.Fprintf(&b, "[%s] %s\n", e.Code, e.Error())
+	}
+	http.Error(w, b.String(), 400)
+}
+
+// specRuler handles GET /spec/{year}/ruler.txt: a plain-text field-position
+// map for the year, for manual verification against Pub 42-014.
+func (h *Handler) specRuler(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil {
+		http.Error(w, "invalid year", 400)
+		return
+	}
+	ys, ok := spec.ForYear(year)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no spec for TY%d", year), 404)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := ys.WriteRuler(w); err != nil {
+		http.Error(w, err.Error(), 500)
+	}
+}
+
+// specDiff handles GET /spec/diff?from=2023&to=2024: the fields added,
+// removed or moved between two years' layouts, as plain text, for checking
+// a new publication against the last one.
+func (h *Handler) specDiff(w http.ResponseWriter, r *http.Request) {
+	var years [2]int
+	for i, key := range []string{"from", "to"} {
+		y, err := strconv.Atoi(r.URL.Query().Get(key))
+		if err != nil {
+			http.Error(w, "invalid "+key+" year", 400)
+			return
+		}
+		if _, ok := spec.ForYear(y); !ok {
+			http.Error(w, fmt.Sprintf("no spec for TY%d", y), 404)
+			return
+		}
+		years[i] = y
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := spec.WriteDiff(w, years[0], years[1], spec.Diff(years[0], years[1])); err != nil {
+		http.Error(w, err.Error(), 500)
+	}
+}
+
+// selfTest is a post-deploy smoke check: it generates the minimal fixture
+// submission for the latest supported tax year, parses the file back and
+// regenerates it. 200 means the spec, generator and parser agree byte for
+// byte; anything else is a 500 naming the first record that differs.
+func (h *Handler) selfTest(w http.ResponseWriter, r *http.Request) {
+	years := h.gen.SupportedYears()
+	if len(years) == 0 {
+		http.Error(w, "selftest: generator supports no tax years", 500)
+		return
+	}
+	s := fixtures.Minimal()
+	s.Employer.TaxYear = years[len(years)-1].Year
+
+	var want bytes.Buffer
+	if err := h.gen.Generate(r.Context(), s, &want); err != nil {
+		generationError(w, fmt.Errorf("selftest: generate: %w", err))
+		return
+	}
+	parsed, err := h.gen.Parse(bytes.NewReader(want.Bytes()))
+	if err != nil {
+		http.Error(w, "selftest: parse: "+err.Error(), 500)
+		return
+	}
+	var got bytes.Buffer
+	if err := h.gen.Generate(r.Context(), parsed, &got); err != nil {
+		generationError(w, fmt.Errorf("selftest: regenerate: %w", err))
+		return
+	}
+
+	n := want.Len() / spec.RecordLen
+	if !bytes.Equal(want.Bytes(), got.Bytes()) {
+		msg := fmt.Sprintf("selftest: round trip mismatch (%d bytes in, %d bytes out)", want.Len(), got.Len())
+		for i := 0; i < n && (i+1)*spec.RecordLen <= got.Len(); i++ {
+			lo, hi := i*spec.RecordLen, (i+1)*spec.RecordLen
+			if !bytes.Equal(want.Bytes()[lo:hi], got.Bytes()[lo:hi]) {
+				msg += fmt.Sprintf(": record %d (%s) differs", i+1, want.Bytes()[lo:lo+3])
+				break
+			}
+		}
+		http.Error(w, msg, 500)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "selftest ok: TY%s, %d records (%d bytes) round-tripped\n", s.Employer.TaxYear, n, want.Len())
+}
+
+// employeeWarnings returns the reconciliation warnings for a single saved
+// employee. The parent submission is loaded so checks resolve against the
+// right tax year.
+func (h *Handler) employeeWarnings(ctx context.Context, e *domain.EmployeeRecord) ([]domain.Warning, error) {
+	s, err := h.repo.GetSubmission(ctx, e.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.Employees {
+		if s.Employees[i].ID == e.ID {
+			return domain.WarningsFor(h.gen.Check(s), i), nil
+		}
+	}
+	return nil, nil
+}
+
 // render writes a templ component to the response.
 func render(w http.ResponseWriter, r *http.Request, c templ.Component) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -477,6 +1238,29 @@ func stripDashes(s string) string {
 	return strings.ReplaceAll(s, "-", "")
 }
 
+// nineDigitsProblem describes why an EIN or SSN, its dashes already
+// stripped, is not exactly nine digits, or returns "". Blank is allowed
+// unless required. Stopping it here matters: cleanDigits would zero-pad a
+// short value into a different, valid-looking number. Generator.Validate
+// checks the digits themselves.
+func nineDigitsProblem(label, v string, required bool) string {
+	if v == "" && !required {
+		return ""
+	}
+	if len(v) != 9 || stripNonDigits(v) != v {
+		return fmt.Sprintf("%s must be 9 digits, got %q", label, v)
+	}
+	return ""
+}
+
+// employeeSSNProblem is nineDigitsProblem for e's SSN and original SSN.
+func employeeSSNProblem(e *domain.EmployeeRecord) string {
+	if msg := nineDigitsProblem("SSN", e.SSN, true); msg != "" {
+		return msg
+	}
+	return nineDigitsProblem("Original SSN", e.OriginalSSN, false)
+}
+
 func stripNonDigits(s string) string {
 	var b strings.Builder
 	for _, r := range s {
@@ -487,22 +1271,78 @@ func stripNonDigits(s string) string {
 	return b.String()
 }
 
+// countryCode normalises a form's two-letter country code. Blank and "US"
+// both mean a domestic address.
+func countryCode(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// resubmission reads the RCA resubmission fields. The WFID identifies the
+// rejected file being replaced, so it is dropped unless the indicator is 1.
+func resubmission(r *http.Request) (indicator, wfid string) {
+	if r.FormValue("resub_indicator") != "1" {
+		return "0", ""
+	}
+	return "1", strings.ToUpper(strings.TrimSpace(r.FormValue("resub_wfid")))
+}
+
+// software reads the RCA software fields. The vendor code only applies to
+// off-the-shelf software (99), so it is dropped for any other code.
+func software(r *http.Request) (code, vendor string) {
+	code = strings.TrimSpace(r.FormValue("software_code"))
+	if code != "99" {
+		return code, ""
+	}
+	return code, stripNonDigits(r.FormValue("software_vendor_code"))
+}
+
+// indicator keeps a "0"/"1" form value and blanks anything else.
+func indicator(s string) string {
+	if s = strings.TrimSpace(s); s == "0" || s == "1" {
+		return s
+	}
+	return ""
+}
+
+// parseCents reads a form's dollar amount leniently: blank or garbage is
+// zero, and more than two decimals round half-up (away from zero for
+// negatives) to the cent, so "1234.995" is 123500. Input that must not be
+// rounded silently goes through ParseCentsStrict instead.
 func parseCents(s string) int64 {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return 0
 	}
-	parts := strings.SplitN(s, ".", 2)
-	dollars, _ := strconv.ParseInt(parts[0], 10, 64)
-	var cents int64
-	if len(parts) == 2 {
-		c := parts[1]
-		if len(c) == 1 {
-			c += "0"
-		} else if len(c) > 2 {
-			c = c[:2]
-		}
-		cents, _ = strconv.ParseInt(c, 10, 64)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		return -parseCents(rest)
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	dollars, _ := strconv.ParseInt(whole, 10, 64)
+	frac += "000"
+	cents, _ := strconv.ParseInt(frac[:2], 10, 64)
+	if frac[2] >= '5' && frac[2] <= '9' {
+		cents++
 	}
 	return dollars*100 + cents
 }
+
+var decimalRE = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,2})?$`)
+
+// ParseCentsStrict is parseCents for untrusted input such as the JSON API
+// and CSV imports: it rejects anything but an optionally negative dollar
+// amount with up to two decimals, so a sub-cent value like "1234.999" is
+// an error rather than a rounding decision made for the caller. Blank is
+// zero.
+func ParseCentsStrict(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if !decimalRE.MatchString(s) {
+		if _, frac, ok := strings.Cut(s, "."); ok && len(frac) > 2 && strings.Trim(frac, "0123456789") == "" {
+			return 0, fmt.Errorf("invalid money amount %q: more than 2 decimal places; round to the cent", s)
+		}
+		return 0, fmt.Errorf("invalid money amount %q: want dollars with up to 2 decimals", s)
+	}
+	return parseCents(s), nil
+}
@@ -0,0 +1,1293 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csg33k/w2c-generator/internal/adapters/efw2c"
+	"github.com/csg33k/w2c-generator/internal/domain"
+	"github.com/csg33k/w2c-generator/internal/handlers"
+	"github.com/csg33k/w2c-generator/internal/ports"
+)
+
+// ---------------------------------------------------------------------------
+// In-memory repository
+// ---------------------------------------------------------------------------
+
+// memRepo is a minimal ports.SubmissionRepository backed by maps.
+type memRepo struct {
+	mu          sync.Mutex
+	nextSubID   int64
+	nextEmpID   int64
+	submissions map[int64]*domain.Submission
+
+	// failSSN, when set, makes AddEmployees and UpsertEmployees fail on a
+	// batch holding that SSN, saving nothing.
+	failSSN string
+	// getErr, when set, is returned by every GetSubmission call.
+	getErr error
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{submissions: map[int64]*domain.Submission{}}
+}
+
+func (m *memRepo) CreateSubmission(_ context.Context, s *domain.Submission) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextSubID++
+	s.ID = m.nextSubID
+	cp := *s
+	cp.Employees = nil
+	m.submissions[s.ID] = &cp
+	return nil
+}
+
+func (m *memRepo) GetSubmission(_ context.Context, id int64) (*domain.Submission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	s, ok := m.submissions[id]
+	if !ok {
+		return nil, fmt.Errorf("submission %d: %w", id, ports.ErrNotFound)
+	}
+	cp := *s
+	cp.Employees = append([]domain.EmployeeRecord(nil), s.Employees...)
+	return &cp, nil
+}
+
+func (m *memRepo) ListSubmissions(_ context.Context) ([]domain.Submission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []domain.Submission
+	for _, s := range m.submissions {
+		list = append(list, *s)
+	}
+	return list, nil
+}
+
+func (m *memRepo) ListSubmissionsByEIN(_ context.Context, ein string, excludeID int64) ([]domain.Submission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []domain.Submission
+	for _, s := range m.submissions {
+		if s.ID != excludeID && s.Employer.EIN == ein {
+			cp := *s
+			cp.Employees = nil
+			list = append(list, cp)
+		}
+	}
+	slices.SortFunc(list, func(a, b domain.Submission) int { return int(b.ID - a.ID) })
+	return list, nil
+}
+
+func (m *memRepo) SearchSubmissions(ctx context.Context, query string) ([]domain.Submission, error) {
+	all, err := m.ListSubmissions(ctx)
+	if err != nil || query == "" {
+		return all, err
+	}
+	var list []domain.Submission
+	for _, s := range all {
+		if strings.Contains(strings.ToUpper(s.Employer.Name), strings.ToUpper(query)) || strings.Contains(s.Employer.EIN, strings.ReplaceAll(query, "-", "")) {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func (m *memRepo) ListSubmissionsPage(ctx context.Context, query string, limit, offset int) ([]domain.Submission, int, error) {
+	list, err := m.SearchSubmissions(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	slices.SortFunc(list, func(a, b domain.Submission) int { return int(b.ID - a.ID) })
+	total := len(list)
+	list = list[min(offset, total):min(offset+limit, total)]
+	return list, total, nil
+}
+
+func (m *memRepo) UpdateSubmission(_ context.Context, s *domain.Submission) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.submissions[s.ID]
+	if !ok {
+		return fmt.Errorf("submission %d not found", s.ID)
+	}
+	cp := *s
+	cp.Employees = existing.Employees
+	m.submissions[s.ID] = &cp
+	return nil
+}
+
+func (m *memRepo) IncrementGenerateCount(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.submissions[id]
+	if !ok {
+		return fmt.Errorf("submission %d not found", id)
+	}
+	s.GenerateCount++
+	return nil
+}
+
+func (m *memRepo) MarkSubmitted(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.submissions[id]
+	if !ok {
+		return fmt.Errorf("submission %d not found", id)
+	}
+	now := time.Now()
+	s.SubmittedAt = &now
+	return nil
+}
+
+func (m *memRepo) DeleteSubmission(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.submissions, id)
+	return nil
+}
+
+func (m *memRepo) AddEmployee(_ context.Context, submissionID int64, e *domain.EmployeeRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.submissions[submissionID]
+	if !ok {
+		return fmt.Errorf("submission %d not found", submissionID)
+	}
+	m.nextEmpID++
+	e.ID = m.nextEmpID
+	e.SubmissionID = submissionID
+	s.Employees = append(s.Employees, *e)
+	return nil
+}
+
+func (m *memRepo) AddEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error {
+	for _, e := range employees {
+		if m.failSSN != "" && e.SSN == m.failSSN {
+			return fmt.Errorf("forced failure on SSN %s", e.SSN)
+		}
+	}
+	for i := range employees {
+		if err := m.AddEmployee(ctx, submissionID, &employees[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memRepo) UpsertEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error {
+	for _, e := range employees {
+		if m.failSSN != "" && e.SSN == m.failSSN {
+			return fmt.Errorf("forced failure on SSN %s", e.SSN)
+		}
+	}
+	for i := range employees {
+		var err error
+		if employees[i].ID != 0 {
+			err = m.UpdateEmployee(ctx, &employees[i])
+		} else {
+			err = m.AddEmployee(ctx, submissionID, &employees[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memRepo) GetEmployee(_ context.Context, id int64) (*domain.EmployeeRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.submissions {
+		for i := range s.Employees {
+			if s.Employees[i].ID == id {
+				e := s.Employees[i]
+				return &e, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("employee %d not found", id)
+}
+
+func (m *memRepo) UpdateEmployee(_ context.Context, e *domain.EmployeeRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.submissions[e.SubmissionID]
+	if !ok {
+		return fmt.Errorf("submission %d not found", e.SubmissionID)
+	}
+	for i := range s.Employees {
+		if s.Employees[i].ID == e.ID {
+			s.Employees[i] = *e
+			return nil
+		}
+	}
+	return fmt.Errorf("employee %d not found", e.ID)
+}
+
+func (m *memRepo) DeleteEmployee(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.submissions {
+		for i := range s.Employees {
+			if s.Employees[i].ID == id {
+				s.Employees = append(s.Employees[:i], s.Employees[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memRepo) DeleteEmployeesBySubmission(_ context.Context, submissionID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.submissions[submissionID]; ok {
+		s.Employees = nil
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// newServer returns a test server over a fresh memRepo seeded with one
+// TY2024 submission (ID 1).
+func newServer(t *testing.T) (*httptest.Server, *memRepo) {
+	t.Helper()
+	repo := newMemRepo()
+	if err := repo.CreateSubmission(context.Background(), &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER", ContactEmail: "jane@example.com"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(handlers.New(repo, efw2c.MustNew(2024)).Routes())
+	t.Cleanup(srv.Close)
+	return srv, repo
+}
+
+// slowGen is a generator whose Generate and GenerateResult block until
+// their context is done.
+type slowGen struct{ *efw2c.Generator }
+
+func (slowGen) Generate(ctx context.Context, _ *domain.Submission, _ io.Writer) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+func (g slowGen) GenerateResult(ctx context.Context, s *domain.Submission, w io.Writer) (domain.GenerateResult, error) {
+	return domain.GenerateResult{}, g.Generate(ctx, s, w)
+}
+
+// do sends a form-encoded request and returns the status and body.
+func do(t *testing.T, method, u string, form url.Values) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+// TestAddEmployee_ShowsSSTaxWarning posts an employee whose corrected SS tax
+// is far from 6.2% of SS wages and expects an inline warning badge.
+func TestAddEmployee_ShowsSSTaxWarning(t *testing.T) {
+	srv, _ := newServer(t)
+	form := url.Values{
+		"ssn":           {"987-65-4321"},
+		"first_name":    {"JOHN"},
+		"last_name":     {"SMITH"},
+		"orig_ss_wages": {"50000.00"},
+		"corr_ss_wages": {"51000.00"},
+		"orig_ss_tax":   {"3100.00"},
+		"corr_ss_tax":   {"1000.00"}, // expected ~3162.00
+	}
+	status, body := do(t, http.MethodPost, srv.URL+"/submissions/1/employees", form)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `data-warning="`+efw2c.CodeSSTaxRate+`"`) {
+		t.Errorf("response fragment missing SS tax warning badge:\n%s", body)
+	}
+	if !strings.Contains(body, "not 6.2%") {
+		t.Errorf("response fragment missing SS tax warning message")
+	}
+}
+
+// TestAddEmployee_ShortSSN expects a 7-digit SSN to be refused with a 400
+// instead of stored, and a short EIN likewise on a new submission.
+func TestAddEmployee_ShortSSN(t *testing.T) {
+	srv, repo := newServer(t)
+	form := url.Values{"ssn": {"123-4567"}, "first_name": {"JOHN"}, "last_name": {"SMITH"}}
+	status, body := do(t, http.MethodPost, srv.URL+"/submissions/1/employees", form)
+	if status != http.StatusBadRequest || !strings.Contains(body, "SSN must be 9 digits") {
+		t.Errorf("7-digit SSN: want 400 naming the SSN, got %d: %s", status, body)
+	}
+	s, _ := repo.GetSubmission(context.Background(), 1)
+	if len(s.Employees) != 0 {
+		t.Errorf("7-digit SSN must not be stored; have %d employees", len(s.Employees))
+	}
+
+	status, body = do(t, http.MethodPost, srv.URL+"/submissions", url.Values{"ein": {"12-345"}, "employer_name": {"ACME"}})
+	if status != http.StatusBadRequest || !strings.Contains(body, "EIN must be 9 digits") {
+		t.Errorf("short EIN: want 400 naming the EIN, got %d: %s", status, body)
+	}
+}
+
+// TestViewSubmission_WarningsBanner expects the detail page to count the
+// reconciliation warnings above the employee cards.
+func TestViewSubmission_WarningsBanner(t *testing.T) {
+	srv, repo := newServer(t)
+	e := &domain.EmployeeRecord{SSN: "987654321", FirstName: "JOHN", LastName: "SMITH"}
+	e.Amounts.CorrectSocialSecurityWages = 20000000 // over every year's wage base
+	e.Amounts.CorrectSocialSecurityTax = 100000
+	if err := repo.AddEmployee(context.Background(), 1, e); err != nil {
+		t.Fatal(err)
+	}
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `id="warnings-banner"`) || !strings.Contains(body, "advisories — SSA may reject") {
+		t.Errorf("detail page: want warnings banner:\n%s", body)
+	}
+	if !strings.Contains(body, `data-warning="`+efw2c.CodeSSWageBase+`"`) {
+		t.Errorf("detail page: missing wage base warning badge")
+	}
+}
+
+// TestViewSubmission_TaxYearHeader verifies the detail header names the
+// submission's own tax year and the SSA publication it follows.
+func TestViewSubmission_TaxYearHeader(t *testing.T) {
+	srv, _ := newServer(t)
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `Tax Year <span class="font-mono">2024</span>`) {
+		t.Errorf("detail page: want Tax Year 2024 in the header:\n%s", body)
+	}
+	if !strings.Contains(body, "SSA Pub. 42-014") || !strings.Contains(body, "24efw2c.pdf") {
+		t.Errorf("detail page: want the TY2024 link to SSA Pub. 42-014")
+	}
+}
+
+// TestUpdateEmployee_ShowsSSTaxWarning verifies the edit flow re-renders the
+// card with the same warning.
+func TestUpdateEmployee_ShowsSSTaxWarning(t *testing.T) {
+	srv, repo := newServer(t)
+	e := &domain.EmployeeRecord{SSN: "987654321", FirstName: "JOHN", LastName: "SMITH"}
+	if err := repo.AddEmployee(context.Background(), 1, e); err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{
+		"ssn":           {"987654321"},
+		"first_name":    {"JOHN"},
+		"last_name":     {"SMITH"},
+		"orig_ss_wages": {"50000.00"},
+		"corr_ss_wages": {"51000.00"},
+		"orig_ss_tax":   {"3100.00"},
+		"corr_ss_tax":   {"9999.00"},
+	}
+	status, body := do(t, http.MethodPut, fmt.Sprintf("%s/employees/%d", srv.URL, e.ID), form)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `data-warning="`+efw2c.CodeSSTaxRate+`"`) {
+		t.Errorf("card missing SS tax warning badge:\n%s", body)
+	}
+}
+
+// TestSpecRuler lists every field with its positions, length and type.
+func TestSpecRuler(t *testing.T) {
+	srv, _ := newServer(t)
+	status, body := do(t, http.MethodGet, srv.URL+"/spec/2024/ruler.txt", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	var found bool
+	for _, line := range strings.Split(body, "\n") {
+		if strings.Join(strings.Fields(line), " ") == "OrigWagesTipsOther 244 254 11 Money11" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("ruler missing line 'OrigWagesTipsOther 244 254 11 Money11':\n%s", body)
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/spec/1999/ruler.txt", nil); status != http.StatusNotFound {
+		t.Errorf("unsupported year: want 404, got %d", status)
+	}
+}
+
+// TestSpecDiff lists the TY2024 Code II addition and rejects an unknown year.
+func TestSpecDiff(t *testing.T) {
+	srv, _ := newServer(t)
+	status, body := do(t, http.MethodGet, srv.URL+"/spec/diff?from=2023&to=2024", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	var found bool
+	for _, line := range strings.Split(body, "\n") {
+		if strings.Join(strings.Fields(line), " ") == "RCO OrigMedicaidWaiver added - 277-287" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("diff missing the RCO Code II addition:\n%s", body)
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/spec/diff?from=1999&to=2024", nil); status != http.StatusNotFound {
+		t.Errorf("unknown year: want 404, got %d", status)
+	}
+}
+
+// TestGenerateFile_Timeout expects a 504, not a hung or half-written
+// response, when generation runs past the configured timeout.
+func TestGenerateFile_Timeout(t *testing.T) {
+	repo := newMemRepo()
+	ctx := context.Background()
+	s := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER", ContactEmail: "jane@example.com"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+	}
+	if err := repo.CreateSubmission(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddEmployee(ctx, s.ID, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	h := handlers.New(repo, slowGen{efw2c.MustNew(2024)}, handlers.WithTimeout(50*time.Millisecond))
+	srv := httptest.NewServer(h.Routes())
+	t.Cleanup(srv.Close)
+
+	start := time.Now()
+	status, body := do(t, http.MethodGet, fmt.Sprintf("%s/submissions/%d/generate", srv.URL, s.ID), nil)
+	if status != http.StatusGatewayTimeout {
+		t.Fatalf("status: want 504, got %d: %s", status, body)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("timeout took %v; want roughly 50ms", elapsed)
+	}
+}
+
+// TestSeedOriginals copies a prior submission's corrected amounts into the
+// originals of the matching employee and persists them.
+func TestSeedOriginals(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	prior := &domain.Submission{Employer: domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"}}
+	if err := repo.CreateSubmission(ctx, prior); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddEmployee(ctx, prior.ID, &domain.EmployeeRecord{
+		SSN: "987654321", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	e := &domain.EmployeeRecord{SSN: "987654321", FirstName: "JOHN", LastName: "SMITH"}
+	if err := repo.AddEmployee(ctx, 1, e); err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := do(t, http.MethodPost, srv.URL+"/submissions/1/seed-originals",
+		url.Values{"prior_id": {fmt.Sprint(prior.ID)}})
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	got, err := repo.GetEmployee(ctx, e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Amounts.OriginalWagesTipsOther != 5100000 {
+		t.Errorf("OriginalWagesTipsOther: want 5100000, got %d", got.Amounts.OriginalWagesTipsOther)
+	}
+}
+
+// TestViewSubmission_RecordsPanel expects the RCO line to count exactly the
+// employees with allocated tips.
+func TestViewSubmission_RecordsPanel(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	for i, tips := range []int64{0, 25000, 0, 10000} {
+		if err := repo.AddEmployee(ctx, 1, &domain.EmployeeRecord{
+			SSN: fmt.Sprintf("98765432%d", i), FirstName: "JOHN", LastName: "SMITH",
+			Amounts: domain.MonetaryAmounts{CorrectAllocatedTips: tips},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, "RCO: 2</span>") || !strings.Contains(body, "2 employees have allocated tips") {
+		t.Errorf("records panel: want RCO count 2 for allocated tips:\n%s", body)
+	}
+	if !strings.Contains(body, "RCW: 4</span>") {
+		t.Errorf("records panel: want RCW count 4")
+	}
+	if !strings.Contains(body, `id="totals-panel"`) || !strings.Contains(body, `data-box="7"`) {
+		t.Errorf("totals panel: want Box 1-7 rows")
+	}
+}
+
+// TestViewSubmission_SortByName expects ?sort=name to list employees by
+// last then first name, and no sort param to keep id order.
+func TestViewSubmission_SortByName(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	for i, name := range [][2]string{{"WALTER", "YOUNG"}, {"ALICE", "BAKER"}, {"ZOE", "BAKER"}} {
+		if err := repo.AddEmployee(ctx, 1, &domain.EmployeeRecord{
+			SSN: fmt.Sprintf("98765432%d", i), FirstName: name[0], LastName: name[1],
+			Amounts: domain.MonetaryAmounts{CorrectWagesTipsOther: 100},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	order := func(body string, names ...string) bool {
+		last := -1
+		for _, n := range names {
+			i := strings.Index(body, n)
+			if i <= last {
+				return false
+			}
+			last = i
+		}
+		return true
+	}
+
+	_, body := do(t, http.MethodGet, srv.URL+"/submissions/1?sort=name", nil)
+	if !order(body, "BAKER, ALICE", "BAKER, ZOE", "YOUNG, WALTER") {
+		t.Errorf("sort=name: want BAKER, ALICE / BAKER, ZOE / YOUNG, WALTER in order")
+	}
+	_, body = do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if !order(body, "YOUNG, WALTER", "BAKER, ALICE", "BAKER, ZOE") {
+		t.Errorf("default: want id order")
+	}
+}
+
+// TestGenerateFile_IncrementsCount expects every successful generate request
+// to bump the submission's download count, and the detail page to show it.
+func TestGenerateFile_IncrementsCount(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	if err := repo.AddEmployee(ctx, 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	for want := 1; want <= 3; want++ {
+		if status, body := do(t, http.MethodGet, srv.URL+"/submissions/1/generate", nil); status != http.StatusOK {
+			t.Fatalf("generate #%d: want 200, got %d: %s", want, status, body)
+		}
+		s, err := repo.GetSubmission(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.GenerateCount != want {
+			t.Errorf("GenerateCount after %d request(s): got %d", want, s.GenerateCount)
+		}
+	}
+	if _, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil); !strings.Contains(body, "downloaded 3 times") {
+		t.Errorf("detail page missing 'downloaded 3 times'")
+	}
+}
+
+// TestGenerateFile_Disposition expects ?disposition=inline to serve the file
+// for in-browser preview without counting it as a download, and attachment
+// otherwise.
+func TestGenerateFile_Disposition(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	if err := repo.AddEmployee(ctx, 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	for query, want := range map[string]string{
+		"":                    "attachment; ",
+		"?disposition=inline": "inline; ",
+	} {
+		resp, err := http.Get(srv.URL + "/submissions/1/generate" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%q: want 200, got %d", query, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Content-Disposition"); !strings.HasPrefix(got, want) {
+			t.Errorf("%q: Content-Disposition: want prefix %q, got %q", query, want, got)
+		}
+		if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+			t.Errorf("%q: Content-Type: want text/plain, got %q", query, got)
+		}
+	}
+	if s, _ := repo.GetSubmission(ctx, 1); s.GenerateCount != 1 {
+		t.Errorf("GenerateCount: want 1 (preview not counted), got %d", s.GenerateCount)
+	}
+}
+
+// TestEmployeePDF expects a single employee's PDF, named after the
+// employee, and a 500 for an unknown employee.
+func TestEmployeePDF(t *testing.T) {
+	srv, repo := newServer(t)
+	e := &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "O'Neil",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}
+	if err := repo.AddEmployee(context.Background(), 1, e); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(srv.URL + fmt.Sprintf("/employees/%d/pdf", e.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", resp.StatusCode, body)
+	}
+	if !bytes.HasPrefix(body, []byte("%PDF")) {
+		t.Errorf("body: want a PDF, got %q", body[:min(len(body), 20)])
+	}
+	if got, want := resp.Header.Get("Content-Disposition"), `filename="W2C_123456789_ONEIL.pdf"`; !strings.Contains(got, want) {
+		t.Errorf("Content-Disposition: want %s, got %q", want, got)
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/employees/999/pdf", nil); status != http.StatusInternalServerError {
+		t.Errorf("unknown employee: want 500, got %d", status)
+	}
+}
+
+// TestUpdateSubmission_Resubmission expects the resubmission fields to be
+// saved, and the WFID dropped once the file is marked original again.
+func TestUpdateSubmission_Resubmission(t *testing.T) {
+	srv, repo := newServer(t)
+	form := url.Values{
+		"bso_uid": {"TESTUSER"}, "ein": {"123456789"}, "employer_name": {"ACME CORP"}, "tax_year": {"2024"},
+		"resub_indicator": {"1"}, "resub_wfid": {" abc123 "},
+	}
+	if status, body := do(t, http.MethodPut, srv.URL+"/submissions/1", form); status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	s, _ := repo.GetSubmission(context.Background(), 1)
+	if s.Submitter.ResubIndicator != "1" || s.Submitter.ResubWFID != "ABC123" {
+		t.Errorf("resubmission: got indicator %q WFID %q, want 1 and ABC123", s.Submitter.ResubIndicator, s.Submitter.ResubWFID)
+	}
+
+	form.Set("resub_indicator", "0")
+	if status, body := do(t, http.MethodPut, srv.URL+"/submissions/1", form); status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	s, _ = repo.GetSubmission(context.Background(), 1)
+	if s.Submitter.ResubIndicator != "0" || s.Submitter.ResubWFID != "" {
+		t.Errorf("original: got indicator %q WFID %q, want 0 and blank", s.Submitter.ResubIndicator, s.Submitter.ResubWFID)
+	}
+}
+
+// TestDebug expects the record dump to show every record with its fields,
+// and a 404 for an unknown submission.
+func TestDebug(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1/debug", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	for _, id := range []string{"RCA", "RCE", "RCW", "RCT", "RCF"} {
+		if !strings.Contains(body, `data-record="`+id+`"`) {
+			t.Errorf("dump missing %s record", id)
+		}
+	}
+	if !strings.Contains(body, `data-field="EmployerEIN"`) || !strings.Contains(body, `data-value="123456789"`) {
+		t.Error("dump missing the RCE EmployerEIN field and value")
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/submissions/99/debug", nil); status != http.StatusNotFound {
+		t.Errorf("unknown submission: want 404, got %d", status)
+	}
+}
+
+// TestPreview expects the file inline as whole 1024-byte records, and one
+// ruler line per record with ?ruler=1.
+func TestPreview(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(srv.URL + "/submissions/1/preview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type: want text/plain, got %q", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != "" {
+		t.Errorf("Content-Disposition: want none, got %q", cd)
+	}
+	if len(body) == 0 || len(body)%1024 != 0 {
+		t.Errorf("body length %d is not a multiple of 1024", len(body))
+	}
+
+	status, ruled := do(t, http.MethodGet, srv.URL+"/submissions/1/preview?ruler=1", nil)
+	if status != http.StatusOK {
+		t.Fatalf("ruler: want 200, got %d: %s", status, ruled)
+	}
+	lines := strings.Split(strings.TrimSuffix(ruled, "\n"), "\n")
+	if len(lines) != 2*len(body)/1024 {
+		t.Fatalf("ruler: want %d lines, got %d", 2*len(body)/1024, len(lines))
+	}
+	if r := lines[0]; len(r) != 1024 || r[9] != '1' || r[4] != '+' {
+		t.Errorf("ruler line: got %q", r[:20])
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/submissions/99/preview", nil); status != http.StatusNotFound {
+		t.Errorf("unknown submission: want 404, got %d", status)
+	}
+}
+
+// TestIndex_Search expects ?q= to filter the list and keep the query in
+// the search box.
+func TestIndex_Search(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.CreateSubmission(context.Background(), &domain.Submission{
+		Employer: domain.EmployerRecord{EIN: "987654321", Name: "GLOBEX INC", TaxYear: "2024"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	acme, globex := `hx-get="/submissions/1"`, `hx-get="/submissions/2"`
+	_, body := do(t, http.MethodGet, srv.URL+"/", nil)
+	if !strings.Contains(body, acme) || !strings.Contains(body, globex) {
+		t.Error("no query: want both submissions listed")
+	}
+	_, body = do(t, http.MethodGet, srv.URL+"/?q=glob", nil)
+	if strings.Contains(body, acme) || !strings.Contains(body, globex) {
+		t.Error("q=glob: want only GLOBEX INC")
+	}
+	if !strings.Contains(body, `value="glob"`) {
+		t.Error("q=glob: query not kept in the search box")
+	}
+	if _, body = do(t, http.MethodGet, srv.URL+"/?q=nobody", nil); !strings.Contains(body, "No submissions match") {
+		t.Error("q=nobody: want the no-match message")
+	}
+}
+
+// TestIndex_Pagination expects 25 submissions a page with prev/next links
+// that keep the search.
+func TestIndex_Pagination(t *testing.T) {
+	srv, repo := newServer(t)
+	for i := 2; i <= 30; i++ {
+		if err := repo.CreateSubmission(context.Background(), &domain.Submission{
+			Employer: domain.EmployerRecord{EIN: "123456789", Name: fmt.Sprintf("ACME %02d", i), TaxYear: "2024"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, body := do(t, http.MethodGet, srv.URL+"/?q=acme", nil)
+	if n := strings.Count(body, `hx-get="/submissions/`); n != 25 {
+		t.Errorf("page 1: want 25 submissions, got %d", n)
+	}
+	if !strings.Contains(body, `href="/?page=2&amp;q=acme"`) || strings.Contains(body, `rel="prev"`) {
+		t.Error("page 1: want a next link keeping q and no prev link")
+	}
+	_, body = do(t, http.MethodGet, srv.URL+"/?q=acme&page=2", nil)
+	if n := strings.Count(body, `hx-get="/submissions/`); n != 5 {
+		t.Errorf("page 2: want 5 submissions, got %d", n)
+	}
+	if !strings.Contains(body, `href="/?q=acme"`) || strings.Contains(body, `rel="next"`) {
+		t.Error("page 2: want a prev link to page 1 and no next link")
+	}
+}
+
+// TestDuplicate expects a copy with its own IDs and the same employees,
+// reached through HX-Redirect.
+func TestDuplicate(t *testing.T) {
+	srv, repo := newServer(t)
+	yes := true
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000},
+		Box13:   domain.Box13Flags{CorrectRetirementPlan: &yes}}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/submissions/1/duplicate", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status: want 201, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("HX-Redirect"); got != "/submissions/2" {
+		t.Errorf("HX-Redirect: want /submissions/2, got %q", got)
+	}
+	c, err := repo.GetSubmission(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Employer.EIN != "123456789" || len(c.Employees) != 1 || c.Employees[0].SSN != "123456789" {
+		t.Fatalf("copy: got EIN %q with %d employees", c.Employer.EIN, len(c.Employees))
+	}
+	if c.Employees[0].SubmissionID != 2 || c.Employees[0].ID == 1 {
+		t.Errorf("copied employee: got ID %d in submission %d", c.Employees[0].ID, c.Employees[0].SubmissionID)
+	}
+
+	if status, _ := do(t, http.MethodPost, srv.URL+"/submissions/99/duplicate", nil); status != http.StatusNotFound {
+		t.Errorf("unknown submission: want 404, got %d", status)
+	}
+
+	// A failed employee save leaves no empty copy behind.
+	repo.failSSN = "123456789"
+	if status, _ := do(t, http.MethodPost, srv.URL+"/submissions/1/duplicate", nil); status != http.StatusInternalServerError {
+		t.Errorf("failed save: want 500, got %d", status)
+	}
+	if list, _ := repo.ListSubmissions(context.Background()); len(list) != 2 {
+		t.Errorf("failed save: want 2 submissions, got %d", len(list))
+	}
+}
+
+// TestMarkSubmitted expects mark-submitted to turn the DRAFT badge into
+// SUBMITTED, and later downloads to carry the re-download header.
+func TestMarkSubmitted(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}}); err != nil {
+		t.Fatal(err)
+	}
+	generated := func() string {
+		resp, err := http.Get(srv.URL + "/submissions/1/generate")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("generate: want 200, got %d", resp.StatusCode)
+		}
+		return resp.Header.Get("X-W2C-Submitted")
+	}
+	if _, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil); !strings.Contains(body, `data-status="draft"`) {
+		t.Error("new submission is not shown as a draft")
+	}
+	if got := generated(); got != "" {
+		t.Errorf("draft download: X-W2C-Submitted = %q, want none", got)
+	}
+
+	status, body := do(t, http.MethodPost, srv.URL+"/submissions/1/mark-submitted", nil)
+	if status != http.StatusOK {
+		t.Fatalf("mark-submitted: want 200, got %d: %s", status, body)
+	}
+	today := time.Now().Format("2006-01-02")
+	if !strings.Contains(body, `data-status="submitted"`) || !strings.Contains(body, "SUBMITTED "+today) {
+		t.Errorf("header after mark-submitted missing SUBMITTED %s badge", today)
+	}
+	if got := generated(); !strings.Contains(got, today) {
+		t.Errorf("filed download: X-W2C-Submitted = %q, want the filing date", got)
+	}
+
+	if status, _ := do(t, http.MethodPost, srv.URL+"/submissions/99/mark-submitted", nil); status != http.StatusNotFound {
+		t.Errorf("unknown submission: want 404, got %d", status)
+	}
+}
+
+// TestGenerateFile_InvalidSSN expects a 400 listing the offending SSN with
+// its machine-readable code instead of a file.
+func TestGenerateFile_InvalidSSN(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "078-05-1120", FirstName: "JOHN", LastName: "SMITH"}); err != nil {
+		t.Fatal(err)
+	}
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1/generate", nil)
+	if status != http.StatusBadRequest {
+		t.Fatalf("status: want 400, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, "["+efw2c.CodeInvalidSSN+"] employee 1: SSN") {
+		t.Errorf("body missing invalid SSN entry:\n%s", body)
+	}
+}
+
+// TestAudit renders the checklist for a submission with a bad SSN and
+// expects it flagged as an error and the generate button withheld.
+func TestAudit(t *testing.T) {
+	srv, repo := newServer(t)
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{SSN: "078-05-1120", FirstName: "JOHN", LastName: "SMITH"}); err != nil {
+		t.Fatal(err)
+	}
+	status, body := do(t, http.MethodGet, srv.URL+"/submissions/1/audit", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	for _, want := range []string{
+		`data-clean="false"`,
+		`data-severity="error" data-code="` + efw2c.CodeInvalidSSN + `"`,
+		`data-severity="error" data-code="` + efw2c.CodeNoCorrection + `"`,
+		"#1 SMITH, JOHN · SSN",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("audit page missing %q", want)
+		}
+	}
+	if strings.Contains(body, "/submissions/1/generate") {
+		t.Error("audit page offers generate despite errors")
+	}
+
+	if status, _ := do(t, http.MethodGet, srv.URL+"/submissions/99/audit", nil); status != http.StatusNotFound {
+		t.Errorf("unknown submission: want 404, got %d", status)
+	}
+}
+
+// TestDetail_NoCorrection expects the employee card to flag an employee
+// whose original and correct values all match, and not one that corrects
+// something.
+func TestDetail_NoCorrection(t *testing.T) {
+	srv, repo := newServer(t)
+	e := &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5000000}}
+	if err := repo.AddEmployee(context.Background(), 1, e); err != nil {
+		t.Fatal(err)
+	}
+	_, body := do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if !strings.Contains(body, `data-error="no_correction"`) {
+		t.Error("detail page does not flag the unchanged employee")
+	}
+
+	e.Amounts.CorrectWagesTipsOther = 5100000
+	if err := repo.UpdateEmployee(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	_, body = do(t, http.MethodGet, srv.URL+"/submissions/1", nil)
+	if strings.Contains(body, `data-error="no_correction"`) {
+		t.Error("detail page flags an employee that corrects Box 1")
+	}
+}
+
+// TestSelfTest expects the minimal submission to round-trip through
+// generate, parse and regenerate on a healthy build.
+func TestSelfTest(t *testing.T) {
+	srv, _ := newServer(t)
+	code, body := do(t, "GET", srv.URL+"/selftest", nil)
+	if code != 200 {
+		t.Fatalf("GET /selftest: want 200, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "selftest ok: TY2024") {
+		t.Errorf("GET /selftest: want summary for TY2024, got %q", body)
+	}
+}
+
+// TestImportRCW pastes two RCW records, trailing blanks trimmed as a text
+// box might, and expects two employees with the decoded amounts.
+func TestImportRCW(t *testing.T) {
+	srv, repo := newServer(t)
+	src := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER"},
+		Employer:  domain.EmployerRecord{EIN: "123456789", Name: "ACME CORP", TaxYear: "2024"},
+		Employees: []domain.EmployeeRecord{
+			{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH", Amounts: domain.MonetaryAmounts{
+				OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}},
+			{SSN: "234567890", FirstName: "MARY", LastName: "JONES", Amounts: domain.MonetaryAmounts{
+				OriginalFederalIncomeTax: 400000, CorrectFederalIncomeTax: 450000}},
+		},
+	}
+	var file bytes.Buffer
+	if err := efw2c.MustNew(2024).Generate(context.Background(), src, &file); err != nil {
+		t.Fatal(err)
+	}
+	var paste strings.Builder
+	for n := 2; n <= 3; n++ { // RCA RCE [RCW RCW] RCT RCF
+		rec := file.String()[n*1024 : (n+1)*1024]
+		paste.WriteString(strings.TrimRight(rec, " ") + "\r\n")
+	}
+
+	status, body := do(t, http.MethodPost, srv.URL+"/submissions/1/employees/import-rcw", url.Values{"records": {paste.String()}})
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	s, _ := repo.GetSubmission(context.Background(), 1)
+	if len(s.Employees) != 2 {
+		t.Fatalf("want 2 employees, got %d", len(s.Employees))
+	}
+	a, b := s.Employees[0], s.Employees[1]
+	if a.SSN != "123456789" || a.LastName != "SMITH" || a.Amounts.OriginalWagesTipsOther != 5000000 || a.Amounts.CorrectWagesTipsOther != 5100000 {
+		t.Errorf("employee 0: got %s %s %+v", a.SSN, a.LastName, a.Amounts)
+	}
+	if b.SSN != "234567890" || b.Amounts.OriginalFederalIncomeTax != 400000 || b.Amounts.CorrectFederalIncomeTax != 450000 {
+		t.Errorf("employee 1: got %s %+v", b.SSN, b.Amounts)
+	}
+	if !strings.Contains(body, "JONES") {
+		t.Errorf("response: want refreshed employee list")
+	}
+
+	if status, _ := do(t, http.MethodPost, srv.URL+"/submissions/1/employees/import-rcw", url.Values{"records": {"RCW too short"}}); status != http.StatusBadRequest {
+		t.Errorf("malformed paste: want 400, got %d", status)
+	}
+}
+
+// uploadCSV posts csv as the "file" field of a multipart form to u.
+func uploadCSV(t *testing.T, u, csv string) (int, string) {
+	t.Helper()
+	return uploadFile(t, u, "employees.csv", csv)
+}
+
+// uploadFile posts content as the "file" field of a multipart form to u,
+// named name.
+func uploadFile(t *testing.T, u, name, content string) (int, string) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(fw, content)
+	mw.Close()
+	resp, err := http.Post(u, mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestImportCSV(t *testing.T) {
+	srv, repo := newServer(t)
+	csv := "ssn,first_name,last_name,orig_wages,corr_wages\n" +
+		"123-45-6789,JOHN,SMITH,50000.00,51000.00\n" +
+		"234567890,MARY,JONES,not-money,0\n" +
+		"\n" +
+		"345678901,\"LEE, JR\",PARK,100,200.5\n"
+
+	status, body := uploadCSV(t, srv.URL+"/submissions/1/employees/import", csv)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	s, _ := repo.GetSubmission(context.Background(), 1)
+	if len(s.Employees) != 2 {
+		t.Fatalf("want 2 employees imported, got %d", len(s.Employees))
+	}
+	if e := s.Employees[1]; e.FirstName != "LEE, JR" || e.Amounts.CorrectWagesTipsOther != 20050 {
+		t.Errorf("employee 1: got %q %+v", e.FirstName, e.Amounts)
+	}
+	for _, want := range []string{"Imported 2 employees", `data-line="3"`, "orig_wages", "SMITH"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("response: want %q", want)
+		}
+	}
+
+	if status, _ := uploadCSV(t, srv.URL+"/submissions/1/employees/import", "name\nJOHN\n"); status != http.StatusBadRequest {
+		t.Errorf("bad header: want 400, got %d", status)
+	}
+}
+
+// TestImportCSV_AllOrNothing forces the save to fail on the third row and
+// expects none of the rows to be applied.
+func TestImportCSV_AllOrNothing(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	if err := repo.AddEmployee(ctx, 1, &domain.EmployeeRecord{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH"}); err != nil {
+		t.Fatal(err)
+	}
+	repo.failSSN = "345678901"
+	csv := "ssn,first_name,last_name,orig_wages,corr_wages\n" +
+		"123456789,JOHN,SMITH,50000.00,51000.00\n" + // update
+		"234567890,MARY,JONES,400,450\n" + // insert
+		"345678901,LEE,PARK,100,200\n" // forced failure
+
+	if status, body := uploadCSV(t, srv.URL+"/submissions/1/employees/import", csv); status != http.StatusInternalServerError {
+		t.Fatalf("status: want 500, got %d: %s", status, body)
+	}
+	s, _ := repo.GetSubmission(ctx, 1)
+	if len(s.Employees) != 1 {
+		t.Fatalf("want the 1 stored employee only, got %d", len(s.Employees))
+	}
+	if a := s.Employees[0].Amounts; a.CorrectWagesTipsOther != 0 {
+		t.Errorf("stored employee was updated: %+v", a)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	srv, repo := newServer(t)
+	yes := true
+	if err := repo.AddEmployee(context.Background(), 1, &domain.EmployeeRecord{
+		SSN: "123456789", FirstName: "JOHN", LastName: "SMITH, JR", State: "IL",
+		Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100050},
+		Box13:   domain.Box13Flags{CorrectRetirementPlan: &yes},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(srv.URL + "/submissions/1/csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status: want 200, got %d", resp.StatusCode)
+	}
+	if got, want := resp.Header.Get("Content-Disposition"), `attachment; filename="W2C_123456789_employees.csv"`; got != want {
+		t.Errorf("Content-Disposition: want %s, got %s", want, got)
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("want header and one row, got %d rows", len(rows))
+	}
+	header, row := rows[0], rows[1]
+	if strings.Join(header[:6], ",") != "ssn,original_ssn,first_name,middle_name,last_name,suffix" {
+		t.Errorf("header starts %v", header[:6])
+	}
+	got := make(map[string]string, len(header))
+	for i, h := range header {
+		got[h] = row[i]
+	}
+	for col, want := range map[string]string{
+		"ssn": "123456789", "last_name": "SMITH, JR", "emp_state": "IL",
+		"orig_wages": "50000.00", "corr_wages": "51000.50", "orig_fed_tax": "0.00",
+		"corr_retirement_plan": "1", "orig_retirement_plan": "",
+	} {
+		if got[col] != want {
+			t.Errorf("%s: want %q, got %q", col, want, got[col])
+		}
+	}
+}
+
+// TestImportCSV_DedupReport verifies rows are classified against the
+// submission's existing employees by SSN.
+func TestImportCSV_DedupReport(t *testing.T) {
+	srv, repo := newServer(t)
+	ctx := context.Background()
+	for _, e := range []domain.EmployeeRecord{
+		{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH", Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}},
+		{SSN: "345678901", FirstName: "LEE", LastName: "PARK", Amounts: domain.MonetaryAmounts{OriginalWagesTipsOther: 10000, CorrectWagesTipsOther: 20000}},
+	} {
+		if err := repo.AddEmployee(ctx, 1, &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	csv := "ssn,first_name,last_name,orig_wages,corr_wages\n" +
+		"234-56-7890,MARY,JONES,400,450\n" + // new
+		"123-45-6789,JOHN,SMITH,50000,52000\n" + // existing, corrected wages changed
+		"345678901,LEE,PARK,100,200\n" // existing, identical
+
+	status, body := uploadCSV(t, srv.URL+"/submissions/1/employees/import", csv)
+	if status != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", status, body)
+	}
+	for line, outcome := range map[int]domain.ImportOutcome{2: domain.ImportInserted, 3: domain.ImportUpdated, 4: domain.ImportSkipped} {
+		if want := fmt.Sprintf(`data-line="%d" data-outcome="%s"`, line, outcome); !strings.Contains(body, want) {
+			t.Errorf("report: want %s", want)
+		}
+	}
+
+	s, _ := repo.GetSubmission(ctx, 1)
+	if len(s.Employees) != 3 {
+		t.Fatalf("want 3 employees after upsert, got %d", len(s.Employees))
+	}
+	if got := s.Employees[0].Amounts.CorrectWagesTipsOther; got != 5200000 {
+		t.Errorf("SMITH corrected wages: want 5200000, got %d", got)
+	}
+}
+
+// TestImportEFW2C uploads a generated file and expects a new resubmission
+// draft with the file's employer and employees.
+func TestImportEFW2C(t *testing.T) {
+	srv, repo := newServer(t)
+	src := &domain.Submission{
+		Submitter: domain.SubmitterInfo{BSOUID: "TESTUSER", ContactEmail: "jane@example.com"},
+		Employer:  domain.EmployerRecord{EIN: "987654321", Name: "WIDGET CO", TaxYear: "2024"},
+		Employees: []domain.EmployeeRecord{
+			{SSN: "123456789", FirstName: "JOHN", LastName: "SMITH", Amounts: domain.MonetaryAmounts{
+				OriginalWagesTipsOther: 5000000, CorrectWagesTipsOther: 5100000}},
+			{SSN: "234567890", FirstName: "MARY", LastName: "JONES", Amounts: domain.MonetaryAmounts{
+				OriginalFederalIncomeTax: 400000, CorrectFederalIncomeTax: 450000}},
+		},
+	}
+	var file bytes.Buffer
+	if err := efw2c.MustNew(2024).Generate(context.Background(), src, &file); err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := uploadFile(t, srv.URL+"/submissions/import-efw2c", "W2C_987654321.txt", file.String())
+	if status != http.StatusCreated {
+		t.Fatalf("status: want 201, got %d: %s", status, body)
+	}
+	s, err := repo.GetSubmission(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Employer.EIN != "987654321" {
+		t.Errorf("EIN: want 987654321, got %q", s.Employer.EIN)
+	}
+	if len(s.Employees) != 2 {
+		t.Errorf("want 2 employees, got %d", len(s.Employees))
+	}
+	if s.Submitter.ResubIndicator != "1" {
+		t.Errorf("ResubIndicator: want 1, got %q", s.Submitter.ResubIndicator)
+	}
+
+	for name, content := range map[string]string{
+		"malformed": "not an EFW2C file",
+		"empty":     "",
+		"blank":     strings.Repeat(" ", 2048),
+		"no RCW":    file.String()[:2*1024] + file.String()[4*1024:],
+	} {
+		if status, body := uploadFile(t, srv.URL+"/submissions/import-efw2c", "x.txt", content); status != http.StatusBadRequest {
+			t.Errorf("%s file: want 400, got %d: %s", name, status, body)
+		}
+	}
+
+	// A failed employee save leaves no submission behind.
+	repo.failSSN = "234567890"
+	if status, _ := uploadFile(t, srv.URL+"/submissions/import-efw2c", "W2C_987654321.txt", file.String()); status != http.StatusInternalServerError {
+		t.Errorf("failed save: want 500, got %d", status)
+	}
+	if list, _ := repo.ListSubmissions(context.Background()); len(list) != 2 {
+		t.Errorf("failed save: want 2 submissions, got %d", len(list))
+	}
+}
@@ -2,23 +2,48 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/csg33k/w2c-generator/internal/domain"
 )
 
+// ErrNotFound is wrapped by SubmissionRepository.GetSubmission when no
+// submission has the requested ID.
+var ErrNotFound = errors.New("not found")
+
 // SubmissionRepository defines persistence operations.
 type SubmissionRepository interface {
 	CreateSubmission(ctx context.Context, s *domain.Submission) error
 	GetSubmission(ctx context.Context, id int64) (*domain.Submission, error)
 	ListSubmissions(ctx context.Context) ([]domain.Submission, error)
+	// SearchSubmissions lists submissions whose employer name or EIN
+	// contains query; an empty query lists them all.
+	SearchSubmissions(ctx context.Context, query string) ([]domain.Submission, error)
+	// ListSubmissionsPage is SearchSubmissions limited to one page, with
+	// the total number of matches.
+	ListSubmissionsPage(ctx context.Context, query string, limit, offset int) ([]domain.Submission, int, error)
+	// ListSubmissionsByEIN lists the submissions for employer ein other
+	// than excludeID, newest first, without their employees.
+	ListSubmissionsByEIN(ctx context.Context, ein string, excludeID int64) ([]domain.Submission, error)
 	UpdateSubmission(ctx context.Context, s *domain.Submission) error
 	DeleteSubmission(ctx context.Context, id int64) error
+	// IncrementGenerateCount records one more EFW2C download for the submission.
+	IncrementGenerateCount(ctx context.Context, id int64) error
+	// MarkSubmitted records that the submission's file was filed with SSA.
+	MarkSubmitted(ctx context.Context, id int64) error
 
 	AddEmployee(ctx context.Context, submissionID int64, e *domain.EmployeeRecord) error
+	// AddEmployees inserts a batch all-or-nothing, setting each ID.
+	AddEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error
+	// UpsertEmployees updates the employees with an ID and inserts the
+	// rest, all-or-nothing, setting each new ID.
+	UpsertEmployees(ctx context.Context, submissionID int64, employees []domain.EmployeeRecord) error
 	GetEmployee(ctx context.Context, id int64) (*domain.EmployeeRecord, error)
 	UpdateEmployee(ctx context.Context, e *domain.EmployeeRecord) error
 	DeleteEmployee(ctx context.Context, id int64) error
+	// DeleteEmployeesBySubmission removes every employee of a submission.
+	DeleteEmployeesBySubmission(ctx context.Context, submissionID int64) error
 }
 
 // EFW2CGenerator defines the output generation port.
@@ -27,7 +52,43 @@ type EFW2CGenerator interface {
 	// The spec version is selected from s.Employer.TaxYear automatically.
 	Generate(ctx context.Context, s *domain.Submission, w io.Writer) error
 
+	// GenerateResult is Generate, also reporting the record, byte and RCW
+	// counts written, any truncated values, and the submission's warnings.
+	GenerateResult(ctx context.Context, s *domain.Submission, w io.Writer) (domain.GenerateResult, error)
+
+	// GenerateWithManifest is Generate, also returning an archival
+	// manifest (SHA-256, length, record counts) of the bytes written.
+	GenerateWithManifest(ctx context.Context, s *domain.Submission, w io.Writer) (*domain.Manifest, error)
+
 	// SupportedYears returns the tax years this generator can produce files for,
 	// in ascending order, each with its SSA publication URL.
 	SupportedYears() []domain.TaxYearInfo
+
+	// Year returns the default tax year: the one preselected for new
+	// submissions and given to those that leave it blank.
+	Year() int
+
+	// Check returns non-fatal reconciliation warnings (e.g. SS tax not 6.2%
+	// of SS wages) for every employee in s, tagged with the employee index.
+	Check(s *domain.Submission) []domain.Warning
+
+	// Validate returns every blocking identifier problem (malformed or
+	// never-issued EINs and SSNs) in s; empty means the file may be built.
+	Validate(s *domain.Submission) domain.ValidationErrors
+
+	// Parse reads an EFW2C file back into a submission, so that generating
+	// the result reproduces the file.
+	Parse(r io.Reader) (*domain.Submission, error)
+
+	// Audit lists every error, warning and informational note a
+	// pre-submission check such as AccuWage would raise for s.
+	Audit(s *domain.Submission) *domain.AuditReport
+
+	// Annotate generates s and splits each record into its layout fields,
+	// for locating a field SSA rejected.
+	Annotate(s *domain.Submission) ([]domain.RecordDump, error)
+
+	// ParseEmployees reads a block of RCW records (each optionally followed
+	// by RCO/RCS) laid out for year, for importing employees.
+	ParseEmployees(r io.Reader, year int) ([]domain.EmployeeRecord, error)
 }
@@ -2,9 +2,12 @@ package templates
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/csg33k/w2c-generator/internal/domain"
 )
 
 // centsToDisplay converts an integer cent value to a "$0.00"-style string.
@@ -53,6 +56,10 @@ func formatPhone(p string) string {
 	return p
 }
 
+// ssaPublication is the number of the SSA EFW2C specification. It is the
+// same for every tax year; only the per-year PDF (taxYearPubURL) changes.
+const ssaPublication = "42-014"
+
 // taxYearPubURL returns the SSA publication URL for a given 4-digit tax year
 // string (e.g. "2024" → "https://www.ssa.gov/employer/efw/24efw2c.pdf").
 // Returns an empty string for unrecognised input.
@@ -77,3 +84,79 @@ func boolPtrToFormVal(b *bool) string {
 	}
 	return "0"
 }
+
+// formatDelta renders a signed cent delta with thousands separators,
+// e.g. 1240000 → "+$12,400.00" and -550 → "-$5.50".
+func formatDelta(cents int64) string {
+	sign := "+"
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	whole := strconv.FormatInt(cents/100, 10)
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, b.String(), cents%100)
+}
+
+// formatDollars renders cents as "$12,400.00", with a leading "-" when
+// negative.
+func formatDollars(cents int64) string {
+	return strings.TrimPrefix(formatDelta(cents), "+")
+}
+
+// pluralize returns singular when n == 1 and plural otherwise.
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// pageURL links to page n of the submission list, keeping p's search.
+func pageURL(p domain.SubmissionPage, n int) string {
+	v := url.Values{}
+	if p.Query != "" {
+		v.Set("q", p.Query)
+	}
+	if n > 1 {
+		v.Set("page", strconv.Itoa(n))
+	}
+	if len(v) == 0 {
+		return "/"
+	}
+	return "/?" + v.Encode()
+}
+
+// boolAttr renders b as a "true"/"false" attribute value.
+func boolAttr(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// dumpValue shows a raw fixed-width value with every space as "·" so its
+// padding can be counted; an all-blank value is summarised by its width.
+func dumpValue(v string) string {
+	if strings.TrimSpace(v) == "" {
+		return fmt.Sprintf("(blank ×%d)", len(v))
+	}
+	return strings.ReplaceAll(v, " ", "·")
+}
+
+// auditLocation says where an audit finding applies: the employee (by
+// number and name) or the submission, then the field if there is one.
+func auditLocation(s *domain.Submission, f domain.AuditFinding) string {
+	where := "Submission"
+	if f.Employee >= 0 && f.Employee < len(s.Employees) {
+		e := s.Employees[f.Employee]
+		where = fmt.Sprintf("#%d %s, %s", f.Employee+1, e.LastName, e.FirstName)
+	}
+	if f.Field != "" {
+		where += " · " + f.Field
+	}
+	return where
+}
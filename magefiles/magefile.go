@@ -105,6 +105,13 @@ func Watch() error {
 	return nil
 }
 
+// Fixtures loads representative demo submissions into the database.
+// Run Dbup first on a fresh database.
+func Fixtures() error {
+	fmt.Println(">> Loading fixtures...")
+	return sh.Run("go", "run", "./cmd/fixtures")
+}
+
 // Tidy runs go mod tidy.
 func Tidy() error {
 	fmt.Println(">> go mod tidy...")